@@ -2,12 +2,18 @@ package cmd
 
 import (
 	"app-noti/common"
+	"app-noti/config"
+	"app-noti/internal/services"
+	"app-noti/pkg"
 	"app-noti/server"
 	logger2 "app-noti/services/logger"
 	postgres3 "app-noti/services/postgres"
+	redis3 "app-noti/services/redis"
 	"app-noti/services/rest_api_service"
+	storage2 "app-noti/services/storage"
 	"context"
 	"log"
+	"time"
 
 	"github.com/spf13/cobra"
 )
@@ -41,10 +47,62 @@ var restApiServiceCmd = &cobra.Command{
 			svr.AddLogger(logger)
 			svr.InitContext(ctx)
 			svr.InitService(postgres)
+			// without redis the menu cache stays in memory
+			if config.Config.Redis != nil && config.Config.Redis.Host != "" {
+				svr.InitService(redis3.NewMainRedis(common.PREFIX_MAIN_REDIS))
+			}
+			svr.InitService(storage2.NewMainStorage(common.PREFIX_MAIN_STORAGE))
 			svr.AddHandler(restHdl)
+			svr.AddJob(common.PREFIX_CRONJOB_MENU_PURGE, newMenuPurgeJob(ctx, svr))
+			svr.AddJob(common.PREFIX_CRONJOB_UPLOAD_GC, newUploadCleanupJob(ctx, svr))
 			if err := svr.Run(); err != nil {
 				logger.Error().Printf("Server is stopped by %v", err.Error())
 			}
 		}
 	},
 }
+
+// newMenuPurgeJob hard-deletes menu items that have stayed in the trash past the retention period.
+// The service is built on the first run, once postgres is up.
+func newMenuPurgeJob(ctx context.Context, sc server.ServerContext) *pkg.Job {
+	interval := time.Duration(config.Config.Menu.PurgeIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	var service *services.Service
+	return pkg.NewJob(common.PREFIX_CRONJOB_MENU_PURGE, interval, func() error {
+		if service == nil {
+			created, err := services.NewService(sc)
+			if err != nil {
+				return err
+			}
+			service = created
+		}
+
+		_, err := service.PurgeDeletedMenuItems(ctx)
+		return err
+	})
+}
+
+// newUploadCleanupJob deletes uploads that were never attached to a menu item
+func newUploadCleanupJob(ctx context.Context, sc server.ServerContext) *pkg.Job {
+	interval := time.Duration(config.Config.Storage.CleanupIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = time.Hour
+	}
+
+	var service *services.Service
+	return pkg.NewJob(common.PREFIX_CRONJOB_UPLOAD_GC, interval, func() error {
+		if service == nil {
+			created, err := services.NewService(sc)
+			if err != nil {
+				return err
+			}
+			service = created
+		}
+
+		_, err := service.PurgeOrphanUploads(ctx)
+		return err
+	})
+}
@@ -16,6 +16,16 @@ const (
 	PREFIX_MAIN_POSTGRES       = "MAIN_POSTGRES"
 	PREFIX_YOUPASS_DO_STORAGE  = "YOUPASS_DO_STORAGE"
 	PREFIX_CRONJOB_AUTO_CANCEL = "CRONJOB_AUTO_CANCEL"
+	PREFIX_CRONJOB_MENU_PURGE  = "CRONJOB_MENU_PURGE"
+	PREFIX_CRONJOB_UPLOAD_GC   = "CRONJOB_UPLOAD_GC"
+	PREFIX_MAIN_REDIS          = "MAIN_REDIS"
+	PREFIX_MAIN_STORAGE        = "MAIN_STORAGE"
+)
+
+const (
+	MENU_PHOTO_FOLDER   = "menu-items"
+	UPLOAD_FOLDER       = "menu-uploads"
+	LOCAL_STORAGE_ROUTE = "/uploads"
 )
 
 const ( //must NOT edit this
@@ -47,4 +57,24 @@ const (
 	POSTGRES_TABLE_NAME_MODIFIER_GROUPS           = "public.modifier_groups"
 	POSTGRES_TABLE_NAME_MODIFIER_OPTIONS          = "public.modifier_options"
 	POSTGRES_TABLE_NAME_MENU_ITEM_MODIFIER_GROUPS = "public.menu_item_modifier_groups"
+	POSTGRES_TABLE_NAME_STOCK_ALERTS              = "public.stock_alerts"
+	POSTGRES_TABLE_NAME_INGREDIENTS               = "public.ingredients"
+	POSTGRES_TABLE_NAME_RECIPE_LINES              = "public.recipe_lines"
+	POSTGRES_TABLE_NAME_STOCK_MOVEMENTS           = "public.stock_movements"
+	POSTGRES_TABLE_NAME_MENU_TRANSLATIONS         = "public.menu_translations"
+	POSTGRES_TABLE_NAME_COMBOS                    = "public.combos"
+	POSTGRES_TABLE_NAME_COMBO_SLOTS               = "public.combo_slots"
+	POSTGRES_TABLE_NAME_COMBO_SLOT_CHOICES        = "public.combo_slot_choices"
+	POSTGRES_TABLE_NAME_MODIFIER_CHILD_GROUPS     = "public.modifier_option_child_groups"
+	POSTGRES_TABLE_NAME_UPLOADS                   = "public.uploads"
+	POSTGRES_TABLE_NAME_RESERVATIONS              = "public.reservations"
+	POSTGRES_TABLE_NAME_RESERVATION_TABLES        = "public.reservation_tables"
+	POSTGRES_TABLE_NAME_WAITLIST_ENTRIES          = "public.waitlist_entries"
+	POSTGRES_TABLE_NAME_TABLE_GROUPS              = "public.table_groups"
+	POSTGRES_TABLE_NAME_TABLE_GROUP_MEMBERS       = "public.table_group_members"
+	POSTGRES_TABLE_NAME_TABLE_MOVES               = "public.table_moves"
+	POSTGRES_TABLE_NAME_FLOOR_PLANS               = "public.floor_plans"
+	POSTGRES_TABLE_NAME_FLOOR_PLAN_AREAS          = "public.floor_plan_areas"
+	POSTGRES_TABLE_NAME_TABLE_LAYOUTS             = "public.table_layouts"
+	POSTGRES_TABLE_NAME_ZONES                     = "public.zones"
 )
@@ -66,6 +66,101 @@ var (
 	ErrCodeInvalidTimeRange = errors.New("invalid_time_range")
 )
 
+var (
+	ErrOrderNotFound       = errors.New("order_not_found")
+	ErrInvalidOrderStatus  = errors.New("invalid_order_status")
+	ErrInsufficientStock   = errors.New("insufficient_stock")
+	ErrInvalidStockRequest = errors.New("invalid_stock_request")
+)
+
+var (
+	ErrUnsupportedLocale = errors.New("unsupported_locale")
+)
+
+var (
+	ErrInvalidQuoteItem         = errors.New("invalid_quote_item")
+	ErrItemUnavailable          = errors.New("item_unavailable")
+	ErrInvalidModifierSelection = errors.New("invalid_modifier_selection")
+	ErrInvalidComboSelection    = errors.New("invalid_combo_selection")
+)
+
+var (
+	ErrModifierCycle         = errors.New("modifier_cycle")
+	ErrModifierDepthExceeded = errors.New("modifier_depth_exceeded")
+)
+
+var (
+	ErrInvalidModifierOverride = errors.New("invalid_modifier_override")
+)
+
+var (
+	ErrInvalidReorder = errors.New("invalid_reorder")
+)
+
+var (
+	ErrInvalidBulkRequest = errors.New("invalid_bulk_request")
+	ErrBulkLimitExceeded  = errors.New("bulk_limit_exceeded")
+	ErrInvalidPrice       = errors.New("invalid_price")
+)
+
+var (
+	ErrCategoryHasItems      = errors.New("category_has_items")
+	ErrModifierGroupInUse    = errors.New("modifier_group_in_use")
+	ErrInvalidTargetCategory = errors.New("invalid_target_category")
+	ErrCategoryDeleted       = errors.New("category_deleted")
+)
+
+var (
+	ErrInvalidImage = errors.New("invalid_image")
+)
+
+var (
+	ErrUploadTooLarge    = errors.New("upload_too_large")
+	ErrUploadNotReceived = errors.New("upload_not_received")
+	ErrUploadMismatch    = errors.New("upload_mismatch")
+)
+
+var (
+	ErrReservationNotFound       = errors.New("reservation_not_found")
+	ErrInvalidReservationStatus  = errors.New("invalid_reservation_status")
+	ErrInvalidReservationTime    = errors.New("invalid_reservation_time")
+	ErrNoTableAvailable          = errors.New("no_table_available")
+	ErrOutsideOpeningHours       = errors.New("outside_opening_hours")
+	ErrTableAlreadyBooked        = errors.New("table_already_booked")
+	ErrInsufficientTableCapacity = errors.New("insufficient_table_capacity")
+	ErrTableLocationMismatch     = errors.New("table_location_mismatch")
+	ErrTableNotAvailable         = errors.New("table_not_available")
+)
+
+var (
+	ErrWaitlistEntryNotFound   = errors.New("waitlist_entry_not_found")
+	ErrInvalidWaitlistStatus   = errors.New("invalid_waitlist_status")
+	ErrWaitEstimateUnavailable = errors.New("wait_estimate_unavailable")
+)
+
+var (
+	ErrTableGroupNotFound = errors.New("table_group_not_found")
+	ErrTableAlreadyMerged = errors.New("table_already_merged")
+	ErrInvalidTableGroup  = errors.New("invalid_table_group")
+	ErrTableGroupSplit    = errors.New("table_group_split")
+)
+
+var (
+	ErrInvalidTableMove = errors.New("invalid_table_move")
+	ErrTableOccupied    = errors.New("table_occupied")
+)
+
+var (
+	ErrFloorPlanNotFound      = errors.New("floor_plan_not_found")
+	ErrInvalidFloorPlanLayout = errors.New("invalid_floor_plan_layout")
+)
+
+var (
+	ErrZoneNotFound      = errors.New("zone_not_found")
+	ErrZoneAlreadyExists = errors.New("zone_already_exists")
+	ErrZoneInUse         = errors.New("zone_in_use")
+)
+
 var listErrorData = []errData{
 	{
 		Code:        "cart_not_found",
@@ -331,6 +426,276 @@ var listErrorData = []errData{
 		MessageViVn: "Không tìm thấy token",
 		MessageEnUs: "Token not found",
 	},
+	{
+		Code:        "insufficient_stock",
+		HTTPCode:    409,
+		MessageViVn: "Sản phẩm không đủ số lượng tồn kho",
+		MessageEnUs: "Not enough stock for this item",
+	},
+	{
+		Code:        "invalid_stock_request",
+		HTTPCode:    400,
+		MessageViVn: "Thông tin tồn kho không hợp lệ",
+		MessageEnUs: "Invalid stock data",
+	},
+	{
+		Code:        "unsupported_locale",
+		HTTPCode:    400,
+		MessageViVn: "Ngôn ngữ không được hỗ trợ",
+		MessageEnUs: "Unsupported language",
+	},
+	{
+		Code:        "invalid_quote_item",
+		HTTPCode:    400,
+		MessageViVn: "Mỗi món phải là một món ăn hoặc một combo",
+		MessageEnUs: "Each line must be either a menu item or a combo",
+	},
+	{
+		Code:        "item_unavailable",
+		HTTPCode:    409,
+		MessageViVn: "Món ăn hiện không phục vụ",
+		MessageEnUs: "This item is currently unavailable",
+	},
+	{
+		Code:        "invalid_modifier_selection",
+		HTTPCode:    400,
+		MessageViVn: "Lựa chọn tuỳ chỉnh không hợp lệ",
+		MessageEnUs: "Invalid modifier selection",
+	},
+	{
+		Code:        "invalid_combo_selection",
+		HTTPCode:    400,
+		MessageViVn: "Lựa chọn combo không hợp lệ",
+		MessageEnUs: "Invalid combo selection",
+	},
+	{
+		Code:        "modifier_cycle",
+		HTTPCode:    400,
+		MessageViVn: "Nhóm tuỳ chỉnh này sẽ tạo thành vòng lặp",
+		MessageEnUs: "This modifier group would create a loop",
+	},
+	{
+		Code:        "modifier_depth_exceeded",
+		HTTPCode:    400,
+		MessageViVn: "Vượt quá số cấp tuỳ chỉnh lồng nhau cho phép",
+		MessageEnUs: "Too many levels of nested modifiers",
+	},
+	{
+		Code:        "invalid_modifier_override",
+		HTTPCode:    400,
+		MessageViVn: "Tuỳ chỉnh riêng cho món không hợp lệ",
+		MessageEnUs: "The modifier overrides for this item are not valid",
+	},
+	{
+		Code:        "invalid_reorder",
+		HTTPCode:    400,
+		MessageViVn: "Danh sách sắp xếp phải chứa đúng và đủ các mục hiện có",
+		MessageEnUs: "The new order must list every current entry exactly once",
+	},
+	{
+		Code:        "invalid_bulk_request",
+		HTTPCode:    400,
+		MessageViVn: "Yêu cầu hàng loạt không hợp lệ: cần ids hoặc bộ lọc và đủ tham số cho thao tác",
+		MessageEnUs: "Invalid bulk request: send either ids or a filter, and the parameters the action needs",
+	},
+	{
+		Code:        "bulk_limit_exceeded",
+		HTTPCode:    400,
+		MessageViVn: "Quá nhiều món trong một lần thao tác",
+		MessageEnUs: "Too many items for one bulk operation",
+	},
+	{
+		Code:        "invalid_price",
+		HTTPCode:    400,
+		MessageViVn: "Giá sau khi điều chỉnh phải lớn hơn 0",
+		MessageEnUs: "The adjusted price must be greater than 0",
+	},
+	{
+		Code:        "category_has_items",
+		HTTPCode:    409,
+		MessageViVn: "Danh mục vẫn còn món, hãy chuyển món sang danh mục khác trước khi xoá",
+		MessageEnUs: "The category still has items, move them to another category to delete it",
+	},
+	{
+		Code:        "modifier_group_in_use",
+		HTTPCode:    409,
+		MessageViVn: "Nhóm tuỳ chọn vẫn đang được gắn với món hoặc tuỳ chọn khác",
+		MessageEnUs: "The modifier group is still attached to items or options",
+	},
+	{
+		Code:        "invalid_target_category",
+		HTTPCode:    400,
+		MessageViVn: "Danh mục đích không hợp lệ",
+		MessageEnUs: "The target category is not valid",
+	},
+	{
+		Code:        "category_deleted",
+		HTTPCode:    409,
+		MessageViVn: "Danh mục của món đã bị xoá",
+		MessageEnUs: "The item's category has been deleted",
+	},
+	{
+		Code:        "invalid_image",
+		HTTPCode:    400,
+		MessageViVn: "Ảnh không hợp lệ, chỉ chấp nhận JPEG hoặc PNG",
+		MessageEnUs: "The image is not valid, only JPEG and PNG are accepted",
+	},
+	{
+		Code:        "upload_too_large",
+		HTTPCode:    400,
+		MessageViVn: "Tệp tải lên vượt quá dung lượng cho phép",
+		MessageEnUs: "The upload is larger than allowed",
+	},
+	{
+		Code:        "upload_not_received",
+		HTTPCode:    409,
+		MessageViVn: "Tệp chưa được tải lên",
+		MessageEnUs: "The file has not been uploaded yet",
+	},
+	{
+		Code:        "upload_mismatch",
+		HTTPCode:    400,
+		MessageViVn: "Tệp tải lên không khớp với kích thước hoặc định dạng đã khai báo",
+		MessageEnUs: "The uploaded file does not match the declared size or type",
+	},
+	{
+		Code:        "reservation_not_found",
+		HTTPCode:    404,
+		MessageViVn: "Đặt bàn không tồn tại",
+		MessageEnUs: "Reservation not found",
+	},
+	{
+		Code:        "invalid_reservation_status",
+		HTTPCode:    409,
+		MessageViVn: "Không thể chuyển trạng thái đặt bàn",
+		MessageEnUs: "The reservation cannot move to this status",
+	},
+	{
+		Code:        "invalid_reservation_time",
+		HTTPCode:    400,
+		MessageViVn: "Thời gian đặt bàn không hợp lệ",
+		MessageEnUs: "The reservation time is not valid",
+	},
+	{
+		Code:        "no_table_available",
+		HTTPCode:    409,
+		MessageViVn: "Không còn bàn trống phù hợp",
+		MessageEnUs: "No suitable table is free at this time",
+	},
+	{
+		Code:        "outside_opening_hours",
+		HTTPCode:    400,
+		MessageViVn: "Nhà hàng không mở cửa trong khung giờ này",
+		MessageEnUs: "The restaurant is not open for the whole reservation",
+	},
+	{
+		Code:        "table_already_booked",
+		HTTPCode:    409,
+		MessageViVn: "Bàn đã được đặt trong khung giờ này",
+		MessageEnUs: "The table is already booked at this time",
+	},
+	{
+		Code:        "insufficient_table_capacity",
+		HTTPCode:    400,
+		MessageViVn: "Số chỗ của bàn không đủ cho nhóm khách",
+		MessageEnUs: "The tables do not seat the whole party",
+	},
+	{
+		Code:        "table_location_mismatch",
+		HTTPCode:    400,
+		MessageViVn: "Bàn không thuộc khu vực đã chọn",
+		MessageEnUs: "The table is not in the chosen location",
+	},
+	{
+		Code:        "table_not_available",
+		HTTPCode:    409,
+		MessageViVn: "Bàn đang có khách hoặc ngừng phục vụ",
+		MessageEnUs: "The table is occupied or inactive",
+	},
+	{
+		Code:        "waitlist_entry_not_found",
+		HTTPCode:    404,
+		MessageViVn: "Không tìm thấy khách trong danh sách chờ",
+		MessageEnUs: "Waitlist entry not found",
+	},
+	{
+		Code:        "invalid_waitlist_status",
+		HTTPCode:    409,
+		MessageViVn: "Không thể chuyển trạng thái khách chờ",
+		MessageEnUs: "The waitlist entry cannot move to this status",
+	},
+	{
+		Code:        "wait_estimate_unavailable",
+		HTTPCode:    409,
+		MessageViVn: "Không ước tính được thời gian chờ, vui lòng nhập thời gian chờ",
+		MessageEnUs: "No table seats the party, so no wait can be estimated; give a quoted wait",
+	},
+	{
+		Code:        "table_group_not_found",
+		HTTPCode:    404,
+		MessageViVn: "Không tìm thấy nhóm bàn",
+		MessageEnUs: "Table group not found",
+	},
+	{
+		Code:        "table_already_merged",
+		HTTPCode:    409,
+		MessageViVn: "Bàn đã được ghép với bàn khác",
+		MessageEnUs: "The table is already merged with other tables",
+	},
+	{
+		Code:        "invalid_table_group",
+		HTTPCode:    400,
+		MessageViVn: "Cần ít nhất hai bàn khác nhau, và bàn chính phải thuộc nhóm",
+		MessageEnUs: "A group needs at least two different tables, including the session table",
+	},
+	{
+		Code:        "table_group_split",
+		HTTPCode:    409,
+		MessageViVn: "Nhóm bàn đã được tách",
+		MessageEnUs: "The table group has already been split",
+	},
+	{
+		Code:        "invalid_table_move",
+		HTTPCode:    400,
+		MessageViVn: "Không thể chuyển sang bàn này",
+		MessageEnUs: "Orders can only move to another table of the same restaurant, and the table must have something to move",
+	},
+	{
+		Code:        "table_occupied",
+		HTTPCode:    409,
+		MessageViVn: "Bàn đang có khách, chọn gộp để chuyển vào phiên của bàn này",
+		MessageEnUs: "The table is occupied; ask to merge to join its session",
+	},
+	{
+		Code:        "floor_plan_not_found",
+		HTTPCode:    404,
+		MessageViVn: "Không tìm thấy sơ đồ tầng",
+		MessageEnUs: "Floor plan not found",
+	},
+	{
+		Code:        "invalid_floor_plan_layout",
+		HTTPCode:    400,
+		MessageViVn: "Sơ đồ không hợp lệ: bàn hoặc khu vực nằm ngoài sơ đồ, hoặc một bàn được đặt hai lần",
+		MessageEnUs: "Invalid layout: a table or area lies outside the plan, or a table is placed twice",
+	},
+	{
+		Code:        "zone_not_found",
+		HTTPCode:    404,
+		MessageViVn: "Không tìm thấy khu vực",
+		MessageEnUs: "Zone not found",
+	},
+	{
+		Code:        "zone_already_exists",
+		HTTPCode:    409,
+		MessageViVn: "Tên khu vực đã tồn tại",
+		MessageEnUs: "A zone with this name already exists",
+	},
+	{
+		Code:        "zone_in_use",
+		HTTPCode:    409,
+		MessageViVn: "Khu vực vẫn còn bàn, hãy chuyển bàn sang khu vực khác trước",
+		MessageEnUs: "The zone still has tables; move them to another zone first",
+	},
 	{
 		Code:        ErrActionNotAllowed.Error(),
 		HTTPCode:    403,
@@ -356,7 +721,8 @@ type errData struct {
 }
 
 type ExtraData struct {
-	OrderID int64 `json:"order_id,omitempty"`
+	OrderID    int64       `json:"order_id,omitempty"`
+	Dependents interface{} `json:"dependents,omitempty"`
 }
 
 type LocalizeErrRes struct {
@@ -447,6 +813,14 @@ func (a *LocalizeErrRes) SetOrderIDToExtraData(orderID int64) *LocalizeErrRes {
 	return a
 }
 
+func (a *LocalizeErrRes) SetDependents(dependents interface{}) *LocalizeErrRes {
+	if a.ExtraData == nil {
+		a.ExtraData = new(ExtraData)
+	}
+	a.ExtraData.Dependents = dependents
+	return a
+}
+
 func (a *LocalizeErrRes) ConvertToBaseError() Response {
 	res := BaseResponse(REQUEST_FAILED, a.Message, a.Internal, a.ExtraData)
 	res.SetErrorCode(a.Code)
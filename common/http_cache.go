@@ -0,0 +1,50 @@
+package common
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"net/http"
+	"strings"
+	"time"
+
+	"github.com/gin-gonic/gin"
+)
+
+// ComputeETag is a strong ETag over the JSON form of the payload
+func ComputeETag(payload interface{}) (string, error) {
+	body, err := json.Marshal(payload)
+	if err != nil {
+		return "", err
+	}
+
+	sum := sha256.Sum256(body)
+	return `"` + hex.EncodeToString(sum[:16]) + `"`, nil
+}
+
+// SetCacheHeaders lets clients keep the response but ask again before using it
+func SetCacheHeaders(c *gin.Context, etag string, lastModified time.Time) {
+	c.Header("ETag", etag)
+	if !lastModified.IsZero() {
+		c.Header("Last-Modified", lastModified.UTC().Format(http.TimeFormat))
+	}
+	c.Header("Cache-Control", "private, no-cache")
+}
+
+// IsNotModified only trusts If-None-Match. Last-Modified is informational: not every change to a
+// response leaves a timestamp behind, so If-Modified-Since alone could answer 304 to stale clients.
+func IsNotModified(c *gin.Context, etag string) bool {
+	ifNoneMatch := c.GetHeader("If-None-Match")
+	if ifNoneMatch == "" {
+		return false
+	}
+
+	for _, candidate := range strings.Split(ifNoneMatch, ",") {
+		candidate = strings.TrimPrefix(strings.TrimSpace(candidate), "W/")
+		if candidate == "*" || candidate == etag {
+			return true
+		}
+	}
+
+	return false
+}
@@ -0,0 +1,85 @@
+package common
+
+import (
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/gin-gonic/gin"
+)
+
+// Menu content in the base tables is written in DEFAULT_LOCALE, other locales live in menu_translations
+const (
+	LOCALE_EN      = "en"
+	LOCALE_VI      = "vi"
+	DEFAULT_LOCALE = LOCALE_EN
+)
+
+var SUPPORTED_LOCALES = []string{LOCALE_EN, LOCALE_VI}
+
+func IsSupportedLocale(locale string) bool {
+	return ContainsString(SUPPORTED_LOCALES, locale)
+}
+
+// GetLocale picks the locale of a request: the lang query param wins, then the best supported
+// Accept-Language entry, then DEFAULT_LOCALE
+func GetLocale(c *gin.Context) string {
+	if lang := normalizeLocale(c.Query("lang")); IsSupportedLocale(lang) {
+		return lang
+	}
+
+	if locale := parseAcceptLanguage(c.GetHeader("Accept-Language")); locale != "" {
+		return locale
+	}
+
+	return DEFAULT_LOCALE
+}
+
+// parseAcceptLanguage handles headers like "vi-VN,vi;q=0.9,en;q=0.8"
+func parseAcceptLanguage(header string) string {
+	type weighted struct {
+		locale string
+		q      float64
+	}
+
+	candidates := make([]weighted, 0)
+	for _, part := range strings.Split(header, ",") {
+		fields := strings.Split(strings.TrimSpace(part), ";")
+		locale := normalizeLocale(fields[0])
+		if !IsSupportedLocale(locale) {
+			continue
+		}
+
+		q := 1.0
+		for _, field := range fields[1:] {
+			field = strings.TrimSpace(field)
+			if strings.HasPrefix(field, "q=") {
+				if value, err := strconv.ParseFloat(strings.TrimPrefix(field, "q="), 64); err == nil {
+					q = value
+				}
+			}
+		}
+		if q > 0 {
+			candidates = append(candidates, weighted{locale: locale, q: q})
+		}
+	}
+
+	if len(candidates) == 0 {
+		return ""
+	}
+
+	sort.SliceStable(candidates, func(i, j int) bool {
+		return candidates[i].q > candidates[j].q
+	})
+
+	return candidates[0].locale
+}
+
+// normalizeLocale reduces "vi-VN" / "vi_VN" to "vi"
+func normalizeLocale(locale string) string {
+	locale = strings.ToLower(strings.TrimSpace(locale))
+	if i := strings.IndexAny(locale, "-_"); i > 0 {
+		locale = locale[:i]
+	}
+	return locale
+}
@@ -39,15 +39,60 @@ type Schema struct {
 		StorageAcl           string `mapstructure:"storage_acl"`
 	} `mapstructure:"digital_ocean"`
 
+	Storage struct {
+		Driver    string `mapstructure:"driver"`
+		Bucket    string `mapstructure:"bucket"`
+		PublicURL string `mapstructure:"public_url"`
+		LocalRoot string `mapstructure:"local_root"`
+		// SigningSecret keys the signed urls of local storage, which is refused without one
+		SigningSecret string `mapstructure:"signing_secret"`
+
+		MaxUploadMB            int `mapstructure:"max_upload_mb"`
+		PresignExpiryMinutes   int `mapstructure:"presign_expiry_minutes"`
+		OrphanRetentionHours   int `mapstructure:"orphan_retention_hours"`
+		CleanupIntervalMinutes int `mapstructure:"cleanup_interval_minutes"`
+	} `mapstructure:"storage"`
+
 	Http struct {
 		MaxIdleConnection     int `mapstructure:"max_idle_connection"`
 		IdleConnectionTimeout int `mapstructure:"idle_connection_timeout"`
 	} `mapstructure:"http"`
 
+	Menu struct {
+		ModifierMaxDepth     int `mapstructure:"modifier_max_depth"`
+		CacheTTL             int `mapstructure:"cache_ttl"`
+		TrashRetentionDays   int `mapstructure:"trash_retention_days"`
+		PurgeIntervalMinutes int `mapstructure:"purge_interval_minutes"`
+	} `mapstructure:"menu"`
+
+	Reservation struct {
+		DefaultDurationMinutes int                 `mapstructure:"default_duration_minutes"`
+		UpcomingHours          int                 `mapstructure:"upcoming_hours"`
+		BufferMinutes          int                 `mapstructure:"buffer_minutes"`
+		SlotIntervalMinutes    int                 `mapstructure:"slot_interval_minutes"`
+		SearchWindowMinutes    int                 `mapstructure:"search_window_minutes"`
+		MaxCombinedTables      int                 `mapstructure:"max_combined_tables"`
+		TurnTimes              []TurnTime          `mapstructure:"turn_times"`
+		OpeningHours           map[string][]string `mapstructure:"opening_hours"`
+	} `mapstructure:"reservation"`
+
+	Waitlist struct {
+		StatusURL            string `mapstructure:"status_url"`
+		HistoryDays          int    `mapstructure:"history_days"`
+		MinHistoryOrders     int    `mapstructure:"min_history_orders"`
+		QuoteRoundingMinutes int    `mapstructure:"quote_rounding_minutes"`
+	} `mapstructure:"waitlist"`
+
 	JwtSecret        string `mapstructure:"jwt_secret"`
 	TokenExpiredTime int64  `mapstructure:"token_expired_time"`
 }
 
+// TurnTime is how long a party of up to MaxPartySize guests keeps a table. 0 means any size.
+type TurnTime struct {
+	MaxPartySize int `mapstructure:"max_party_size"`
+	Minutes      int `mapstructure:"minutes"`
+}
+
 // Redis ...
 type Redis struct {
 	Host string `yaml:"host" mapstructure:"host"`
@@ -15,11 +15,16 @@ type Handler struct {
 	service *services.Service
 }
 
-func NewHandler(sc server.ServerContext) *Handler {
+func NewHandler(sc server.ServerContext) (*Handler, error) {
+	service, err := services.NewService(sc)
+	if err != nil {
+		return nil, err
+	}
+
 	return &Handler{
 		sc:      sc,
-		service: services.NewService(sc),
-	}
+		service: service,
+	}, nil
 }
 
 func (h *Handler) RegisterRouter(c *gin.Engine) {
@@ -29,15 +34,81 @@ func (h *Handler) RegisterRouter(c *gin.Engine) {
 	admin := c.Group("/api/admin")
 	{
 		admin.POST("/upload", h.UploadImage())
+		admin.POST("/uploads/presign", h.PresignUpload())
+		admin.POST("/uploads/:id/confirm", h.ConfirmUpload())
 		admin.GET("/tables", h.GetTables())
 		admin.GET("/tables/:id", h.GetTableByID())
 		admin.POST("/tables", h.CreateTable())
 		admin.PUT("/tables/:id", h.UpdateTable())
 		admin.PATCH("/tables/:id/status", h.UpdateTableStatus())
+		admin.POST("/tables/:id/move", h.MoveTable())
+		admin.GET("/tables/:id/moves", h.GetTableMoves())
 		admin.POST("/tables/:id/qr/generate", h.GenerateQrCodeByTableId())
 		admin.GET("tables/:id/qr/download", h.DownloadQrCodeByTableId())
 		admin.GET("tables/qr/download-all", h.DownloadAllQrCode())
 		admin.GET("tables/:id/qr", h.GetQrCodeByTableId())
+		admin.POST("/orders/:id/accept", h.AcceptOrder())
+		admin.POST("/orders/:id/cancel", h.CancelOrder())
+		admin.GET("/inventory/alerts", h.GetStockAlerts())
+		admin.GET("/inventory/movements", h.GetStockMovements())
+		admin.GET("/inventory/usage-report", h.GetIngredientUsageReport())
+
+		ingredientsAdmin := admin.Group("/inventory/ingredients")
+		{
+			ingredientsAdmin.GET("", h.GetIngredients())
+			ingredientsAdmin.GET("/:id", h.GetIngredientByID())
+			ingredientsAdmin.POST("", h.CreateIngredient())
+			ingredientsAdmin.PUT("/:id", h.UpdateIngredient())
+			ingredientsAdmin.DELETE("/:id", h.DeleteIngredient())
+			ingredientsAdmin.POST("/:id/movements", h.CreateStockMovement())
+		}
+
+		reservationsAdmin := admin.Group("/reservations")
+		{
+			reservationsAdmin.GET("", h.GetReservations())
+			reservationsAdmin.GET("/availability", h.GetReservationAvailability())
+			reservationsAdmin.GET("/:id", h.GetReservationByID())
+			reservationsAdmin.POST("", h.CreateReservation())
+			reservationsAdmin.PUT("/:id", h.UpdateReservation())
+			reservationsAdmin.PATCH("/:id/status", h.UpdateReservationStatus())
+		}
+
+		floorPlansAdmin := admin.Group("/floor-plans")
+		{
+			floorPlansAdmin.GET("", h.GetFloorPlans())
+			floorPlansAdmin.GET("/:id", h.GetFloorPlanByID())
+			floorPlansAdmin.POST("", h.CreateFloorPlan())
+			floorPlansAdmin.PUT("/:id/layout", h.SaveFloorPlanLayout())
+			floorPlansAdmin.DELETE("/:id", h.DeleteFloorPlan())
+		}
+
+		zonesAdmin := admin.Group("/zones")
+		{
+			zonesAdmin.GET("", h.GetZones())
+			zonesAdmin.GET("/:id", h.GetZoneByID())
+			zonesAdmin.POST("", h.CreateZone())
+			zonesAdmin.PUT("/:id", h.UpdateZone())
+			zonesAdmin.DELETE("/:id", h.DeleteZone())
+		}
+
+		tableGroupsAdmin := admin.Group("/table-groups")
+		{
+			tableGroupsAdmin.GET("", h.GetTableGroups())
+			tableGroupsAdmin.GET("/:id", h.GetTableGroupByID())
+			tableGroupsAdmin.POST("", h.MergeTables())
+			tableGroupsAdmin.POST("/:id/split", h.SplitTableGroup())
+		}
+
+		waitlistAdmin := admin.Group("/waitlist")
+		{
+			waitlistAdmin.GET("", h.GetWaitlist())
+			waitlistAdmin.GET("/estimate", h.GetWaitEstimate())
+			waitlistAdmin.GET("/:id", h.GetWaitlistEntryByID())
+			waitlistAdmin.POST("", h.CreateWaitlistEntry())
+			waitlistAdmin.POST("/:id/notify", h.NotifyWaitlistEntry())
+			waitlistAdmin.POST("/:id/seat", h.SeatWaitlistEntry())
+			waitlistAdmin.DELETE("/:id", h.RemoveWaitlistEntry())
+		}
 
 		menuAdmin := admin.Group("/menu")
 		{
@@ -46,14 +117,47 @@ func (h *Handler) RegisterRouter(c *gin.Engine) {
 			menuAdmin.POST("/categories", h.CreateMenuCategory())
 			menuAdmin.PUT("/categories/:id", h.UpdateMenuCategory())
 			menuAdmin.PATCH("/categories/:id/status", h.UpdateMenuCategoryStatus())
+			menuAdmin.DELETE("/categories/:id", h.DeleteMenuCategory())
+			menuAdmin.PUT("/categories/reorder", h.ReorderMenuCategories())
+			menuAdmin.PUT("/categories/:id/items/reorder", h.ReorderCategoryItems())
+
+			combosAdmin := menuAdmin.Group("/combos")
+			{
+				combosAdmin.GET("", h.GetCombos())
+				combosAdmin.GET("/:id", h.GetComboByID())
+				combosAdmin.POST("", h.CreateCombo())
+				combosAdmin.PUT("/:id", h.UpdateCombo())
+				combosAdmin.DELETE("/:id", h.DeleteCombo())
+			}
+
+			translationsAdmin := menuAdmin.Group("/translations")
+			{
+				translationsAdmin.GET("", h.GetTranslations())
+				translationsAdmin.GET("/missing", h.GetMissingTranslations())
+				translationsAdmin.PUT("/:entity_type/:entity_id/:locale", h.UpsertTranslation())
+				translationsAdmin.DELETE("/:entity_type/:entity_id/:locale", h.DeleteTranslation())
+			}
 
 			itemsAdmin := menuAdmin.Group("/items")
 			{
 				itemsAdmin.GET("", h.GetMenuItems())
+				itemsAdmin.GET("/trash", h.GetDeletedMenuItems())
 				itemsAdmin.GET("/:id", h.GetMenuItemByID())
 				itemsAdmin.POST("", h.CreateMenuItem())
+				itemsAdmin.POST("/bulk", h.BulkUpdateMenuItems())
 				itemsAdmin.PUT("/:id", h.UpdateMenuItem())
 				itemsAdmin.DELETE("/:id", h.DeleteMenuItem())
+				itemsAdmin.POST("/:id/restore", h.RestoreMenuItem())
+				itemsAdmin.DELETE("/:id/purge", h.PurgeMenuItem())
+				itemsAdmin.PATCH("/:id/stock", h.UpdateMenuItemStock())
+				itemsAdmin.GET("/:id/recipe", h.GetMenuItemRecipe())
+				itemsAdmin.PUT("/:id/recipe", h.UpdateMenuItemRecipe())
+				itemsAdmin.PUT("/:id/modifier-groups/:groupId", h.UpdateMenuItemModifierGroup())
+				itemsAdmin.GET("/:id/photos", h.GetMenuItemPhotos())
+				itemsAdmin.POST("/:id/photos", h.AddMenuItemPhoto())
+				itemsAdmin.PUT("/:id/photos/reorder", h.ReorderMenuItemPhotos())
+				itemsAdmin.PATCH("/:id/photos/:photoId/primary", h.SetPrimaryMenuItemPhoto())
+				itemsAdmin.DELETE("/:id/photos/:photoId", h.DeleteMenuItemPhoto())
 			}
 
 			modifiersGroupAdmin := menuAdmin.Group("/modifier-groups")
@@ -63,12 +167,20 @@ func (h *Handler) RegisterRouter(c *gin.Engine) {
 				modifiersGroupAdmin.PUT("/:id", h.UpdateModifierGroup())
 				modifiersGroupAdmin.DELETE("/:id", h.DeleteModifierGroup())
 				modifiersGroupAdmin.POST("/:id/options", h.CreateModifierOptions())
+				modifiersGroupAdmin.PUT("/reorder", h.ReorderModifierGroups())
+				modifiersGroupAdmin.PUT("/:id/options/reorder", h.ReorderModifierOptions())
 			}
 
 			modifiersOptionsAdmin := menuAdmin.Group("/modifier-options")
 			{
 				modifiersOptionsAdmin.PUT("/:id", h.UpdateModifierOptions())
 				modifiersOptionsAdmin.DELETE("/:id", h.DeleteModifierOptions())
+				modifiersOptionsAdmin.PATCH("/:id/stock", h.UpdateModifierOptionStock())
+				modifiersOptionsAdmin.GET("/:id/recipe", h.GetModifierOptionRecipe())
+				modifiersOptionsAdmin.PUT("/:id/recipe", h.UpdateModifierOptionRecipe())
+				modifiersOptionsAdmin.GET("/:id/child-groups", h.GetModifierOptionChildGroups())
+				modifiersOptionsAdmin.POST("/:id/child-groups", h.AttachModifierOptionChildGroup())
+				modifiersOptionsAdmin.DELETE("/:id/child-groups/:groupId", h.DetachModifierOptionChildGroup())
 			}
 		}
 	}
@@ -76,13 +188,25 @@ func (h *Handler) RegisterRouter(c *gin.Engine) {
 	menu := c.Group("/api/menu")
 	{
 		menu.GET("", h.LoadMenu())
+		menu.GET("/document", h.GetMenuDocument())
+		menu.POST("/quote", h.Quote())
 
 		menuItem := menu.Group("/items")
 		{
-			menuItem.GET("/:id", h.GetMenuItemByID())
+			menuItem.GET("/:id", h.GetGuestMenuItemByID())
 			menuItem.POST("/:id/modifier-groups", h.AssignMenuItemModifierGroup())
 			menuItem.DELETE("/:id/modifier-groups/:groupId", h.DeleteMenuItemModifierGroup())
 		}
 	}
 
+	reservations := c.Group("/api/reservations")
+	{
+		reservations.GET("/availability", h.GetGuestReservationAvailability())
+	}
+
+	waitlist := c.Group("/api/waitlist")
+	{
+		waitlist.GET("/:token", h.GetWaitlistStatus())
+	}
+
 }
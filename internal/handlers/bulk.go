@@ -0,0 +1,26 @@
+package handlers
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) BulkUpdateMenuItems() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.BulkMenuItemRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.BulkUpdateMenuItems(c, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
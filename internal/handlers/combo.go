@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetCombos() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.ListComboRequest
+		if err := c.ShouldBindQuery(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetCombos(c, &params)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetComboByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.ComboIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetComboByID(c, params.ID)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) CreateCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateComboRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.CreateCombo(c, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) UpdateCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.ComboIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.UpdateComboRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.UpdateCombo(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) DeleteCombo() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.ComboIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		if err := h.service.DeleteCombo(c, params.ID); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(nil))
+	}
+}
@@ -0,0 +1,103 @@
+package handlers
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetFloorPlans() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.ListFloorPlansRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetFloorPlans(c, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetFloorPlanByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.FloorPlanIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetFloorPlanByID(c, params.ID)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) CreateFloorPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateFloorPlanRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.CreateFloorPlan(c, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) SaveFloorPlanLayout() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.FloorPlanIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.SaveFloorPlanLayoutRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.SaveFloorPlanLayout(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) DeleteFloorPlan() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.FloorPlanIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		if err := h.service.DeleteFloorPlan(c, params.ID); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(nil))
+	}
+}
@@ -0,0 +1,250 @@
+package handlers
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"strconv"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) GetIngredients() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.ListIngredientRequest
+		if err := c.ShouldBindQuery(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetIngredients(c, &params)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetIngredientByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.IngredientIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetIngredientByID(c, params.ID)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) CreateIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.CreateIngredientRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.CreateIngredient(c, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) UpdateIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.IngredientIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.UpdateIngredientRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.UpdateIngredient(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) DeleteIngredient() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.IngredientIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		if err := h.service.DeleteIngredient(c, params.ID); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(nil))
+	}
+}
+
+func (h *Handler) CreateStockMovement() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.IngredientIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.CreateStockMovementRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.CreateStockMovement(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetStockMovements() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.ListStockMovementRequest
+		if err := c.ShouldBindQuery(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetStockMovements(c, &params)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetIngredientUsageReport() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.IngredientUsageReportRequest
+		if err := c.ShouldBindQuery(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetIngredientUsageReport(c, &params)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetMenuItemRecipe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.MenuItemIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetMenuItemRecipe(c, params.ID)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) UpdateMenuItemRecipe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.MenuItemIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.UpdateRecipeRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.UpdateMenuItemRecipe(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) GetModifierOptionRecipe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetModifierOptionRecipe(c, id)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) UpdateModifierOptionRecipe() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.UpdateRecipeRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.UpdateModifierOptionRecipe(c, id, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
@@ -3,7 +3,6 @@ package handlers
 import (
 	"app-noti/common"
 	"app-noti/internal/models"
-	storage "app-noti/services/digital_ocean_storage"
 	"net/http"
 	"strconv"
 	"time"
@@ -11,48 +10,103 @@ import (
 	"github.com/gin-gonic/gin"
 )
 
+// menuTable checks the table and token from the QR link. It writes the error response itself.
+func (h *Handler) menuTable(c *gin.Context) (*models.TableWithOrderData, bool) {
+	tableIdStr := c.Query("table")
+	token := c.Query("token")
+
+	if tableIdStr == "" || token == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"menu": false, "error": "table and token required"})
+		return nil, false
+	}
+
+	tableId, err := strconv.Atoi(tableIdStr)
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"menu": false, "error": "invalid table id"})
+		return nil, false
+	}
+
+	table, err := h.service.GetTableByID(c, tableId)
+	if err != nil {
+		c.JSON(http.StatusForbidden, gin.H{"menu": false})
+		return nil, false
+	}
+
+	if table.QrToken != token || table.QrTokenExpiresAt == nil || time.Now().After(*table.QrTokenExpiresAt) {
+		c.JSON(http.StatusForbidden, gin.H{"menu": false})
+		return nil, false
+	}
+
+	return table, true
+}
+
 func (h *Handler) LoadMenu() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		tableIdStr := c.Query("table")
-		token := c.Query("token")
-
-		if tableIdStr == "" || token == "" {
-			c.JSON(http.StatusBadRequest, gin.H{"menu": false, "error": "table and token required"})
+		table, ok := h.menuTable(c)
+		if !ok {
 			return
 		}
 
-		tableId, err := strconv.Atoi(tableIdStr)
-		if err != nil {
-			c.JSON(http.StatusBadRequest, gin.H{"menu": false, "error": "invalid table id"})
+		var params models.ListMenuRequest
+		if err := c.ShouldBindQuery(&params); err != nil {
+			common.AbortWithError(c, err)
 			return
 		}
 
-		table, err := h.service.GetTableByID(c, tableId)
+		params.Locale = common.GetLocale(c)
+
+		menu, err := h.service.GetGuestMenu(c, table.RestaurantId, &params)
 		if err != nil {
-			c.JSON(http.StatusForbidden, gin.H{"menu": false})
+			c.JSON(http.StatusInternalServerError, gin.H{
+				"message": err.Error(),
+			})
 			return
 		}
 
-		if table.QrToken != token || table.QrTokenExpiresAt == nil || time.Now().After(*table.QrTokenExpiresAt) {
-			c.JSON(http.StatusForbidden, gin.H{"menu": false})
+		c.JSON(http.StatusOK, menu)
+	}
+}
+
+// GetMenuDocument returns the full menu in one payload. Clients send back the ETag in
+// If-None-Match and get a 304 while nothing has changed.
+func (h *Handler) GetMenuDocument() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		table, ok := h.menuTable(c)
+		if !ok {
 			return
 		}
 
-		var params models.ListMenuRequest
+		var params models.PhotoSizeRequest
 		if err := c.ShouldBindQuery(&params); err != nil {
 			common.AbortWithError(c, err)
 			return
 		}
 
-		restaurantId := table.RestaurantId
-		menuItemsResponse, err := h.service.GetMenuItemsByRestaurant(c, restaurantId, &params)
+		data, err := h.service.GetMenuDocument(c, table.RestaurantId, common.GetLocale(c), params.Size)
 		if err != nil {
-			c.JSON(http.StatusInternalServerError, gin.H{
-				"message": err.Error(),
-			})
+			common.AbortWithError(c, err)
+			return
+		}
+
+		etag, err := common.ComputeETag(data)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var lastModified time.Time
+		if data.UpdatedAt != nil {
+			lastModified = *data.UpdatedAt
+		}
+
+		common.SetCacheHeaders(c, etag, lastModified)
+		c.Header("Vary", "Accept-Language")
+		if common.IsNotModified(c, etag) {
+			c.Status(http.StatusNotModified)
 			return
 		}
-		c.JSON(http.StatusOK, menuItemsResponse)
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
 	}
 }
 
@@ -158,6 +212,30 @@ func (h *Handler) UpdateMenuCategoryStatus() gin.HandlerFunc {
 	}
 }
 
+func (h *Handler) DeleteMenuCategory() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.MenuCategoryParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.DeleteMenuCategoryRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		err := h.service.DeleteMenuCategory(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(gin.H{"message": "Menu category deleted successfully"}))
+	}
+}
+
 func (h *Handler) GetMenuItems() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var params models.ListMenuItemRequest
@@ -194,6 +272,30 @@ func (h *Handler) GetMenuItemByID() gin.HandlerFunc {
 	}
 }
 
+func (h *Handler) GetGuestMenuItemByID() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.MenuItemIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var size models.PhotoSizeRequest
+		if err := c.ShouldBindQuery(&size); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetGuestMenuItemByID(c, params.ID, common.GetLocale(c), size.Size)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
 func (h *Handler) CreateMenuItem() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		var request models.CreateMenuItemRequest
@@ -254,6 +356,8 @@ func (h *Handler) DeleteMenuItem() gin.HandlerFunc {
 	}
 }
 
+// UploadImage stores a menu photo in every variant size. The type is checked from the file
+// content, whatever Content-Type the client sent.
 func (h *Handler) UploadImage() gin.HandlerFunc {
 	return func(c *gin.Context) {
 		file, err := c.FormFile("file")
@@ -262,49 +366,54 @@ func (h *Handler) UploadImage() gin.HandlerFunc {
 			return
 		}
 
-		contentType := file.Header.Get("Content-Type")
-		if contentType != "image/jpeg" && contentType != "image/jpg" && contentType != "image/png" {
-			c.JSON(http.StatusBadRequest, gin.H{"error": "Only PNG and JPG images are allowed"})
-			return
-		}
-
 		if file.Size > 10*1024*1024 {
 			c.JSON(http.StatusBadRequest, gin.H{"error": "File size must be less than 10MB"})
 			return
 		}
 
-		err, doStorage := storage.NewDOStorage("menu-items")
+		data, err := h.service.UploadMenuPhoto(c, file)
 		if err != nil {
 			common.AbortWithError(c, err)
 			return
 		}
 
-		if err := doStorage.Run(); err != nil {
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) AssignMenuItemModifierGroup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var request models.AssignModifierToMenuItemRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
 			common.AbortWithError(c, err)
 			return
 		}
 
-		fileURL, err := doStorage.UploadFile(file, "smart-restaurant")
+		data, err := h.service.AssignMenuItemModifierGroup(c, &request)
 		if err != nil {
 			common.AbortWithError(c, err)
 			return
 		}
 
-		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(gin.H{
-			"url": fileURL,
-		}))
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
 	}
 }
 
-func (h *Handler) AssignMenuItemModifierGroup() gin.HandlerFunc {
+func (h *Handler) UpdateMenuItemModifierGroup() gin.HandlerFunc {
 	return func(c *gin.Context) {
-		var request models.AssignModifierToMenuItemRequest
+		var uri models.MenuItemModifierGroupUri
+		if err := c.ShouldBindUri(&uri); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.UpdateMenuItemModifierGroupRequest
 		if err := c.ShouldBindJSON(&request); err != nil {
 			common.AbortWithError(c, err)
 			return
 		}
 
-		data, err := h.service.AssignMenuItemModifierGroup(c, &request)
+		data, err := h.service.UpdateMenuItemModifierGroup(c, uri.MenuItemID, uri.GroupID, &request)
 		if err != nil {
 			common.AbortWithError(c, err)
 			return
@@ -77,7 +77,13 @@ func (h *Handler) DeleteModifierGroup() gin.HandlerFunc {
 			return
 		}
 
-		err := h.service.DeleteModifierGroup(c, params.ID)
+		var request models.DeleteModifierGroupRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		err := h.service.DeleteModifierGroup(c, params.ID, &request)
 		if err != nil {
 			common.AbortWithError(c, err)
 			return
@@ -154,3 +160,65 @@ func (h *Handler) DeleteModifierOptions() gin.HandlerFunc {
 		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(gin.H{"message": "Modifier Option deleted successfully"}))
 	}
 }
+
+func (h *Handler) GetModifierOptionChildGroups() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.GetModifierOptionChildGroups(c, id)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) AttachModifierOptionChildGroup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		idStr := c.Param("id")
+		id, err := strconv.Atoi(idStr)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.AttachChildGroupRequest
+		if err := c.ShouldBindJSON(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.AttachChildGroup(c, id, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) DetachModifierOptionChildGroup() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.DetachChildGroupUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		err := h.service.DetachChildGroup(c, params.OptionID, params.GroupID)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(gin.H{"message": "Child group detached successfully"}))
+	}
+}
@@ -0,0 +1,56 @@
+package handlers
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+
+	"github.com/gin-gonic/gin"
+)
+
+func (h *Handler) AcceptOrder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.OrderIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.OrderActionRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.AcceptOrder(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
+
+func (h *Handler) CancelOrder() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		var params models.OrderIDParamsUri
+		if err := c.ShouldBindUri(&params); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		var request models.OrderActionRequest
+		if err := c.ShouldBindQuery(&request); err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		data, err := h.service.CancelOrder(c, params.ID, &request)
+		if err != nil {
+			common.AbortWithError(c, err)
+			return
+		}
+
+		c.JSON(common.SUCCESS_STATUS, common.ResponseOk(data))
+	}
+}
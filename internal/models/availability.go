@@ -0,0 +1,34 @@
+package models
+
+import "time"
+
+type AvailabilityRequest struct {
+	RestaurantID *int    `form:"restaurant_id"`
+	Date         string  `form:"date" binding:"required,datetime=2006-01-02"`
+	Time         *string `form:"time" binding:"omitempty,datetime=15:04"`
+	PartySize    int     `form:"party_size" binding:"required,min=1,max=100"`
+	// Zone is a zone name, in any case; ZoneID picks the zone by id
+	Zone   *string `form:"zone"`
+	ZoneID *int    `form:"zone_id"`
+}
+
+type AvailabilityResponse struct {
+	Date            string              `json:"date"`
+	PartySize       int                 `json:"party_size"`
+	DurationMinutes int                 `json:"duration_minutes"`
+	Slots           []*AvailabilitySlot `json:"slots"`
+}
+
+type AvailabilitySlot struct {
+	StartsAt time.Time `json:"starts_at"`
+	EndsAt   time.Time `json:"ends_at"`
+	// Options are left out for guests
+	Options []*TableOption `json:"options,omitempty"`
+}
+
+// TableOption is one table, or tables in the same zone pushed together, that seats the party
+type TableOption struct {
+	TableIDs []int            `json:"table_ids"`
+	Capacity int              `json:"capacity"`
+	Tables   []*ReservedTable `json:"tables"`
+}
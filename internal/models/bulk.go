@@ -0,0 +1,79 @@
+package models
+
+const (
+	BulkActionSetStatus          = "set_status"
+	BulkActionMoveCategory       = "move_category"
+	BulkActionAdjustPrice        = "adjust_price"
+	BulkActionSetChefRecommended = "set_chef_recommended"
+	BulkActionDelete             = "delete"
+)
+
+const (
+	PriceAdjustAbsolute = "absolute"
+	PriceAdjustPercent  = "percent"
+)
+
+const (
+	RoundingNearest = "nearest"
+	RoundingUp      = "up"
+	RoundingDown    = "down"
+)
+
+const (
+	BulkResultUpdated     = "updated"
+	BulkResultWouldUpdate = "would_update"
+	BulkResultUnchanged   = "unchanged"
+	BulkResultFailed      = "failed"
+)
+
+// BulkMenuItemRequest targets either a list of ids or a filter, never both
+type BulkMenuItemRequest struct {
+	IDs    []int               `json:"ids" binding:"omitempty,unique,dive,min=1"`
+	Filter *BulkMenuItemFilter `json:"filter"`
+	Action string              `json:"action" binding:"required,oneof=set_status move_category adjust_price set_chef_recommended delete"`
+	DryRun bool                `json:"dry_run"`
+
+	Status          *string              `json:"status" binding:"omitempty,oneof=available unavailable sold_out"`
+	CategoryID      *int                 `json:"category_id" binding:"omitempty,min=1"`
+	Price           *BulkPriceAdjustment `json:"price"`
+	ChefRecommended *bool                `json:"chef_recommended"`
+}
+
+type BulkMenuItemFilter struct {
+	RestaurantID *int    `json:"restaurant_id"`
+	CategoryID   *int    `json:"category_id"`
+	Status       *string `json:"status" binding:"omitempty,oneof=available unavailable sold_out"`
+	Search       *string `json:"search"`
+}
+
+// BulkPriceAdjustment changes prices by an amount ("absolute", may be negative) or by a percent
+// ("percent", 5 = +5%), then rounds the result. Step defaults to 0.01.
+type BulkPriceAdjustment struct {
+	Mode     string        `json:"mode" binding:"required,oneof=absolute percent"`
+	Value    float64       `json:"value"`
+	Rounding *BulkRounding `json:"rounding"`
+}
+
+type BulkRounding struct {
+	Mode string  `json:"mode" binding:"required,oneof=nearest up down"`
+	Step float64 `json:"step" binding:"omitempty,gt=0"`
+}
+
+type BulkMenuItemResponse struct {
+	Action    string                `json:"action"`
+	DryRun    bool                  `json:"dry_run"`
+	Matched   int                   `json:"matched"`
+	Updated   int                   `json:"updated"`
+	Unchanged int                   `json:"unchanged"`
+	Failed    int                   `json:"failed"`
+	Results   []*BulkMenuItemResult `json:"results"`
+}
+
+type BulkMenuItemResult struct {
+	ID     int                    `json:"id"`
+	Name   string                 `json:"name"`
+	Result string                 `json:"result"`
+	Error  string                 `json:"error,omitempty"`
+	Before map[string]interface{} `json:"before,omitempty"`
+	After  map[string]interface{} `json:"after,omitempty"`
+}
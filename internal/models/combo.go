@@ -0,0 +1,137 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+type Combo struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	CategoryID   *int       `json:"category_id,omitempty" gorm:"column:category_id"`
+	Name         string     `json:"name" gorm:"column:name"`
+	Description  *string    `json:"description,omitempty" gorm:"column:description"`
+	Price        float64    `json:"price" gorm:"column:price"`
+	ImageURL     *string    `json:"image_url,omitempty" gorm:"column:image_url"`
+	Status       string     `json:"status" gorm:"column:status"`
+	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
+	IsDeleted    bool       `json:"is_deleted" gorm:"column:is_deleted"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (Combo) TableName() string {
+	return common.POSTGRES_TABLE_NAME_COMBOS
+}
+
+type ComboSlot struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ComboID      int        `json:"combo_id" gorm:"column:combo_id"`
+	Name         string     `json:"name" gorm:"column:name"`
+	MinChoices   int        `json:"min_choices" gorm:"column:min_choices"`
+	MaxChoices   int        `json:"max_choices" gorm:"column:max_choices"`
+	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (ComboSlot) TableName() string {
+	return common.POSTGRES_TABLE_NAME_COMBO_SLOTS
+}
+
+type ComboSlotChoice struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	SlotID       int        `json:"slot_id" gorm:"column:slot_id"`
+	MenuItemID   int        `json:"menu_item_id" gorm:"column:menu_item_id"`
+	Upcharge     float64    `json:"upcharge" gorm:"column:upcharge"`
+	IsDefault    bool       `json:"is_default" gorm:"column:is_default"`
+	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (ComboSlotChoice) TableName() string {
+	return common.POSTGRES_TABLE_NAME_COMBO_SLOT_CHOICES
+}
+
+type CreateComboRequest struct {
+	RestaurantID *int                     `json:"restaurant_id"`
+	CategoryID   *int                     `json:"category_id"`
+	Name         string                   `json:"name" binding:"required,max=80"`
+	Description  *string                  `json:"description"`
+	Price        float64                  `json:"price" binding:"required,gt=0"`
+	ImageURL     *string                  `json:"image_url"`
+	Status       string                   `json:"status" binding:"required,oneof=available unavailable"`
+	DisplayOrder int                      `json:"display_order" binding:"min=0"`
+	Slots        []CreateComboSlotRequest `json:"slots" binding:"required,min=1,dive"`
+}
+
+type CreateComboSlotRequest struct {
+	Name         string                     `json:"name" binding:"required,max=80"`
+	MinChoices   int                        `json:"min_choices" binding:"min=0"`
+	MaxChoices   int                        `json:"max_choices" binding:"required,min=1"`
+	DisplayOrder int                        `json:"display_order" binding:"min=0"`
+	Choices      []CreateComboChoiceRequest `json:"choices" binding:"required,min=1,dive"`
+}
+
+type CreateComboChoiceRequest struct {
+	MenuItemID   int     `json:"menu_item_id" binding:"required,min=1"`
+	Upcharge     float64 `json:"upcharge" binding:"min=0"`
+	IsDefault    bool    `json:"is_default"`
+	DisplayOrder int     `json:"display_order" binding:"min=0"`
+}
+
+// UpdateComboRequest replaces all slots when Slots is sent
+type UpdateComboRequest struct {
+	CategoryID   *int                     `json:"category_id"`
+	Name         *string                  `json:"name" binding:"omitempty,max=80"`
+	Description  *string                  `json:"description"`
+	Price        *float64                 `json:"price" binding:"omitempty,gt=0"`
+	ImageURL     *string                  `json:"image_url"`
+	Status       *string                  `json:"status" binding:"omitempty,oneof=available unavailable"`
+	DisplayOrder *int                     `json:"display_order" binding:"omitempty,min=0"`
+	Slots        []CreateComboSlotRequest `json:"slots" binding:"omitempty,dive"`
+}
+
+type ListComboRequest struct {
+	BaseRequestParamsUri
+	Search *string `form:"search"`
+	Status *string `form:"status"`
+}
+
+type ComboIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+type ComboResponse struct {
+	ID           int                  `json:"id"`
+	Name         string               `json:"name"`
+	Description  *string              `json:"description,omitempty"`
+	Price        float64              `json:"price"`
+	ImageURL     *string              `json:"image_url,omitempty"`
+	Category     string               `json:"category,omitempty"`
+	Status       string               `json:"status"`
+	IsAvailable  bool                 `json:"is_available"`
+	DisplayOrder int                  `json:"display_order"`
+	Slots        []*ComboSlotResponse `json:"slots"`
+}
+
+type ComboSlotResponse struct {
+	ID         int                    `json:"id"`
+	Name       string                 `json:"name"`
+	MinChoices int                    `json:"min_choices"`
+	MaxChoices int                    `json:"max_choices"`
+	Choices    []*ComboChoiceResponse `json:"choices"`
+}
+
+type ComboChoiceResponse struct {
+	MenuItemID  int     `json:"menu_item_id"`
+	Name        string  `json:"name"`
+	Upcharge    float64 `json:"upcharge"`
+	IsDefault   bool    `json:"is_default"`
+	IsAvailable bool    `json:"is_available"`
+}
+
+// GuestMenuResponse is the guest menu: the item list plus the combos on offer
+type GuestMenuResponse struct {
+	BaseListResponse
+	Combos []*ComboResponse `json:"combos"`
+}
@@ -0,0 +1,52 @@
+package models
+
+// The 14 allergens of EU regulation 1169/2011
+const (
+	AllergenGluten      = "gluten"
+	AllergenCrustaceans = "crustaceans"
+	AllergenEggs        = "eggs"
+	AllergenFish        = "fish"
+	AllergenPeanuts     = "peanuts"
+	AllergenSoybeans    = "soybeans"
+	AllergenMilk        = "milk"
+	AllergenNuts        = "nuts"
+	AllergenCelery      = "celery"
+	AllergenMustard     = "mustard"
+	AllergenSesame      = "sesame"
+	AllergenSulphites   = "sulphites"
+	AllergenLupin       = "lupin"
+	AllergenMolluscs    = "molluscs"
+)
+
+const (
+	DietaryVegan      = "vegan"
+	DietaryVegetarian = "vegetarian"
+	DietaryHalal      = "halal"
+	DietaryGlutenFree = "gluten_free"
+)
+
+// NutritionFacts are per portion. Every field is optional.
+type NutritionFacts struct {
+	Calories *float64 `json:"calories,omitempty" binding:"omitempty,min=0"`
+	ProteinG *float64 `json:"protein_g,omitempty" binding:"omitempty,min=0"`
+	CarbsG   *float64 `json:"carbs_g,omitempty" binding:"omitempty,min=0"`
+	FatG     *float64 `json:"fat_g,omitempty" binding:"omitempty,min=0"`
+	SugarG   *float64 `json:"sugar_g,omitempty" binding:"omitempty,min=0"`
+	FiberG   *float64 `json:"fiber_g,omitempty" binding:"omitempty,min=0"`
+	SaltG    *float64 `json:"salt_g,omitempty" binding:"omitempty,min=0"`
+}
+
+// DietaryInfo is shared by menu item and modifier option requests
+type DietaryInfo struct {
+	Allergens   []string        `json:"allergens" binding:"omitempty,dive,oneof=gluten crustaceans eggs fish peanuts soybeans milk nuts celery mustard sesame sulphites lupin molluscs"`
+	DietaryTags []string        `json:"dietary_tags" binding:"omitempty,dive,oneof=vegan vegetarian halal gluten_free"`
+	SpicyLevel  *int            `json:"spicy_level" binding:"omitempty,min=0,max=5"`
+	Nutrition   *NutritionFacts `json:"nutrition"`
+}
+
+// DietaryFilter narrows menu listings. Values are comma separated.
+type DietaryFilter struct {
+	ExcludeAllergens *string `form:"exclude_allergens"`
+	Dietary          *string `form:"dietary"`
+	MaxSpicyLevel    *int    `form:"max_spicy_level" binding:"omitempty,min=0,max=5"`
+}
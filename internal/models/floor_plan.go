@@ -0,0 +1,119 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	TableShapeRectangle = "rectangle"
+	TableShapeCircle    = "circle"
+)
+
+type FloorPlan struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	Name         string     `json:"name" gorm:"column:name"`
+	Width        float64    `json:"width" gorm:"column:width"`
+	Height       float64    `json:"height" gorm:"column:height"`
+	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt    *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+
+	Areas  []*FloorPlanArea  `json:"areas,omitempty" gorm:"-"`
+	Tables []*FloorPlanTable `json:"tables,omitempty" gorm:"-"`
+}
+
+func (FloorPlan) TableName() string {
+	return common.POSTGRES_TABLE_NAME_FLOOR_PLANS
+}
+
+// FloorPlanArea is a named area of a zone, drawn as a rectangle on the plan
+type FloorPlanArea struct {
+	ID          int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	FloorPlanID int        `json:"floor_plan_id" gorm:"column:floor_plan_id"`
+	ZoneID      int        `json:"zone_id" gorm:"column:zone_id"`
+	Name        string     `json:"name" gorm:"column:name"`
+	X           float64    `json:"x" gorm:"column:x"`
+	Y           float64    `json:"y" gorm:"column:y"`
+	Width       float64    `json:"width" gorm:"column:width"`
+	Height      float64    `json:"height" gorm:"column:height"`
+	Color       *string    `json:"color,omitempty" gorm:"column:color"`
+	CreatedAt   *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (FloorPlanArea) TableName() string {
+	return common.POSTGRES_TABLE_NAME_FLOOR_PLAN_AREAS
+}
+
+// TableLayout is where a table sits on a plan. Rotation is in degrees clockwise around its center.
+type TableLayout struct {
+	TableID     int        `json:"table_id" gorm:"column:table_id;primaryKey"`
+	FloorPlanID int        `json:"floor_plan_id" gorm:"column:floor_plan_id"`
+	X           float64    `json:"x" gorm:"column:x"`
+	Y           float64    `json:"y" gorm:"column:y"`
+	Width       float64    `json:"width" gorm:"column:width"`
+	Height      float64    `json:"height" gorm:"column:height"`
+	Rotation    float64    `json:"rotation" gorm:"column:rotation"`
+	Shape       string     `json:"shape" gorm:"column:shape"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (TableLayout) TableName() string {
+	return common.POSTGRES_TABLE_NAME_TABLE_LAYOUTS
+}
+
+// FloorPlanTable is a placed table with its live status and order data
+type FloorPlanTable struct {
+	TableLayout
+	Table *TableWithOrderData `json:"table"`
+}
+
+type ListFloorPlansRequest struct {
+	RestaurantID *int `form:"restaurant_id"`
+}
+
+type CreateFloorPlanRequest struct {
+	RestaurantID *int    `json:"restaurant_id"`
+	Name         string  `json:"name" binding:"required,max=100"`
+	Width        float64 `json:"width" binding:"required,gt=0"`
+	Height       float64 `json:"height" binding:"required,gt=0"`
+	DisplayOrder *int    `json:"display_order"`
+}
+
+// SaveFloorPlanLayoutRequest saves the layout of a plan. Areas, when sent, replace the plan's
+// areas. Tables are placed where sent, and tables on another plan move to this one; tables not
+// sent stay where they are, and only RemovedTableIDs are taken off the plan.
+type SaveFloorPlanLayoutRequest struct {
+	Name            *string              `json:"name" binding:"omitempty,max=100"`
+	Width           *float64             `json:"width" binding:"omitempty,gt=0"`
+	Height          *float64             `json:"height" binding:"omitempty,gt=0"`
+	DisplayOrder    *int                 `json:"display_order"`
+	Areas           []FloorPlanAreaInput `json:"areas" binding:"dive"`
+	Tables          []TableLayoutInput   `json:"tables" binding:"dive"`
+	RemovedTableIDs []int                `json:"removed_table_ids" binding:"dive,min=1"`
+}
+
+type FloorPlanAreaInput struct {
+	ZoneID int     `json:"zone_id" binding:"required,min=1"`
+	Name   string  `json:"name" binding:"required,max=100"`
+	X      float64 `json:"x" binding:"min=0"`
+	Y      float64 `json:"y" binding:"min=0"`
+	Width  float64 `json:"width" binding:"required,gt=0"`
+	Height float64 `json:"height" binding:"required,gt=0"`
+	Color  *string `json:"color" binding:"omitempty,max=20"`
+}
+
+type TableLayoutInput struct {
+	TableID  int     `json:"table_id" binding:"required,min=1"`
+	X        float64 `json:"x" binding:"min=0"`
+	Y        float64 `json:"y" binding:"min=0"`
+	Width    float64 `json:"width" binding:"required,gt=0"`
+	Height   float64 `json:"height" binding:"required,gt=0"`
+	Rotation float64 `json:"rotation" binding:"min=0,lt=360"`
+	Shape    string  `json:"shape" binding:"omitempty,oneof=rectangle circle"`
+}
+
+type FloorPlanIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
@@ -0,0 +1,151 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	MovementOrderConsumption = "order_consumption"
+	MovementOrderRestore     = "order_restore"
+	MovementRestock          = "restock"
+	MovementAdjustment       = "adjustment"
+	MovementWaste            = "waste"
+)
+
+type Ingredient struct {
+	ID                int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID      int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	Name              string     `json:"name" gorm:"column:name"`
+	Unit              string     `json:"unit" gorm:"column:unit"`
+	StockQuantity     float64    `json:"stock_quantity" gorm:"column:stock_quantity"`
+	LowStockThreshold *float64   `json:"low_stock_threshold,omitempty" gorm:"column:low_stock_threshold"`
+	Status            string     `json:"status" gorm:"column:status"`
+	CreatedAt         *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (Ingredient) TableName() string {
+	return common.POSTGRES_TABLE_NAME_INGREDIENTS
+}
+
+type CreateIngredientRequest struct {
+	RestaurantID      *int     `json:"restaurant_id"`
+	Name              string   `json:"name" binding:"required,max=80"`
+	Unit              string   `json:"unit" binding:"required,oneof=g kg ml l pcs"`
+	StockQuantity     float64  `json:"stock_quantity" binding:"min=0"`
+	LowStockThreshold *float64 `json:"low_stock_threshold" binding:"omitempty,min=0"`
+	Status            string   `json:"status" binding:"required,oneof=active inactive"`
+}
+
+type UpdateIngredientRequest struct {
+	Name              *string  `json:"name" binding:"omitempty,max=80"`
+	Unit              *string  `json:"unit" binding:"omitempty,oneof=g kg ml l pcs"`
+	LowStockThreshold *float64 `json:"low_stock_threshold" binding:"omitempty,min=0"`
+	Status            *string  `json:"status" binding:"omitempty,oneof=active inactive"`
+}
+
+type ListIngredientRequest struct {
+	BaseRequestParamsUri
+	Search   *string `form:"search"`
+	Status   *string `form:"status"`
+	LowStock *bool   `form:"low_stock"`
+}
+
+type IngredientIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+type IngredientResponse struct {
+	ID                int      `json:"id"`
+	Name              string   `json:"name"`
+	Unit              string   `json:"unit"`
+	StockQuantity     float64  `json:"stock_quantity"`
+	LowStockThreshold *float64 `json:"low_stock_threshold,omitempty"`
+	IsLowStock        bool     `json:"is_low_stock"`
+	Status            string   `json:"status"`
+	UsedBy            int      `json:"used_by"`
+}
+
+type RecipeLine struct {
+	ID               int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	IngredientID     int        `json:"ingredient_id" gorm:"column:ingredient_id"`
+	MenuItemID       *int       `json:"menu_item_id,omitempty" gorm:"column:menu_item_id"`
+	ModifierOptionID *int       `json:"modifier_option_id,omitempty" gorm:"column:modifier_option_id"`
+	Quantity         float64    `json:"quantity" gorm:"column:quantity"`
+	CreatedAt        *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (RecipeLine) TableName() string {
+	return common.POSTGRES_TABLE_NAME_RECIPE_LINES
+}
+
+type RecipeLineRequest struct {
+	IngredientID int     `json:"ingredient_id" binding:"required,min=1"`
+	Quantity     float64 `json:"quantity" binding:"required,gt=0"`
+}
+
+type UpdateRecipeRequest struct {
+	Lines []RecipeLineRequest `json:"lines" binding:"dive"`
+}
+
+type RecipeLineResponse struct {
+	IngredientID   int     `json:"ingredient_id"`
+	IngredientName string  `json:"ingredient_name"`
+	Unit           string  `json:"unit"`
+	Quantity       float64 `json:"quantity"`
+	InStock        bool    `json:"in_stock"`
+}
+
+type StockMovement struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	IngredientID int        `json:"ingredient_id" gorm:"column:ingredient_id"`
+	MovementType string     `json:"movement_type" gorm:"column:movement_type"`
+	Quantity     float64    `json:"quantity" gorm:"column:quantity"`
+	StockAfter   float64    `json:"stock_after" gorm:"column:stock_after"`
+	OrderID      *int       `json:"order_id,omitempty" gorm:"column:order_id"`
+	Reason       *string    `json:"reason,omitempty" gorm:"column:reason"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (StockMovement) TableName() string {
+	return common.POSTGRES_TABLE_NAME_STOCK_MOVEMENTS
+}
+
+// CreateStockMovementRequest records a manual movement. For restock and waste Quantity is the amount
+// added or thrown away; for adjustment it is the physically counted stock level.
+type CreateStockMovementRequest struct {
+	MovementType string  `json:"movement_type" binding:"required,oneof=restock adjustment waste"`
+	Quantity     float64 `json:"quantity" binding:"min=0"`
+	Reason       *string `json:"reason"`
+}
+
+type ListStockMovementRequest struct {
+	BaseRequestParamsUri
+	IngredientID *int       `form:"ingredient_id"`
+	MovementType *string    `form:"movement_type"`
+	From         *time.Time `form:"from" time_format:"2006-01-02"`
+	To           *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type IngredientUsageReportRequest struct {
+	From *time.Time `form:"from" time_format:"2006-01-02"`
+	To   *time.Time `form:"to" time_format:"2006-01-02"`
+}
+
+type IngredientUsageResponse struct {
+	IngredientID     int     `json:"ingredient_id" gorm:"column:ingredient_id"`
+	Name             string  `json:"name" gorm:"column:name"`
+	Unit             string  `json:"unit" gorm:"column:unit"`
+	TheoreticalUsage float64 `json:"theoretical_usage" gorm:"column:theoretical_usage"`
+	ActualUsage      float64 `json:"actual_usage" gorm:"column:actual_usage"`
+	Waste            float64 `json:"waste" gorm:"column:waste"`
+	Variance         float64 `json:"variance" gorm:"-"`
+	VariancePercent  float64 `json:"variance_percent" gorm:"-"`
+}
+
+type IngredientUsageReport struct {
+	From        string                     `json:"from"`
+	To          string                     `json:"to"`
+	Ingredients []*IngredientUsageResponse `json:"ingredients"`
+}
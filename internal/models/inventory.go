@@ -0,0 +1,56 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	StockEntityMenuItem       = "menu_item"
+	StockEntityModifierOption = "modifier_option"
+
+	StockAlertLowStock   = "low_stock"
+	StockAlertOutOfStock = "out_of_stock"
+)
+
+type StockAlert struct {
+	ID                int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	EntityType        string     `json:"entity_type" gorm:"column:entity_type"`
+	EntityID          int        `json:"entity_id" gorm:"column:entity_id"`
+	AlertType         string     `json:"alert_type" gorm:"column:alert_type"`
+	StockQuantity     int        `json:"stock_quantity" gorm:"column:stock_quantity"`
+	LowStockThreshold *int       `json:"low_stock_threshold,omitempty" gorm:"column:low_stock_threshold"`
+	IsResolved        bool       `json:"is_resolved" gorm:"column:is_resolved"`
+	CreatedAt         *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty" gorm:"column:resolved_at"`
+}
+
+func (StockAlert) TableName() string {
+	return common.POSTGRES_TABLE_NAME_STOCK_ALERTS
+}
+
+type UpdateStockRequest struct {
+	TrackStock        bool `json:"track_stock"`
+	StockQuantity     *int `json:"stock_quantity" binding:"omitempty,min=0"`
+	LowStockThreshold *int `json:"low_stock_threshold" binding:"omitempty,min=0"`
+}
+
+type ListStockAlertRequest struct {
+	BaseRequestParamsUri
+	EntityType *string `form:"entity_type"`
+	AlertType  *string `form:"alert_type"`
+	Resolved   *bool   `form:"resolved"`
+}
+
+type StockAlertResponse struct {
+	ID                int        `json:"id"`
+	EntityType        string     `json:"entity_type"`
+	EntityID          int        `json:"entity_id"`
+	EntityName        string     `json:"entity_name"`
+	AlertType         string     `json:"alert_type"`
+	StockQuantity     int        `json:"stock_quantity"`
+	LowStockThreshold *int       `json:"low_stock_threshold,omitempty"`
+	IsResolved        bool       `json:"is_resolved"`
+	CreatedAt         *time.Time `json:"created_at,omitempty"`
+	ResolvedAt        *time.Time `json:"resolved_at,omitempty"`
+}
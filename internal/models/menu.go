@@ -3,6 +3,8 @@ package models
 import (
 	"app-noti/common"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type MenuCategory struct {
@@ -12,6 +14,8 @@ type MenuCategory struct {
 	Description  *string    `json:"description,omitempty" gorm:"column:description"`
 	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
 	Status       string     `json:"status" gorm:"column:status"`
+	IsDeleted    bool       `json:"is_deleted" gorm:"column:is_deleted"`
+	DeletedAt    *time.Time `json:"deleted_at,omitempty" gorm:"column:deleted_at"`
 	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
 	UpdatedAt    *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
 }
@@ -43,8 +47,11 @@ type ListMenuCategoryRequest struct {
 
 type ListMenuRequest struct {
 	BaseRequestParamsUri
+	DietaryFilter
+	PhotoSizeRequest
 	Search   *string `form:"search"`
 	Category *string `form:"category"`
+	Locale   string  `form:"-"`
 }
 
 type MenuCategoryParamsUri struct {
@@ -74,18 +81,26 @@ type UpdateMenuCategoryStatusRequest struct {
 }
 
 type MenuItem struct {
-	ID                int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	RestaurantID      int        `json:"restaurant_id" gorm:"column:restaurant_id"`
-	CategoryID        int        `json:"category_id" gorm:"column:category_id"`
-	Name              string     `json:"name" gorm:"column:name"`
-	Description       *string    `json:"description,omitempty" gorm:"column:description"`
-	Price             float64    `json:"price" gorm:"column:price"`
-	PrepTimeMinutes   int        `json:"prep_time_minutes" gorm:"column:prep_time_minutes"`
-	Status            string     `json:"status" gorm:"column:status"`
-	IsChefRecommended bool       `json:"is_chef_recommended" gorm:"column:is_chef_recommended"`
-	IsDeleted         bool       `json:"is_deleted" gorm:"column:is_deleted"`
-	CreatedAt         *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
-	UpdatedAt         *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+	ID                int             `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID      int             `json:"restaurant_id" gorm:"column:restaurant_id"`
+	CategoryID        int             `json:"category_id" gorm:"column:category_id"`
+	Name              string          `json:"name" gorm:"column:name"`
+	Description       *string         `json:"description,omitempty" gorm:"column:description"`
+	Price             float64         `json:"price" gorm:"column:price"`
+	PrepTimeMinutes   int             `json:"prep_time_minutes" gorm:"column:prep_time_minutes"`
+	Status            string          `json:"status" gorm:"column:status"`
+	IsChefRecommended bool            `json:"is_chef_recommended" gorm:"column:is_chef_recommended"`
+	DisplayOrder      int             `json:"display_order" gorm:"column:display_order"`
+	StockQuantity     *int            `json:"stock_quantity" gorm:"column:stock_quantity"`
+	LowStockThreshold *int            `json:"low_stock_threshold" gorm:"column:low_stock_threshold"`
+	Allergens         pq.StringArray  `json:"allergens" gorm:"column:allergens;type:text[]"`
+	DietaryTags       pq.StringArray  `json:"dietary_tags" gorm:"column:dietary_tags;type:text[]"`
+	SpicyLevel        *int            `json:"spicy_level" gorm:"column:spicy_level"`
+	Nutrition         *NutritionFacts `json:"nutrition" gorm:"column:nutrition;serializer:json"`
+	IsDeleted         bool            `json:"is_deleted" gorm:"column:is_deleted"`
+	DeletedAt         *time.Time      `json:"deleted_at,omitempty" gorm:"column:deleted_at"`
+	CreatedAt         *time.Time      `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt         *time.Time      `json:"updated_at,omitempty" gorm:"column:updated_at"`
 }
 
 func (MenuItem) TableName() string {
@@ -93,15 +108,18 @@ func (MenuItem) TableName() string {
 }
 
 type CreateMenuItemRequest struct {
-	CategoryID        int                             `json:"category_id" binding:"required"`
-	Name              string                          `json:"name" binding:"required,max=80"`
-	Description       *string                         `json:"description"`
-	Price             float64                         `json:"price" binding:"required,gt=0"`
-	PrepTimeMinutes   int                             `json:"preparation_time" binding:"min=0,max=240"`
-	Status            string                          `json:"status" binding:"required,oneof=available unavailable sold_out"`
-	IsChefRecommended bool                            `json:"chef_recommended"`
-	Images            []CreateMenuItemPhotoRequest    `json:"images"`
-	Modifiers         []CreateMenuItemModifierRequest `json:"modifiers"`
+	CategoryID        int     `json:"category_id" binding:"required"`
+	Name              string  `json:"name" binding:"required,max=80"`
+	Description       *string `json:"description"`
+	Price             float64 `json:"price" binding:"required,gt=0"`
+	PrepTimeMinutes   int     `json:"preparation_time" binding:"min=0,max=240"`
+	Status            string  `json:"status" binding:"required,oneof=available unavailable sold_out"`
+	IsChefRecommended bool    `json:"chef_recommended"`
+	StockQuantity     *int    `json:"stock_quantity" binding:"omitempty,min=0"`
+	LowStockThreshold *int    `json:"low_stock_threshold" binding:"omitempty,min=0"`
+	DietaryInfo
+	Images    []CreateMenuItemPhotoRequest    `json:"images"`
+	Modifiers []CreateMenuItemModifierRequest `json:"modifiers"`
 }
 
 type CreateMenuItemPhotoRequest struct {
@@ -114,19 +132,21 @@ type CreateMenuItemModifierRequest struct {
 }
 
 type UpdateMenuItemRequest struct {
-	CategoryID        *int                            `json:"category_id"`
-	Name              *string                         `json:"name" binding:"omitempty,max=80"`
-	Description       *string                         `json:"description"`
-	Price             *float64                        `json:"price" binding:"omitempty,gt=0"`
-	PrepTimeMinutes   *int                            `json:"preparation_time" binding:"omitempty,min=0,max=240"`
-	Status            *string                         `json:"status" binding:"omitempty,oneof=available unavailable sold_out"`
-	IsChefRecommended *bool                           `json:"chef_recommended"`
-	Images            []CreateMenuItemPhotoRequest    `json:"images"`
-	Modifiers         []CreateMenuItemModifierRequest `json:"modifiers"`
+	CategoryID        *int     `json:"category_id"`
+	Name              *string  `json:"name" binding:"omitempty,max=80"`
+	Description       *string  `json:"description"`
+	Price             *float64 `json:"price" binding:"omitempty,gt=0"`
+	PrepTimeMinutes   *int     `json:"preparation_time" binding:"omitempty,min=0,max=240"`
+	Status            *string  `json:"status" binding:"omitempty,oneof=available unavailable sold_out"`
+	IsChefRecommended *bool    `json:"chef_recommended"`
+	DietaryInfo
+	Images    []CreateMenuItemPhotoRequest    `json:"images"`
+	Modifiers []CreateMenuItemModifierRequest `json:"modifiers"`
 }
 
 type ListMenuItemRequest struct {
 	BaseRequestParamsUri
+	DietaryFilter
 	Search   *string `form:"search"`
 	Status   *string `form:"status"`
 	Category *string `form:"category"`
@@ -137,37 +157,60 @@ type MenuItemIDParamsUri struct {
 }
 
 type MenuItemResponse struct {
-	ID              int     `json:"id"`
-	Name            string  `json:"name"`
-	Category        string  `json:"category"`
-	Price           float64 `json:"price"`
-	Status          string  `json:"status"`
-	LastUpdate      string  `json:"last_update"`
-	ChefRecommended bool    `json:"chef_recommended"`
-	ImageURL        string  `json:"image_url,omitempty"`
-	Description     *string `json:"description,omitempty"`
-	PreparationTime int     `json:"preparation_time,omitempty"`
+	ID              int                  `json:"id"`
+	Name            string               `json:"name"`
+	Category        string               `json:"category"`
+	Price           float64              `json:"price"`
+	Status          string               `json:"status"`
+	LastUpdate      string               `json:"last_update"`
+	ChefRecommended bool                 `json:"chef_recommended"`
+	DisplayOrder    int                  `json:"display_order"`
+	ImageURL        string               `json:"image_url,omitempty"`
+	Description     *string              `json:"description,omitempty"`
+	PreparationTime int                  `json:"preparation_time,omitempty"`
+	StockQuantity   *int                 `json:"stock_quantity,omitempty"`
+	Allergens       []string             `json:"allergens"`
+	DietaryTags     []string             `json:"dietary_tags"`
+	SpicyLevel      *int                 `json:"spicy_level,omitempty"`
+	Nutrition       *NutritionFacts      `json:"nutrition,omitempty"`
+	Highlight       *MenuItemHighlight   `json:"highlight,omitempty"`
+	ModifierTree    []*ModifierGroupNode `json:"modifier_tree,omitempty"`
+}
+
+// MenuItemHighlight wraps the words that matched a search in <mark> tags
+type MenuItemHighlight struct {
+	Name        string  `json:"name"`
+	Description *string `json:"description,omitempty"`
 }
 
 type MenuItemDetailResponse struct {
-	ID              int                    `json:"id"`
-	Name            string                 `json:"name"`
-	Category        string                 `json:"category"`
-	Price           float64                `json:"price"`
-	Status          string                 `json:"status"`
-	LastUpdate      string                 `json:"last_update"`
-	ChefRecommended bool                   `json:"chef_recommended"`
-	ImageURL        string                 `json:"image_url,omitempty"`
-	Description     *string                `json:"description,omitempty"`
-	PreparationTime int                    `json:"preparation_time,omitempty"`
-	Images          []MenuItemPhotoRequest `json:"images,omitempty"`
-	Modifiers       []MenuItemModifier     `json:"modifiers,omitempty"`
+	ID                int                    `json:"id"`
+	Name              string                 `json:"name"`
+	Category          string                 `json:"category"`
+	Price             float64                `json:"price"`
+	Status            string                 `json:"status"`
+	LastUpdate        string                 `json:"last_update"`
+	ChefRecommended   bool                   `json:"chef_recommended"`
+	DisplayOrder      int                    `json:"display_order"`
+	ImageURL          string                 `json:"image_url,omitempty"`
+	Description       *string                `json:"description,omitempty"`
+	PreparationTime   int                    `json:"preparation_time,omitempty"`
+	StockQuantity     *int                   `json:"stock_quantity,omitempty"`
+	LowStockThreshold *int                   `json:"low_stock_threshold,omitempty"`
+	Allergens         []string               `json:"allergens"`
+	DietaryTags       []string               `json:"dietary_tags"`
+	SpicyLevel        *int                   `json:"spicy_level,omitempty"`
+	Nutrition         *NutritionFacts        `json:"nutrition,omitempty"`
+	Images            []MenuItemPhotoRequest `json:"images,omitempty"`
+	Modifiers         []MenuItemModifier     `json:"modifiers,omitempty"`
+	ModifierTree      []*ModifierGroupNode   `json:"modifier_tree,omitempty"`
 }
 
 type MenuItemPhotoRequest struct {
-	ID        string `json:"id,omitempty"`
-	URL       string `json:"url"`
-	IsPrimary bool   `json:"is_primary"`
+	ID        string            `json:"id,omitempty"`
+	URL       string            `json:"url"`
+	IsPrimary bool              `json:"is_primary"`
+	Variants  map[string]string `json:"variants,omitempty"`
 }
 
 type MenuItemModifier struct {
@@ -177,14 +220,29 @@ type MenuItemModifier struct {
 	Required        bool   `json:"required,omitempty"`
 	SelectionType   string `json:"selection_type,omitempty"`
 	OptionsPreview  string `json:"options_preview,omitempty"`
+	MinSelections   int    `json:"min_selections"`
+	MaxSelections   int    `json:"max_selections"`
+	DisplayOrder    int    `json:"display_order"`
+
+	Overrides *ModifierGroupOverrides `json:"overrides,omitempty"`
 }
 
 type MenuItemPhoto struct {
-	ID         int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	MenuItemID int        `json:"menu_item_id" gorm:"column:menu_item_id"`
-	Url        string     `json:"url" gorm:"column:url"`
-	IsPrimary  bool       `json:"is_primary" gorm:"column:is_primary"`
-	CreatedAt  *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	ID           int               `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	MenuItemID   int               `json:"menu_item_id" gorm:"column:menu_item_id"`
+	Url          string            `json:"url" gorm:"column:url"`
+	IsPrimary    bool              `json:"is_primary" gorm:"column:is_primary"`
+	DisplayOrder int               `json:"display_order" gorm:"column:display_order"`
+	Variants     map[string]string `json:"variants,omitempty" gorm:"column:variants;serializer:json"`
+	CreatedAt    *time.Time        `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+// SizedURL is the url of the photo's variant for size, or the original when it has none
+func (p *MenuItemPhoto) SizedURL(size string) string {
+	if url, ok := p.Variants[size]; ok {
+		return url
+	}
+	return p.Url
 }
 
 func (MenuItemPhoto) TableName() string {
@@ -192,6 +250,10 @@ func (MenuItemPhoto) TableName() string {
 }
 
 type MenuItemPhotoIDParamsUri struct {
-	MenuItemID int `uri:"menu_item_id" binding:"required,min=1"`
-	ID         int `uri:"id" binding:"required,min=1"`
+	MenuItemID int `uri:"id" binding:"required,min=1"`
+	ID         int `uri:"photoId" binding:"required,min=1"`
+}
+
+type DeleteMenuCategoryRequest struct {
+	MoveToCategoryID *int `form:"move_to_category_id" binding:"omitempty,min=1"`
 }
@@ -0,0 +1,46 @@
+package models
+
+import "time"
+
+// MenuDocumentResponse is the whole guest menu in one payload: active categories in display order,
+// each with its items, photos and modifier trees, plus the combos on offer
+type MenuDocumentResponse struct {
+	RestaurantID int                     `json:"restaurant_id"`
+	Locale       string                  `json:"locale"`
+	UpdatedAt    *time.Time              `json:"updated_at,omitempty"`
+	Categories   []*MenuDocumentCategory `json:"categories"`
+	Combos       []*ComboResponse        `json:"combos"`
+}
+
+type MenuDocumentCategory struct {
+	ID           int                 `json:"id"`
+	Name         string              `json:"name"`
+	Description  *string             `json:"description,omitempty"`
+	DisplayOrder int                 `json:"display_order"`
+	Items        []*MenuDocumentItem `json:"items"`
+}
+
+type MenuDocumentItem struct {
+	ID              int                  `json:"id"`
+	Name            string               `json:"name"`
+	Description     *string              `json:"description,omitempty"`
+	Price           float64              `json:"price"`
+	Status          string               `json:"status"`
+	IsAvailable     bool                 `json:"is_available"`
+	ChefRecommended bool                 `json:"chef_recommended"`
+	PreparationTime int                  `json:"preparation_time"`
+	Allergens       []string             `json:"allergens"`
+	DietaryTags     []string             `json:"dietary_tags"`
+	SpicyLevel      *int                 `json:"spicy_level,omitempty"`
+	Nutrition       *NutritionFacts      `json:"nutrition,omitempty"`
+	ImageURL        string               `json:"image_url,omitempty"`
+	Photos          []*MenuDocumentPhoto `json:"photos"`
+	ModifierGroups  []*ModifierGroupNode `json:"modifier_groups"`
+}
+
+type MenuDocumentPhoto struct {
+	ID        int               `json:"id"`
+	URL       string            `json:"url"`
+	IsPrimary bool              `json:"is_primary"`
+	Variants  map[string]string `json:"variants,omitempty"`
+}
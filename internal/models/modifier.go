@@ -3,6 +3,8 @@ package models
 import (
 	"app-noti/common"
 	"time"
+
+	"github.com/lib/pq"
 )
 
 type ModifierGroup struct {
@@ -15,6 +17,8 @@ type ModifierGroup struct {
 	MaxSelections int        `json:"max_selections" gorm:"column:max_selections"`
 	DisplayOrder  int        `json:"display_order" gorm:"column:display_order"`
 	Status        string     `json:"status" gorm:"column:status"`
+	IsDeleted     bool       `json:"is_deleted" gorm:"column:is_deleted"`
+	DeletedAt     *time.Time `json:"deleted_at,omitempty" gorm:"column:deleted_at"`
 	CreatedAt     *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
 	UpdatedAt     *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
 }
@@ -55,12 +59,19 @@ type ModifierGroupIDParamsUri struct {
 }
 
 type ModifierOption struct {
-	ID              int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	GroupID         int        `json:"group_id" gorm:"column:group_id"`
-	Name            string     `json:"name" gorm:"column:name"`
-	PriceAdjustment float64    `json:"price_adjustment" gorm:"column:price_adjustment"`
-	Status          string     `json:"status" gorm:"column:status"`
-	CreatedAt       *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	ID                int             `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	GroupID           int             `json:"group_id" gorm:"column:group_id"`
+	Name              string          `json:"name" gorm:"column:name"`
+	PriceAdjustment   float64         `json:"price_adjustment" gorm:"column:price_adjustment"`
+	Status            string          `json:"status" gorm:"column:status"`
+	DisplayOrder      int             `json:"display_order" gorm:"column:display_order"`
+	StockQuantity     *int            `json:"stock_quantity" gorm:"column:stock_quantity"`
+	LowStockThreshold *int            `json:"low_stock_threshold" gorm:"column:low_stock_threshold"`
+	Allergens         pq.StringArray  `json:"allergens" gorm:"column:allergens;type:text[]"`
+	DietaryTags       pq.StringArray  `json:"dietary_tags" gorm:"column:dietary_tags;type:text[]"`
+	SpicyLevel        *int            `json:"spicy_level" gorm:"column:spicy_level"`
+	Nutrition         *NutritionFacts `json:"nutrition" gorm:"column:nutrition;serializer:json"`
+	CreatedAt         *time.Time      `json:"created_at,omitempty" gorm:"column:created_at"`
 }
 
 func (ModifierOption) TableName() string {
@@ -71,12 +82,14 @@ type CreateModifierOptionRequest struct {
 	Name            string  `json:"name" binding:"required,max=80"`
 	PriceAdjustment float64 `json:"price_adjustment" binding:"min=0"`
 	Status          string  `json:"status" binding:"required,oneof=active inactive"`
+	DietaryInfo
 }
 
 type UpdateModifierOptionRequest struct {
 	Name            *string  `json:"name" binding:"max=80"`
 	PriceAdjustment *float64 `json:"price_adjustment" binding:"min=0"`
 	Status          *string  `json:"status" binding:"oneof=active inactive"`
+	DietaryInfo
 }
 
 type ListModifierOptionRequest struct {
@@ -91,10 +104,15 @@ type ModifierOptionIDParamsUri struct {
 }
 
 type MenuItemModifierGroup struct {
-	ID         int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
-	MenuItemID int        `json:"menu_item_id" gorm:"column:menu_item_id"`
-	GroupID    int        `json:"group_id" gorm:"column:group_id"`
-	CreatedAt  *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	ID              int             `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	MenuItemID      int             `json:"menu_item_id" gorm:"column:menu_item_id"`
+	GroupID         int             `json:"group_id" gorm:"column:group_id"`
+	DisplayOrder    *int            `json:"display_order" gorm:"column:display_order"`
+	MinSelections   *int            `json:"min_selections" gorm:"column:min_selections"`
+	MaxSelections   *int            `json:"max_selections" gorm:"column:max_selections"`
+	HiddenOptionIDs pq.Int64Array   `json:"hidden_option_ids" gorm:"column:hidden_option_ids;type:integer[]"`
+	PriceOverrides  map[int]float64 `json:"price_overrides" gorm:"column:price_overrides;serializer:json"`
+	CreatedAt       *time.Time      `json:"created_at,omitempty" gorm:"column:created_at"`
 }
 
 func (MenuItemModifierGroup) TableName() string {
@@ -104,9 +122,88 @@ func (MenuItemModifierGroup) TableName() string {
 type AssignModifierToMenuItemRequest struct {
 	MenuItemID int `json:"menu_item_id" binding:"required"`
 	GroupID    int `json:"group_id" binding:"required"`
+	ModifierGroupOverrides
 }
 
 type DeleteMenuItemModifierGroupUri struct {
 	MenuItemID int `uri:"id" binding:"required,min=1"`
 	GroupID    int `uri:"groupId" binding:"required,min=1"`
 }
+
+type MenuItemModifierGroupUri struct {
+	MenuItemID int `uri:"id" binding:"required,min=1"`
+	GroupID    int `uri:"groupId" binding:"required,min=1"`
+}
+
+// ModifierGroupOverrides changes how a shared group behaves on one item. A nil field keeps the group's own value.
+type ModifierGroupOverrides struct {
+	DisplayOrder    *int            `json:"display_order" binding:"omitempty,min=0"`
+	MinSelections   *int            `json:"min_selections" binding:"omitempty,min=0"`
+	MaxSelections   *int            `json:"max_selections" binding:"omitempty,min=0"`
+	HiddenOptionIDs []int           `json:"hidden_option_ids" binding:"omitempty,dive,min=1"`
+	PriceOverrides  map[int]float64 `json:"price_overrides" binding:"omitempty,dive,min=0"`
+}
+
+// UpdateMenuItemModifierGroupRequest replaces every override of the assignment
+type UpdateMenuItemModifierGroupRequest struct {
+	ModifierGroupOverrides
+}
+
+type ModifierOptionChildGroup struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	OptionID     int        `json:"option_id" gorm:"column:option_id"`
+	GroupID      int        `json:"group_id" gorm:"column:group_id"`
+	DisplayOrder int        `json:"display_order" gorm:"column:display_order"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (ModifierOptionChildGroup) TableName() string {
+	return common.POSTGRES_TABLE_NAME_MODIFIER_CHILD_GROUPS
+}
+
+type AttachChildGroupRequest struct {
+	GroupID      int `json:"group_id" binding:"required,min=1"`
+	DisplayOrder int `json:"display_order" binding:"min=0"`
+}
+
+type DetachChildGroupUri struct {
+	OptionID int `uri:"id" binding:"required,min=1"`
+	GroupID  int `uri:"groupId" binding:"required,min=1"`
+}
+
+// ModifierGroupNode is a modifier group with its options, each option carrying the groups it opens
+type ModifierGroupNode struct {
+	ID            int                   `json:"id"`
+	Name          string                `json:"name"`
+	SelectionType string                `json:"selection_type"`
+	IsRequired    bool                  `json:"is_required"`
+	MinSelections int                   `json:"min_selections"`
+	MaxSelections int                   `json:"max_selections"`
+	Options       []*ModifierOptionNode `json:"options"`
+}
+
+type ModifierOptionNode struct {
+	ID              int                  `json:"id"`
+	Name            string               `json:"name"`
+	PriceAdjustment float64              `json:"price_adjustment"`
+	IsAvailable     bool                 `json:"is_available"`
+	Allergens       []string             `json:"allergens,omitempty"`
+	DietaryTags     []string             `json:"dietary_tags,omitempty"`
+	Children        []*ModifierGroupNode `json:"children,omitempty"`
+}
+
+type DeleteModifierGroupRequest struct {
+	Detach bool `form:"detach"`
+}
+
+const (
+	DependentMenuItem       = "menu_item"
+	DependentModifierOption = "modifier_option"
+)
+
+// MenuDependent is a row that still points at a category or modifier group being deleted
+type MenuDependent struct {
+	Type string `json:"type"`
+	ID   int    `json:"id"`
+	Name string `json:"name"`
+}
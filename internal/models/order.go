@@ -0,0 +1,87 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+
+	"gorm.io/datatypes"
+)
+
+const (
+	OrderStatusPending    = "pending"
+	OrderStatusProcessing = "processing"
+	OrderStatusCancelled  = "cancelled"
+)
+
+type Order struct {
+	ID                  int            `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID        *int           `json:"restaurant_id,omitempty" gorm:"column:restaurant_id"`
+	TableID             int            `json:"table_id" gorm:"column:table_id"`
+	OrderNumber         string         `json:"order_number" gorm:"column:order_number"`
+	Status              string         `json:"status" gorm:"column:status"`
+	CustomerUserID      *string        `json:"customer_user_id,omitempty" gorm:"column:customer_user_id"`
+	Subtotal            float64        `json:"subtotal" gorm:"column:subtotal"`
+	Tax                 float64        `json:"tax" gorm:"column:tax"`
+	Discount            float64        `json:"discount" gorm:"column:discount"`
+	Total               float64        `json:"total" gorm:"column:total"`
+	Notes               *string        `json:"notes,omitempty" gorm:"column:notes"`
+	SpecialInstructions *string        `json:"special_instructions,omitempty" gorm:"column:special_instructions"`
+	Meta                datatypes.JSON `json:"meta,omitempty" gorm:"column:meta"`
+	StockConsumed       bool           `json:"stock_consumed" gorm:"column:stock_consumed"`
+	SourceTableID       *int           `json:"source_table_id,omitempty" gorm:"column:source_table_id"`
+	CreatedAt           *time.Time     `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt           *time.Time     `json:"updated_at,omitempty" gorm:"column:updated_at"`
+	AcceptedAt          *time.Time     `json:"accepted_at,omitempty" gorm:"column:accepted_at"`
+	PreparingAt         *time.Time     `json:"preparing_at,omitempty" gorm:"column:preparing_at"`
+	ReadyAt             *time.Time     `json:"ready_at,omitempty" gorm:"column:ready_at"`
+	ServedAt            *time.Time     `json:"served_at,omitempty" gorm:"column:served_at"`
+	CompletedAt         *time.Time     `json:"completed_at,omitempty" gorm:"column:completed_at"`
+	CancelledAt         *time.Time     `json:"cancelled_at,omitempty" gorm:"column:cancelled_at"`
+}
+
+func (Order) TableName() string {
+	return common.POSTGRES_TABLE_NAME_ORDERS
+}
+
+type OrderItem struct {
+	ID                  int            `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	OrderID             int            `json:"order_id" gorm:"column:order_id"`
+	MenuItemID          *int           `json:"menu_item_id,omitempty" gorm:"column:menu_item_id"`
+	ItemName            string         `json:"item_name" gorm:"column:item_name"`
+	ItemDescription     *string        `json:"item_description,omitempty" gorm:"column:item_description"`
+	Quantity            int            `json:"quantity" gorm:"column:quantity"`
+	UnitPrice           float64        `json:"unit_price" gorm:"column:unit_price"`
+	Subtotal            float64        `json:"subtotal" gorm:"column:subtotal"`
+	Status              string         `json:"status" gorm:"column:status"`
+	SpecialInstructions *string        `json:"special_instructions,omitempty" gorm:"column:special_instructions"`
+	Meta                datatypes.JSON `json:"meta,omitempty" gorm:"column:meta"`
+	CreatedAt           *time.Time     `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt           *time.Time     `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (OrderItem) TableName() string {
+	return common.POSTGRES_TABLE_NAME_ORDER_ITEMS
+}
+
+// OrderItemMeta is the shape of order_items.meta. Combo lines have no menu_item_id and list
+// the picked items in ComboChoices instead.
+type OrderItemMeta struct {
+	ModifierOptionIDs []int                  `json:"modifier_option_ids,omitempty"`
+	ComboID           *int                   `json:"combo_id,omitempty"`
+	ComboChoices      []OrderComboChoiceMeta `json:"combo_choices,omitempty"`
+}
+
+type OrderComboChoiceMeta struct {
+	SlotID            int   `json:"slot_id"`
+	MenuItemID        int   `json:"menu_item_id"`
+	ModifierOptionIDs []int `json:"modifier_option_ids,omitempty"`
+}
+
+type OrderIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// OrderActionRequest names the restaurant the admin works in; the order must belong to it
+type OrderActionRequest struct {
+	RestaurantID *int `form:"restaurant_id"`
+}
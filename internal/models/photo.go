@@ -0,0 +1,18 @@
+package models
+
+const (
+	PhotoSizeThumb = "thumb"
+	PhotoSizeCard  = "card"
+	PhotoSizeFull  = "full"
+)
+
+// PhotoSizeRequest picks which variant of a photo the menu returns as image_url. Without it the
+// original upload is returned.
+type PhotoSizeRequest struct {
+	Size string `form:"size" json:"size,omitempty" binding:"omitempty,oneof=thumb card full"`
+}
+
+type UploadedPhotoResponse struct {
+	URL      string            `json:"url"`
+	Variants map[string]string `json:"variants"`
+}
@@ -0,0 +1,53 @@
+package models
+
+// QuoteRequest prices a cart before it is ordered. Each line is either a menu item or a combo.
+type QuoteRequest struct {
+	Items []QuoteItemRequest `json:"items" binding:"required,min=1,dive"`
+}
+
+type QuoteItemRequest struct {
+	MenuItemID        *int                      `json:"menu_item_id" binding:"omitempty,min=1"`
+	ComboID           *int                      `json:"combo_id" binding:"omitempty,min=1"`
+	Quantity          int                       `json:"quantity" binding:"required,min=1"`
+	ModifierOptionIDs []int                     `json:"modifier_option_ids"`
+	Choices           []QuoteComboChoiceRequest `json:"choices" binding:"omitempty,dive"`
+}
+
+type QuoteComboChoiceRequest struct {
+	SlotID            int   `json:"slot_id" binding:"required,min=1"`
+	MenuItemID        int   `json:"menu_item_id" binding:"required,min=1"`
+	ModifierOptionIDs []int `json:"modifier_option_ids"`
+}
+
+type QuoteResponse struct {
+	Items    []*QuoteLineResponse `json:"items"`
+	Subtotal float64              `json:"subtotal"`
+	Total    float64              `json:"total"`
+}
+
+type QuoteLineResponse struct {
+	MenuItemID *int                   `json:"menu_item_id,omitempty"`
+	ComboID    *int                   `json:"combo_id,omitempty"`
+	Name       string                 `json:"name"`
+	Quantity   int                    `json:"quantity"`
+	BasePrice  float64                `json:"base_price"`
+	UnitPrice  float64                `json:"unit_price"`
+	Subtotal   float64                `json:"subtotal"`
+	Options    []*QuoteOptionResponse `json:"options,omitempty"`
+	Choices    []*QuoteChoiceResponse `json:"choices,omitempty"`
+}
+
+type QuoteOptionResponse struct {
+	ID              int     `json:"id"`
+	Name            string  `json:"name"`
+	PriceAdjustment float64 `json:"price_adjustment"`
+}
+
+type QuoteChoiceResponse struct {
+	SlotID     int                    `json:"slot_id"`
+	SlotName   string                 `json:"slot_name"`
+	MenuItemID int                    `json:"menu_item_id"`
+	Name       string                 `json:"name"`
+	Upcharge   float64                `json:"upcharge"`
+	Options    []*QuoteOptionResponse `json:"options,omitempty"`
+}
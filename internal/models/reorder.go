@@ -0,0 +1,12 @@
+package models
+
+// ReorderRequest is the full list of ids in their new order, first one on top
+type ReorderRequest struct {
+	RestaurantID *int  `json:"restaurant_id"`
+	IDs          []int `json:"ids" binding:"required,min=1,unique,dive,min=1"`
+}
+
+type ReorderResponse struct {
+	ID           int `json:"id"`
+	DisplayOrder int `json:"display_order"`
+}
@@ -0,0 +1,114 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	ReservationStatusBooked    = "booked"
+	ReservationStatusSeated    = "seated"
+	ReservationStatusCompleted = "completed"
+	ReservationStatusNoShow    = "no_show"
+	ReservationStatusCancelled = "cancelled"
+)
+
+type Reservation struct {
+	ID              int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID    int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	GuestName       string     `json:"guest_name" gorm:"column:guest_name"`
+	GuestPhone      string     `json:"guest_phone" gorm:"column:guest_phone"`
+	PartySize       int        `json:"party_size" gorm:"column:party_size"`
+	StartsAt        time.Time  `json:"starts_at" gorm:"column:starts_at"`
+	DurationMinutes int        `json:"duration_minutes" gorm:"column:duration_minutes"`
+	EndsAt          time.Time  `json:"ends_at" gorm:"column:ends_at;->"`
+	Status          string     `json:"status" gorm:"column:status"`
+	Notes           *string    `json:"notes,omitempty" gorm:"column:notes"`
+	SeatedAt        *time.Time `json:"seated_at,omitempty" gorm:"column:seated_at"`
+	CompletedAt     *time.Time `json:"completed_at,omitempty" gorm:"column:completed_at"`
+	CancelledAt     *time.Time `json:"cancelled_at,omitempty" gorm:"column:cancelled_at"`
+	CreatedAt       *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt       *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+
+	Tables []*ReservedTable `json:"tables" gorm:"-"`
+}
+
+func (Reservation) TableName() string {
+	return common.POSTGRES_TABLE_NAME_RESERVATIONS
+}
+
+// ReservationTable holds a table for a reservation over During, a tsrange. Only active rows
+// hold the table.
+type ReservationTable struct {
+	ID            int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ReservationID int        `json:"reservation_id" gorm:"column:reservation_id"`
+	TableID       int        `json:"table_id" gorm:"column:table_id"`
+	During        string     `json:"during" gorm:"column:during"`
+	IsActive      bool       `json:"is_active" gorm:"column:is_active"`
+	CreatedAt     *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (ReservationTable) TableName() string {
+	return common.POSTGRES_TABLE_NAME_RESERVATION_TABLES
+}
+
+type ReservedTable struct {
+	ID          int    `json:"id"`
+	TableNumber string `json:"table_number"`
+	Capacity    int    `json:"capacity"`
+	Location    string `json:"location"`
+}
+
+// TableReservationSummary is an upcoming reservation as shown on a table
+type TableReservationSummary struct {
+	ID        int       `json:"id"`
+	GuestName string    `json:"guest_name"`
+	PartySize int       `json:"party_size"`
+	StartsAt  time.Time `json:"starts_at"`
+	EndsAt    time.Time `json:"ends_at"`
+	Status    string    `json:"status"`
+}
+
+// CreateReservationRequest assigns TableIDs when given; otherwise the smallest free table that
+// fits the party, in Location when given
+type CreateReservationRequest struct {
+	RestaurantID    *int      `json:"restaurant_id"`
+	GuestName       string    `json:"guest_name" binding:"required,max=100"`
+	GuestPhone      string    `json:"guest_phone" binding:"required,max=20"`
+	PartySize       int       `json:"party_size" binding:"required,min=1"`
+	StartsAt        time.Time `json:"starts_at" binding:"required"`
+	DurationMinutes *int      `json:"duration_minutes" binding:"omitempty,min=15,max=720"`
+	TableIDs        []int     `json:"table_ids" binding:"omitempty,dive,min=1"`
+	Location        *string   `json:"location"`
+	Notes           *string   `json:"notes"`
+}
+
+// UpdateReservationRequest changes a booked reservation. Its tables are kept unless TableIDs or
+// Location is given, and are checked again against the new time and party size.
+type UpdateReservationRequest struct {
+	GuestName       *string    `json:"guest_name" binding:"omitempty,max=100"`
+	GuestPhone      *string    `json:"guest_phone" binding:"omitempty,max=20"`
+	PartySize       *int       `json:"party_size" binding:"omitempty,min=1"`
+	StartsAt        *time.Time `json:"starts_at"`
+	DurationMinutes *int       `json:"duration_minutes" binding:"omitempty,min=15,max=720"`
+	TableIDs        []int      `json:"table_ids" binding:"omitempty,dive,min=1"`
+	Location        *string    `json:"location"`
+	Notes           *string    `json:"notes"`
+}
+
+type UpdateReservationStatusRequest struct {
+	Status string `json:"status" binding:"required,oneof=seated completed no_show cancelled"`
+}
+
+type ListReservationRequest struct {
+	BaseRequestParamsUri
+	RestaurantID *int    `form:"restaurant_id"`
+	Date         *string `form:"date" binding:"omitempty,datetime=2006-01-02"`
+	Status       *string `form:"status"`
+	TableID      *int    `form:"table_id"`
+	Search       *string `form:"search"`
+}
+
+type ReservationIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
@@ -9,7 +9,9 @@ type ListTablesRequest struct {
 	BaseRequestParamsUri
 	Search *string `form:"search"`
 	Status *string `form:"status"`
+	// Zone is a zone name, in any case
 	Zone   *string `form:"zone"`
+	ZoneID *int    `form:"zone_id"`
 }
 
 type TableParamsUri struct {
@@ -21,6 +23,7 @@ type Table struct {
 	TableNumber      string     `json:"table_number" gorm:"column:table_number"`
 	RestaurantId     int        `json:"restaurant_id"`
 	Capacity         int        `json:"capacity" gorm:"column:capacity"`
+	ZoneID           *int       `json:"zone_id" gorm:"column:zone_id"`
 	Location         string     `json:"location" gorm:"column:location"`
 	Status           string     `json:"status" gorm:"column:status"`
 	QrToken          string     `json:"qr_token" gorm:"column:qr_token"`
@@ -30,9 +33,13 @@ type Table struct {
 	UpdatedAt        *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
 }
 
+// TableOrderData is the open bill of a table, with the rules of its zone
 type TableOrderData struct {
-	ActiveOrders int     `json:"active_orders"`
-	TotalBill    float64 `json:"total_bill"`
+	ActiveOrders          int      `json:"active_orders"`
+	TotalBill             float64  `json:"total_bill"`
+	ServiceCharge         float64  `json:"service_charge,omitempty"`
+	MinimumSpend          *float64 `json:"minimum_spend,omitempty"`
+	MinimumSpendShortfall float64  `json:"minimum_spend_shortfall,omitempty"`
 }
 
 type TableWithOrderData struct {
@@ -40,24 +47,32 @@ type TableWithOrderData struct {
 	RestaurantId     int             `json:"restaurant_id"`
 	TableNumber      string          `json:"table_number"`
 	Capacity         int             `json:"capacity"`
+	ZoneID           *int            `json:"zone_id"`
 	Location         string          `json:"location"`
 	Status           string          `json:"status"`
 	QrToken          string          `json:"qr_token" gorm:"column:qr_token"`
 	QrTokenCreatedAt *time.Time      `json:"qr_token_created_at" gorm:"column:qr_token_created_at"`
 	QrTokenExpiresAt *time.Time      `json:"qr_token_expires_at" gorm:"column:qr_token_expires_at"`
 	OrderData        *TableOrderData `json:"order_data,omitempty"`
+
+	UpcomingReservations []*TableReservationSummary `json:"upcoming_reservations,omitempty"`
+	Group                *TableGroupSummary         `json:"group,omitempty"`
 }
 
+// CreateTableRequest puts the table in ZoneID, or in the zone named Location, in any case
 type CreateTableRequest struct {
-	TableNumber string `json:"table_number" binding:"required"`
-	Capacity    int    `json:"capacity" binding:"required,min=1"`
-	Location    string `json:"location" binding:"required"`
-	Status      string `json:"status" binding:"required,oneof=active occupied inactive"`
+	RestaurantID *int    `json:"restaurant_id"`
+	TableNumber  string  `json:"table_number" binding:"required"`
+	Capacity     int     `json:"capacity" binding:"required,min=1"`
+	ZoneID       *int    `json:"zone_id" binding:"required_without=Location,omitempty,min=1"`
+	Location     *string `json:"location" binding:"required_without=ZoneID"`
+	Status       string  `json:"status" binding:"required,oneof=active occupied inactive"`
 }
 
 type UpdateTableRequest struct {
 	TableNumber *string `json:"table_number,omitempty"`
 	Capacity    *int    `json:"capacity,omitempty" binding:"omitempty,min=1"`
+	ZoneID      *int    `json:"zone_id,omitempty" binding:"omitempty,min=1"`
 	Location    *string `json:"location,omitempty"`
 	Status      *string `json:"status,omitempty" binding:"omitempty,oneof=active occupied inactive"`
 }
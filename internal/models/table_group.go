@@ -0,0 +1,76 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	TableGroupStatusActive = "active"
+	TableGroupStatusSplit  = "split"
+)
+
+// TableGroup is tables merged for one party. Its orders are all placed on SessionTableID.
+type TableGroup struct {
+	ID             int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID   int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	SessionTableID int        `json:"session_table_id" gorm:"column:session_table_id"`
+	Status         string     `json:"status" gorm:"column:status"`
+	SplitAt        *time.Time `json:"split_at,omitempty" gorm:"column:split_at"`
+	CreatedAt      *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt      *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+
+	Capacity  int             `json:"capacity" gorm:"-"`
+	Tables    []*GroupedTable `json:"tables" gorm:"-"`
+	OrderData *TableOrderData `json:"order_data,omitempty" gorm:"-"`
+}
+
+func (TableGroup) TableName() string {
+	return common.POSTGRES_TABLE_NAME_TABLE_GROUPS
+}
+
+// TableGroupMember puts a table in a group. Only active rows do.
+type TableGroupMember struct {
+	ID        int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	GroupID   int        `json:"group_id" gorm:"column:group_id"`
+	TableID   int        `json:"table_id" gorm:"column:table_id"`
+	IsActive  bool       `json:"is_active" gorm:"column:is_active"`
+	CreatedAt *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (TableGroupMember) TableName() string {
+	return common.POSTGRES_TABLE_NAME_TABLE_GROUP_MEMBERS
+}
+
+type GroupedTable struct {
+	ID          int    `json:"id"`
+	TableNumber string `json:"table_number"`
+	Capacity    int    `json:"capacity"`
+	Location    string `json:"location"`
+	Status      string `json:"status"`
+}
+
+// TableGroupSummary is the group a table is merged into, as shown on the table
+type TableGroupSummary struct {
+	ID             int   `json:"id"`
+	SessionTableID int   `json:"session_table_id"`
+	TableIDs       []int `json:"table_ids"`
+	Capacity       int   `json:"capacity"`
+}
+
+// MergeTablesRequest merges TableIDs into a group that uses the session of SessionTableID,
+// or of the first table when it is not given
+type MergeTablesRequest struct {
+	TableIDs       []int `json:"table_ids" binding:"required,min=2,dive,min=1"`
+	SessionTableID *int  `json:"session_table_id" binding:"omitempty,min=1"`
+}
+
+type ListTableGroupsRequest struct {
+	BaseRequestParamsUri
+	RestaurantID *int    `form:"restaurant_id"`
+	Status       *string `form:"status" binding:"omitempty,oneof=active split all"`
+}
+
+type TableGroupIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
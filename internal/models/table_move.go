@@ -0,0 +1,42 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+// TableMove records open orders moving from one table to another
+type TableMove struct {
+	ID           int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	FromTableID  int        `json:"from_table_id" gorm:"column:from_table_id"`
+	ToTableID    int        `json:"to_table_id" gorm:"column:to_table_id"`
+	OrderIDs     []int      `json:"order_ids" gorm:"column:order_ids;serializer:json"`
+	WholeSession bool       `json:"whole_session" gorm:"column:whole_session"`
+	Merged       bool       `json:"merged" gorm:"column:merged"`
+	Reason       *string    `json:"reason,omitempty" gorm:"column:reason"`
+	CreatedAt    *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+}
+
+func (TableMove) TableName() string {
+	return common.POSTGRES_TABLE_NAME_TABLE_MOVES
+}
+
+// MoveTableRequest moves OrderIDs, or without them every open order and the table itself.
+// An occupied target is refused unless Merge is set; the orders then join its session.
+type MoveTableRequest struct {
+	ToTableID int     `json:"to_table_id" binding:"required,min=1"`
+	OrderIDs  []int   `json:"order_ids" binding:"omitempty,dive,min=1"`
+	Merge     bool    `json:"merge"`
+	Reason    *string `json:"reason"`
+}
+
+type MoveTableResponse struct {
+	Move      *TableMove          `json:"move"`
+	FromTable *TableWithOrderData `json:"from_table"`
+	ToTable   *TableWithOrderData `json:"to_table"`
+}
+
+type ListTableMovesRequest struct {
+	BaseRequestParamsUri
+}
@@ -0,0 +1,59 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	TranslationEntityCategory       = "category"
+	TranslationEntityMenuItem       = "menu_item"
+	TranslationEntityModifierGroup  = "modifier_group"
+	TranslationEntityModifierOption = "modifier_option"
+	TranslationEntityCombo          = "combo"
+)
+
+type MenuTranslation struct {
+	ID          int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	EntityType  string     `json:"entity_type" gorm:"column:entity_type"`
+	EntityID    int        `json:"entity_id" gorm:"column:entity_id"`
+	Locale      string     `json:"locale" gorm:"column:locale"`
+	Name        string     `json:"name" gorm:"column:name"`
+	Description *string    `json:"description,omitempty" gorm:"column:description"`
+	CreatedAt   *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt   *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (MenuTranslation) TableName() string {
+	return common.POSTGRES_TABLE_NAME_MENU_TRANSLATIONS
+}
+
+type TranslationParamsUri struct {
+	EntityType string `uri:"entity_type" binding:"required,oneof=category menu_item modifier_group modifier_option combo"`
+	EntityID   int    `uri:"entity_id" binding:"required,min=1"`
+	Locale     string `uri:"locale" binding:"required"`
+}
+
+type UpsertTranslationRequest struct {
+	Name        string  `json:"name" binding:"required,max=120"`
+	Description *string `json:"description"`
+}
+
+type ListTranslationRequest struct {
+	BaseRequestParamsUri
+	EntityType *string `form:"entity_type" binding:"omitempty,oneof=category menu_item modifier_group modifier_option combo"`
+	EntityID   *int    `form:"entity_id"`
+	Locale     *string `form:"locale"`
+}
+
+type ListMissingTranslationRequest struct {
+	BaseRequestParamsUri
+	Locale     string  `form:"locale" binding:"required"`
+	EntityType *string `form:"entity_type" binding:"omitempty,oneof=category menu_item modifier_group modifier_option combo"`
+}
+
+type MissingTranslationResponse struct {
+	EntityType string `json:"entity_type" gorm:"column:entity_type"`
+	EntityID   int    `json:"entity_id" gorm:"column:entity_id"`
+	Name       string `json:"name" gorm:"column:name"`
+}
@@ -0,0 +1,20 @@
+package models
+
+import "time"
+
+type ListDeletedMenuItemRequest struct {
+	BaseRequestParamsUri
+	RestaurantID *int    `form:"restaurant_id"`
+	Search       *string `form:"search"`
+}
+
+// DeletedMenuItemResponse is one row of the trash. PurgeAt is when the purge job removes it for good.
+type DeletedMenuItemResponse struct {
+	ID        int        `json:"id"`
+	Name      string     `json:"name"`
+	Category  string     `json:"category"`
+	Price     float64    `json:"price"`
+	ImageURL  string     `json:"image_url,omitempty"`
+	DeletedAt *time.Time `json:"deleted_at"`
+	PurgeAt   *time.Time `json:"purge_at"`
+}
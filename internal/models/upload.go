@@ -0,0 +1,52 @@
+package models
+
+import (
+	"app-noti/common"
+	"app-noti/pkg/storage"
+	"time"
+)
+
+const (
+	UploadStatusPending   = "pending"
+	UploadStatusConfirmed = "confirmed"
+)
+
+type Upload struct {
+	ID          int               `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	ObjectKey   string            `json:"key" gorm:"column:object_key"`
+	ContentType string            `json:"content_type" gorm:"column:content_type"`
+	SizeBytes   int64             `json:"size" gorm:"column:size_bytes"`
+	Status      string            `json:"status" gorm:"column:status"`
+	URL         string            `json:"url" gorm:"column:url"`
+	Variants    map[string]string `json:"variants,omitempty" gorm:"column:variants;serializer:json"`
+	ExpiresAt   *time.Time        `json:"expires_at,omitempty" gorm:"column:expires_at"`
+	ConfirmedAt *time.Time        `json:"confirmed_at,omitempty" gorm:"column:confirmed_at"`
+	CreatedAt   *time.Time        `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt   *time.Time        `json:"updated_at,omitempty" gorm:"column:updated_at"`
+}
+
+func (Upload) TableName() string {
+	return common.POSTGRES_TABLE_NAME_UPLOADS
+}
+
+type PresignUploadRequest struct {
+	ContentType string `json:"content_type" binding:"required,oneof=image/jpeg image/png"`
+	Size        int64  `json:"size" binding:"required,min=1"`
+}
+
+type PresignUploadResponse struct {
+	ID        int                    `json:"id"`
+	Key       string                 `json:"key"`
+	Upload    *storage.SignedRequest `json:"upload"`
+	MaxSize   int64                  `json:"max_size"`
+	ExpiresAt time.Time              `json:"expires_at"`
+}
+
+type UploadIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+// ConfirmUploadRequest with Process set stores the upload as photo variants, like POST /upload
+type ConfirmUploadRequest struct {
+	Process bool `json:"process"`
+}
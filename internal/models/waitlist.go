@@ -0,0 +1,92 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+const (
+	WaitlistStatusWaiting  = "waiting"
+	WaitlistStatusNotified = "notified"
+	WaitlistStatusSeated   = "seated"
+	WaitlistStatusRemoved  = "removed"
+)
+
+type WaitlistEntry struct {
+	ID                int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID      int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	GuestName         string     `json:"guest_name" gorm:"column:guest_name"`
+	GuestPhone        string     `json:"guest_phone" gorm:"column:guest_phone"`
+	PartySize         int        `json:"party_size" gorm:"column:party_size"`
+	QuotedWaitMinutes int        `json:"quoted_wait_minutes" gorm:"column:quoted_wait_minutes"`
+	Status            string     `json:"status" gorm:"column:status"`
+	Token             string     `json:"-" gorm:"column:token"`
+	TableID           *int       `json:"table_id,omitempty" gorm:"column:table_id"`
+	Notes             *string    `json:"notes,omitempty" gorm:"column:notes"`
+	NotifiedAt        *time.Time `json:"notified_at,omitempty" gorm:"column:notified_at"`
+	SeatedAt          *time.Time `json:"seated_at,omitempty" gorm:"column:seated_at"`
+	RemovedAt         *time.Time `json:"removed_at,omitempty" gorm:"column:removed_at"`
+	CreatedAt         *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt         *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+
+	// Position and EstimatedWaitMinutes are only set while the party is waiting or notified
+	Position             *int   `json:"position,omitempty" gorm:"-"`
+	EstimatedWaitMinutes *int   `json:"estimated_wait_minutes,omitempty" gorm:"-"`
+	StatusURL            string `json:"status_url" gorm:"-"`
+}
+
+func (WaitlistEntry) TableName() string {
+	return common.POSTGRES_TABLE_NAME_WAITLIST_ENTRIES
+}
+
+// WaitlistStatusResponse is what a guest sees on the status link
+type WaitlistStatusResponse struct {
+	GuestName            string     `json:"guest_name"`
+	PartySize            int        `json:"party_size"`
+	Status               string     `json:"status"`
+	Position             *int       `json:"position,omitempty"`
+	QuotedWaitMinutes    int        `json:"quoted_wait_minutes"`
+	EstimatedWaitMinutes *int       `json:"estimated_wait_minutes,omitempty"`
+	NotifiedAt           *time.Time `json:"notified_at,omitempty"`
+	CreatedAt            *time.Time `json:"created_at,omitempty"`
+}
+
+// CreateWaitlistEntryRequest quotes the estimated wait unless QuotedWaitMinutes is given
+type CreateWaitlistEntryRequest struct {
+	RestaurantID      *int    `json:"restaurant_id"`
+	GuestName         string  `json:"guest_name" binding:"required,max=100"`
+	GuestPhone        string  `json:"guest_phone" binding:"required,max=20"`
+	PartySize         int     `json:"party_size" binding:"required,min=1"`
+	QuotedWaitMinutes *int    `json:"quoted_wait_minutes" binding:"omitempty,min=0,max=600"`
+	Notes             *string `json:"notes"`
+}
+
+type ListWaitlistRequest struct {
+	BaseRequestParamsUri
+	RestaurantID *int `form:"restaurant_id"`
+	// Status defaults to the parties still waiting or notified; all lists every entry
+	Status *string `form:"status" binding:"omitempty,oneof=active all waiting notified seated removed"`
+}
+
+type WaitEstimateRequest struct {
+	RestaurantID *int `form:"restaurant_id"`
+	PartySize    int  `form:"party_size" binding:"required,min=1"`
+}
+
+type WaitEstimateResponse struct {
+	PartySize            int  `json:"party_size"`
+	PartiesAhead         int  `json:"parties_ahead"`
+	EstimatedWaitMinutes *int `json:"estimated_wait_minutes"`
+}
+
+type SeatWaitlistEntryRequest struct {
+	TableID int `json:"table_id" binding:"required,min=1"`
+}
+
+type WaitlistIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
+
+type WaitlistTokenParamsUri struct {
+	Token string `uri:"token" binding:"required,max=64"`
+}
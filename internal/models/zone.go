@@ -0,0 +1,55 @@
+package models
+
+import (
+	"app-noti/common"
+	"time"
+)
+
+// Zone is a part of a restaurant that tables belong to. Tables of an inactive zone are not
+// offered for bookings or walk-ins. The service charge and minimum spend apply to the bill of
+// each table in the zone.
+type Zone struct {
+	ID                   int        `json:"id" gorm:"column:id;primaryKey;autoIncrement"`
+	RestaurantID         int        `json:"restaurant_id" gorm:"column:restaurant_id"`
+	Name                 string     `json:"name" gorm:"column:name"`
+	DisplayOrder         int        `json:"display_order" gorm:"column:display_order"`
+	IsActive             bool       `json:"is_active" gorm:"column:is_active"`
+	ServiceChargePercent *float64   `json:"service_charge_percent,omitempty" gorm:"column:service_charge_percent"`
+	MinimumSpend         *float64   `json:"minimum_spend,omitempty" gorm:"column:minimum_spend"`
+	CreatedAt            *time.Time `json:"created_at,omitempty" gorm:"column:created_at"`
+	UpdatedAt            *time.Time `json:"updated_at,omitempty" gorm:"column:updated_at"`
+
+	TableCount int `json:"table_count" gorm:"-"`
+}
+
+func (Zone) TableName() string {
+	return common.POSTGRES_TABLE_NAME_ZONES
+}
+
+type ListZonesRequest struct {
+	RestaurantID    *int `form:"restaurant_id"`
+	IncludeInactive bool `form:"include_inactive"`
+}
+
+type CreateZoneRequest struct {
+	RestaurantID         *int     `json:"restaurant_id"`
+	Name                 string   `json:"name" binding:"required,max=100"`
+	DisplayOrder         *int     `json:"display_order"`
+	IsActive             *bool    `json:"is_active"`
+	ServiceChargePercent *float64 `json:"service_charge_percent" binding:"omitempty,min=0,max=100"`
+	MinimumSpend         *float64 `json:"minimum_spend" binding:"omitempty,min=0"`
+}
+
+// UpdateZoneRequest changes a zone. A service charge or minimum spend of 0 removes the rule.
+// Renaming a zone renames the location of its tables.
+type UpdateZoneRequest struct {
+	Name                 *string  `json:"name" binding:"omitempty,min=1,max=100"`
+	DisplayOrder         *int     `json:"display_order"`
+	IsActive             *bool    `json:"is_active"`
+	ServiceChargePercent *float64 `json:"service_charge_percent" binding:"omitempty,min=0,max=100"`
+	MinimumSpend         *float64 `json:"minimum_spend" binding:"omitempty,min=0"`
+}
+
+type ZoneIDParamsUri struct {
+	ID int `uri:"id" binding:"required,min=1"`
+}
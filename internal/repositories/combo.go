@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ComboRepo struct {
+	db *gorm.DB
+	BaseRepository[models.Combo]
+}
+
+func NewComboRepository(db *gorm.DB) *ComboRepo {
+	baseRepo := NewBaseRepository[models.Combo](db)
+	return &ComboRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *ComboRepo) GetDB() *gorm.DB {
+	return r.db
+}
+
+type ComboSlotRepo struct {
+	db *gorm.DB
+	BaseRepository[models.ComboSlot]
+}
+
+func NewComboSlotRepository(db *gorm.DB) *ComboSlotRepo {
+	baseRepo := NewBaseRepository[models.ComboSlot](db)
+	return &ComboSlotRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+type ComboSlotChoiceRepo struct {
+	db *gorm.DB
+	BaseRepository[models.ComboSlotChoice]
+}
+
+func NewComboSlotChoiceRepository(db *gorm.DB) *ComboSlotChoiceRepo {
+	baseRepo := NewBaseRepository[models.ComboSlotChoice](db)
+	return &ComboSlotChoiceRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type FloorPlanRepo struct {
+	db *gorm.DB
+	BaseRepository[models.FloorPlan]
+}
+
+func NewFloorPlanRepository(db *gorm.DB) *FloorPlanRepo {
+	baseRepo := NewBaseRepository[models.FloorPlan](db)
+	return &FloorPlanRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *FloorPlanRepo) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,50 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type IngredientRepo struct {
+	db *gorm.DB
+	BaseRepository[models.Ingredient]
+}
+
+func NewIngredientRepository(db *gorm.DB) *IngredientRepo {
+	baseRepo := NewBaseRepository[models.Ingredient](db)
+	return &IngredientRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *IngredientRepo) GetDB() *gorm.DB {
+	return r.db
+}
+
+type RecipeLineRepo struct {
+	db *gorm.DB
+	BaseRepository[models.RecipeLine]
+}
+
+func NewRecipeLineRepository(db *gorm.DB) *RecipeLineRepo {
+	baseRepo := NewBaseRepository[models.RecipeLine](db)
+	return &RecipeLineRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+type StockMovementRepo struct {
+	db *gorm.DB
+	BaseRepository[models.StockMovement]
+}
+
+func NewStockMovementRepository(db *gorm.DB) *StockMovementRepo {
+	baseRepo := NewBaseRepository[models.StockMovement](db)
+	return &StockMovementRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type StockAlertRepo struct {
+	db *gorm.DB
+	BaseRepository[models.StockAlert]
+}
+
+func NewStockAlertRepository(db *gorm.DB) *StockAlertRepo {
+	baseRepo := NewBaseRepository[models.StockAlert](db)
+	return &StockAlertRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
@@ -47,6 +47,10 @@ func NewMenuItemRepository(db *gorm.DB) *MenuItemRepo {
 	}
 }
 
+func (r *MenuItemRepo) GetDB() *gorm.DB {
+	return r.db
+}
+
 type MenuItemPhotoRepo struct {
 	db *gorm.DB
 	BaseRepository[models.MenuItemPhoto]
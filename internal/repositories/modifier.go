@@ -83,3 +83,20 @@ func NewMenuItemModifierGroupRepository(db *gorm.DB) *MenuItemModifierGroupRepo
 		BaseRepository: baseRepo,
 	}
 }
+
+type ModifierOptionChildGroupRepo struct {
+	db *gorm.DB
+	BaseRepository[models.ModifierOptionChildGroup]
+}
+
+func NewModifierOptionChildGroupRepository(db *gorm.DB) *ModifierOptionChildGroupRepo {
+	baseRepo := NewBaseRepository[models.ModifierOptionChildGroup](db)
+	return &ModifierOptionChildGroupRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *ModifierOptionChildGroupRepo) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type OrderRepo struct {
+	db *gorm.DB
+	BaseRepository[models.Order]
+}
+
+func NewOrderRepository(db *gorm.DB) *OrderRepo {
+	baseRepo := NewBaseRepository[models.Order](db)
+	return &OrderRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *OrderRepo) GetDB() *gorm.DB {
+	return r.db
+}
+
+type OrderItemRepo struct {
+	db *gorm.DB
+	BaseRepository[models.OrderItem]
+}
+
+func NewOrderItemRepository(db *gorm.DB) *OrderItemRepo {
+	baseRepo := NewBaseRepository[models.OrderItem](db)
+	return &OrderItemRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
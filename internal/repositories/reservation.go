@@ -0,0 +1,37 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ReservationRepo struct {
+	db *gorm.DB
+	BaseRepository[models.Reservation]
+}
+
+func NewReservationRepository(db *gorm.DB) *ReservationRepo {
+	baseRepo := NewBaseRepository[models.Reservation](db)
+	return &ReservationRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *ReservationRepo) GetDB() *gorm.DB {
+	return r.db
+}
+
+type ReservationTableRepo struct {
+	db *gorm.DB
+	BaseRepository[models.ReservationTable]
+}
+
+func NewReservationTableRepository(db *gorm.DB) *ReservationTableRepo {
+	baseRepo := NewBaseRepository[models.ReservationTable](db)
+	return &ReservationTableRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TableGroupRepo struct {
+	db *gorm.DB
+	BaseRepository[models.TableGroup]
+}
+
+func NewTableGroupRepository(db *gorm.DB) *TableGroupRepo {
+	baseRepo := NewBaseRepository[models.TableGroup](db)
+	return &TableGroupRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *TableGroupRepo) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type TableMoveRepo struct {
+	db *gorm.DB
+	BaseRepository[models.TableMove]
+}
+
+func NewTableMoveRepository(db *gorm.DB) *TableMoveRepo {
+	baseRepo := NewBaseRepository[models.TableMove](db)
+	return &TableMoveRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *TableMoveRepo) GetDB() *gorm.DB {
+	return r.db
+}
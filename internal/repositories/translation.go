@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type MenuTranslationRepo struct {
+	db *gorm.DB
+	BaseRepository[models.MenuTranslation]
+}
+
+func NewMenuTranslationRepository(db *gorm.DB) *MenuTranslationRepo {
+	baseRepo := NewBaseRepository[models.MenuTranslation](db)
+	return &MenuTranslationRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *MenuTranslationRepo) GetDB() *gorm.DB {
+	return r.db
+}
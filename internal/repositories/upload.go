@@ -0,0 +1,20 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type UploadRepo struct {
+	db *gorm.DB
+	BaseRepository[models.Upload]
+}
+
+func NewUploadRepository(db *gorm.DB) *UploadRepo {
+	baseRepo := NewBaseRepository[models.Upload](db)
+	return &UploadRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type WaitlistRepo struct {
+	db *gorm.DB
+	BaseRepository[models.WaitlistEntry]
+}
+
+func NewWaitlistRepository(db *gorm.DB) *WaitlistRepo {
+	baseRepo := NewBaseRepository[models.WaitlistEntry](db)
+	return &WaitlistRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *WaitlistRepo) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,24 @@
+package repositories
+
+import (
+	"app-noti/internal/models"
+
+	"gorm.io/gorm"
+)
+
+type ZoneRepo struct {
+	db *gorm.DB
+	BaseRepository[models.Zone]
+}
+
+func NewZoneRepository(db *gorm.DB) *ZoneRepo {
+	baseRepo := NewBaseRepository[models.Zone](db)
+	return &ZoneRepo{
+		db:             db,
+		BaseRepository: baseRepo,
+	}
+}
+
+func (r *ZoneRepo) GetDB() *gorm.DB {
+	return r.db
+}
@@ -0,0 +1,348 @@
+package services
+
+import (
+	"app-noti/config"
+	"app-noti/internal/models"
+	"context"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultSlotIntervalMinutes = 15
+	defaultSearchWindowMinutes = 120
+	defaultMaxCombinedTables   = 3
+	availabilityOptionLimit    = 3
+)
+
+type busyRange struct {
+	start time.Time
+	end   time.Time
+}
+
+func (r busyRange) overlaps(start time.Time, end time.Time) bool {
+	return r.start.Before(end) && start.Before(r.end)
+}
+
+// GetAvailability lists the times on a day a party can be booked, with the tables or table
+// combinations free for each. Guests get the times only.
+func (s *Service) GetAvailability(ctx context.Context, request *models.AvailabilityRequest, includeTables bool) (*models.AvailabilityResponse, error) {
+	date, err := time.ParseInLocation("2006-01-02", request.Date, time.Local)
+	if err != nil {
+		return nil, err
+	}
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	duration := time.Duration(reservationDuration(nil, request.PartySize)) * time.Minute
+	response := &models.AvailabilityResponse{
+		Date:            request.Date,
+		PartySize:       request.PartySize,
+		DurationMinutes: int(duration / time.Minute),
+		Slots:           []*models.AvailabilitySlot{},
+	}
+
+	starts, err := s.availabilitySlotStarts(date, duration, request.Time)
+	if err != nil {
+		return nil, err
+	}
+	if len(starts) == 0 {
+		return response, nil
+	}
+
+	tables, err := s.tableRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "capacity.asc,id.asc"},
+	}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ? AND status <> ?", restaurantID, "inactive").Where(activeZoneCondition)
+		if request.ZoneID != nil {
+			tx.Where("zone_id = ?", *request.ZoneID)
+		} else if request.Zone != nil && *request.Zone != "" {
+			tx.Where(zoneNameCondition, *request.Zone)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return response, nil
+	}
+
+	length := duration + reservationBuffer()
+	busy, err := s.getReservedRanges(ctx, tables, starts[0], starts[len(starts)-1].Add(length))
+	if err != nil {
+		return nil, err
+	}
+
+	// a table taken by walk-ins is busy for its own turn time from now
+	now := time.Now()
+	for _, table := range tables {
+		if table.Status == "occupied" {
+			turnTime := time.Duration(reservationDuration(nil, table.Capacity)) * time.Minute
+			busy[table.ID] = append(busy[table.ID], busyRange{start: now, end: now.Add(turnTime + reservationBuffer())})
+		}
+	}
+
+	maxCombined := maxCombinedTables()
+	for _, start := range starts {
+		end := start.Add(length)
+		free := make([]*models.Table, 0, len(tables))
+		for _, table := range tables {
+			if !slices.ContainsFunc(busy[table.ID], func(r busyRange) bool { return r.overlaps(start, end) }) {
+				free = append(free, table)
+			}
+		}
+
+		options := tableOptions(free, request.PartySize, maxCombined)
+		if len(options) == 0 {
+			continue
+		}
+
+		slot := &models.AvailabilitySlot{StartsAt: start, EndsAt: start.Add(duration)}
+		if includeTables {
+			slot.Options = options
+		}
+		response.Slots = append(response.Slots, slot)
+	}
+
+	return response, nil
+}
+
+// availabilitySlotStarts lists the start times on date, every slot interval, at which a party can
+// be seated and finish before closing. With at, only times within the search window around it.
+func (s *Service) availabilitySlotStarts(date time.Time, duration time.Duration, at *string) ([]time.Time, error) {
+	interval := time.Duration(config.Config.Reservation.SlotIntervalMinutes) * time.Minute
+	if interval <= 0 {
+		interval = defaultSlotIntervalMinutes * time.Minute
+	}
+
+	dayStart, dayEnd := date, date.AddDate(0, 0, 1)
+	from, to := dayStart, dayEnd
+	if at != nil && *at != "" {
+		clock, err := time.Parse("15:04", *at)
+		if err != nil {
+			return nil, err
+		}
+
+		window := time.Duration(config.Config.Reservation.SearchWindowMinutes) * time.Minute
+		if window <= 0 {
+			window = defaultSearchWindowMinutes * time.Minute
+		}
+		target := time.Date(dayStart.Year(), dayStart.Month(), dayStart.Day(), clock.Hour(), clock.Minute(), 0, 0, time.Local)
+		from, to = target.Add(-window), target.Add(window+time.Second)
+	}
+	if now := time.Now(); from.Before(now) {
+		from = now
+	}
+
+	// a period of the day before may run past midnight
+	periods := append(s.openingPeriods(dayStart.AddDate(0, 0, -1)), s.openingPeriods(dayStart)...)
+
+	var starts []time.Time
+	for _, period := range periods {
+		for start := period.start; !start.Add(duration).After(period.end); start = start.Add(interval) {
+			if start.Before(dayStart) || !start.Before(dayEnd) || start.Before(from) || !start.Before(to) {
+				continue
+			}
+			starts = append(starts, start)
+		}
+	}
+
+	sort.Slice(starts, func(i, j int) bool { return starts[i].Before(starts[j]) })
+	return slices.CompactFunc(starts, func(a time.Time, b time.Time) bool { return a.Equal(b) }), nil
+}
+
+// openingPeriods reads the opening hours of the weekday of date. Times are clock readings on that
+// day, so they stay right on days the clocks change. Entries that do not parse are logged and
+// skipped.
+func (s *Service) openingPeriods(date time.Time) []busyRange {
+	day := strings.ToLower(date.Weekday().String()[:3])
+
+	var periods []busyRange
+	for _, hours := range config.Config.Reservation.OpeningHours[day] {
+		var openHour, openMinute, closeHour, closeMinute int
+		if _, err := fmt.Sscanf(hours, "%d:%d-%d:%d", &openHour, &openMinute, &closeHour, &closeMinute); err != nil {
+			s.logger.Warn("Invalid opening hours", zap.String("day", day), zap.String("hours", hours), zap.Error(err))
+			continue
+		}
+
+		open := time.Date(date.Year(), date.Month(), date.Day(), openHour, openMinute, 0, 0, time.Local)
+		closing := time.Date(date.Year(), date.Month(), date.Day(), closeHour, closeMinute, 0, 0, time.Local)
+		if !closing.After(open) {
+			closing = closing.AddDate(0, 0, 1)
+		}
+		periods = append(periods, busyRange{start: open, end: closing})
+	}
+
+	return periods
+}
+
+// withinOpeningHours tells whether a party starting at start, in the server's zone, can finish
+// within one opening period
+func (s *Service) withinOpeningHours(start time.Time, duration time.Duration) bool {
+	day := time.Date(start.Year(), start.Month(), start.Day(), 0, 0, 0, 0, start.Location())
+	periods := append(s.openingPeriods(day.AddDate(0, 0, -1)), s.openingPeriods(day)...)
+
+	return slices.ContainsFunc(periods, func(period busyRange) bool {
+		return !start.Before(period.start) && !start.Add(duration).After(period.end)
+	})
+}
+
+// getReservedRanges lists, per table, the times it is held by reservations between from and to
+func (s *Service) getReservedRanges(ctx context.Context, tables []*models.Table, from time.Time, to time.Time) (map[int][]busyRange, error) {
+	tableIDs := make([]int, 0, len(tables))
+	for _, table := range tables {
+		tableIDs = append(tableIDs, table.ID)
+	}
+
+	var rows []struct {
+		TableID  int
+		StartsAt time.Time
+		EndsAt   time.Time
+	}
+	err := s.reservationRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT table_id, lower(during) AS starts_at, upper(during) AS ends_at
+		FROM reservation_tables
+		WHERE is_active = TRUE AND table_id IN ? AND during && ?::tsrange
+	`, tableIDs, tsRange(from, to)).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	busy := make(map[int][]busyRange, len(tables))
+	for _, row := range rows {
+		busy[row.TableID] = append(busy[row.TableID], busyRange{start: fromTimestamp(row.StartsAt), end: fromTimestamp(row.EndsAt)})
+	}
+
+	return busy, nil
+}
+
+// tableOptions picks the smallest single tables that seat the party. When none does, it tries
+// two tables, then three and so on up to maxCombined, always from the same zone. Only the
+// availabilityOptionLimit smallest options are kept.
+func tableOptions(free []*models.Table, partySize int, maxCombined int) []*models.TableOption {
+	sorted := slices.Clone(free)
+	sort.SliceStable(sorted, func(i, j int) bool {
+		if sorted[i].Capacity != sorted[j].Capacity {
+			return sorted[i].Capacity < sorted[j].Capacity
+		}
+		return sorted[i].ID < sorted[j].ID
+	})
+
+	var combinations [][]*models.Table
+	for _, table := range sorted {
+		if table.Capacity >= partySize && len(combinations) < availabilityOptionLimit {
+			combinations = append(combinations, []*models.Table{table})
+		}
+	}
+
+	byZone := make(map[int][]*models.Table)
+	var zoneIDs []int
+	for _, table := range sorted {
+		zoneID := 0
+		if table.ZoneID != nil {
+			zoneID = *table.ZoneID
+		}
+		if _, ok := byZone[zoneID]; !ok {
+			zoneIDs = append(zoneIDs, zoneID)
+		}
+		byZone[zoneID] = append(byZone[zoneID], table)
+	}
+	slices.Sort(zoneIDs)
+
+	for size := 2; len(combinations) == 0 && size <= maxCombined; size++ {
+		for _, zoneID := range zoneIDs {
+			combinations = seatingCombinations(byZone[zoneID], size, partySize, combinations)
+		}
+	}
+
+	options := make([]*models.TableOption, 0, len(combinations))
+	for _, combination := range combinations {
+		option := &models.TableOption{
+			TableIDs: make([]int, 0, len(combination)),
+			Capacity: totalCapacity(combination),
+			Tables:   make([]*models.ReservedTable, 0, len(combination)),
+		}
+		for _, table := range combination {
+			option.TableIDs = append(option.TableIDs, table.ID)
+			option.Tables = append(option.Tables, &models.ReservedTable{
+				ID:          table.ID,
+				TableNumber: table.TableNumber,
+				Capacity:    table.Capacity,
+				Location:    table.Location,
+			})
+		}
+		options = append(options, option)
+	}
+
+	return options
+}
+
+// seatingCombinations adds to best the sets of size tables that together seat the party, keeping
+// best to the availabilityOptionLimit smallest. tables must be sorted by capacity, so a branch is
+// dropped once even its largest tables fall short of the party, or its smallest cannot beat best.
+func seatingCombinations(tables []*models.Table, size int, partySize int, best [][]*models.Table) [][]*models.Table {
+	// prefix[i] is the capacity of the first i tables
+	prefix := make([]int, len(tables)+1)
+	for i, table := range tables {
+		prefix[i+1] = prefix[i] + table.Capacity
+	}
+	n := len(tables)
+
+	var walk func(from int, picked []*models.Table, capacity int)
+	walk = func(from int, picked []*models.Table, capacity int) {
+		left := size - len(picked)
+		if left == 0 {
+			if capacity >= partySize {
+				best = addSeatingCombination(best, slices.Clone(picked))
+			}
+			return
+		}
+		if n-from < left || capacity+prefix[n]-prefix[n-left] < partySize {
+			return
+		}
+		for i := from; i <= n-left; i++ {
+			smallest := capacity + prefix[i+left] - prefix[i]
+			if len(best) == availabilityOptionLimit && smallest >= totalCapacity(best[len(best)-1]) {
+				break
+			}
+			walk(i+1, append(picked, tables[i]), capacity+tables[i].Capacity)
+		}
+	}
+	walk(0, make([]*models.Table, 0, size), 0)
+
+	return best
+}
+
+// addSeatingCombination puts combination in best by total capacity, then first table id, and
+// drops what falls past availabilityOptionLimit
+func addSeatingCombination(best [][]*models.Table, combination []*models.Table) [][]*models.Table {
+	capacity := totalCapacity(combination)
+	at, _ := slices.BinarySearchFunc(best, combination, func(a []*models.Table, _ []*models.Table) int {
+		if c := totalCapacity(a); c != capacity {
+			return c - capacity
+		}
+		return a[0].ID - combination[0].ID
+	})
+	best = slices.Insert(best, at, combination)
+	if len(best) > availabilityOptionLimit {
+		best = best[:availabilityOptionLimit]
+	}
+	return best
+}
+
+func totalCapacity(tables []*models.Table) int {
+	capacity := 0
+	for _, table := range tables {
+		capacity += table.Capacity
+	}
+	return capacity
+}
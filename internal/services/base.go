@@ -4,7 +4,10 @@ import (
 	"app-noti/common"
 	"app-noti/internal/repositories"
 	l "app-noti/pkg/logger"
+	"app-noti/pkg/redis"
+	"app-noti/pkg/storage"
 	"app-noti/server"
+	"errors"
 
 	"go.uber.org/zap"
 	"gorm.io/gorm"
@@ -20,10 +23,37 @@ type Service struct {
 	modifierGroupRepo         *repositories.ModifierGroupRepo
 	modifierOptionRepo        *repositories.ModifierOptionRepo
 	menuItemModifierGroupRepo *repositories.MenuItemModifierGroupRepo
+	orderRepo                 *repositories.OrderRepo
+	orderItemRepo             *repositories.OrderItemRepo
+	stockAlertRepo            *repositories.StockAlertRepo
+	ingredientRepo            *repositories.IngredientRepo
+	recipeLineRepo            *repositories.RecipeLineRepo
+	stockMovementRepo         *repositories.StockMovementRepo
+	menuTranslationRepo       *repositories.MenuTranslationRepo
+	comboRepo                 *repositories.ComboRepo
+	comboSlotRepo             *repositories.ComboSlotRepo
+	comboSlotChoiceRepo       *repositories.ComboSlotChoiceRepo
+	modifierChildGroupRepo    *repositories.ModifierOptionChildGroupRepo
+	uploadRepo                *repositories.UploadRepo
+	reservationRepo           *repositories.ReservationRepo
+	reservationTableRepo      *repositories.ReservationTableRepo
+	waitlistRepo              *repositories.WaitlistRepo
+	tableGroupRepo            *repositories.TableGroupRepo
+	tableMoveRepo             *repositories.TableMoveRepo
+	floorPlanRepo             *repositories.FloorPlanRepo
+	zoneRepo                  *repositories.ZoneRepo
+	menuCache                 *menuCache
+	storage                   storage.Storage
 }
 
-func NewService(sc server.ServerContext) *Service {
+// NewService fails when the storage service did not start, rather than every upload failing later
+func NewService(sc server.ServerContext) (*Service, error) {
 	db := sc.GetService(common.PREFIX_MAIN_POSTGRES).(*gorm.DB)
+	redisClient, _ := sc.GetService(common.PREFIX_MAIN_REDIS).(redis.ClientI)
+	objectStorage, ok := sc.GetService(common.PREFIX_MAIN_STORAGE).(storage.Storage)
+	if !ok {
+		return nil, errors.New("main storage is not running")
+	}
 
 	return &Service{
 		logger:                    l.New(),
@@ -35,5 +65,26 @@ func NewService(sc server.ServerContext) *Service {
 		modifierGroupRepo:         repositories.NewModifierGroupRepository(db),
 		modifierOptionRepo:        repositories.NewModifierOptionRepository(db),
 		menuItemModifierGroupRepo: repositories.NewMenuItemModifierGroupRepository(db),
-	}
+		orderRepo:                 repositories.NewOrderRepository(db),
+		orderItemRepo:             repositories.NewOrderItemRepository(db),
+		stockAlertRepo:            repositories.NewStockAlertRepository(db),
+		ingredientRepo:            repositories.NewIngredientRepository(db),
+		recipeLineRepo:            repositories.NewRecipeLineRepository(db),
+		stockMovementRepo:         repositories.NewStockMovementRepository(db),
+		menuTranslationRepo:       repositories.NewMenuTranslationRepository(db),
+		comboRepo:                 repositories.NewComboRepository(db),
+		comboSlotRepo:             repositories.NewComboSlotRepository(db),
+		comboSlotChoiceRepo:       repositories.NewComboSlotChoiceRepository(db),
+		modifierChildGroupRepo:    repositories.NewModifierOptionChildGroupRepository(db),
+		uploadRepo:                repositories.NewUploadRepository(db),
+		reservationRepo:           repositories.NewReservationRepository(db),
+		reservationTableRepo:      repositories.NewReservationTableRepository(db),
+		waitlistRepo:              repositories.NewWaitlistRepository(db),
+		tableGroupRepo:            repositories.NewTableGroupRepository(db),
+		tableMoveRepo:             repositories.NewTableMoveRepository(db),
+		floorPlanRepo:             repositories.NewFloorPlanRepository(db),
+		zoneRepo:                  repositories.NewZoneRepository(db),
+		menuCache:                 newMenuCache(redisClient),
+		storage:                   objectStorage,
+	}, nil
 }
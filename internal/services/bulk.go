@@ -0,0 +1,312 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+const bulkMenuItemLimit = 500
+
+// BulkUpdateMenuItems applies one action to many items. Every item is checked first; the ones
+// that pass are written together in one transaction and the others are reported as failed.
+// With dry_run nothing is written and the results show what would change.
+func (s *Service) BulkUpdateMenuItems(ctx context.Context, request *models.BulkMenuItemRequest) (*models.BulkMenuItemResponse, error) {
+	if err := validateBulkRequest(request); err != nil {
+		return nil, err
+	}
+
+	menuItems, err := s.getBulkMenuItems(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+
+	var targetCategory *models.MenuCategory
+	if request.Action == models.BulkActionMoveCategory {
+		targetCategory, err = s.getActiveMenuCategory(ctx, *request.CategoryID)
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	response := &models.BulkMenuItemResponse{
+		Action:  request.Action,
+		DryRun:  request.DryRun,
+		Matched: len(menuItems),
+		Results: make([]*models.BulkMenuItemResult, 0, len(menuItems)),
+	}
+
+	db := s.menuItemRepo.GetDB().WithContext(ctx)
+	changes := make(map[int]map[string]interface{})
+	nextDisplayOrder := 0
+	if targetCategory != nil {
+		nextDisplayOrder = s.nextMenuItemDisplayOrder(ctx, targetCategory.ID)
+	}
+
+	for _, menuItem := range menuItems {
+		result := &models.BulkMenuItemResult{ID: menuItem.ID, Name: menuItem.Name}
+		response.Results = append(response.Results, result)
+
+		before, after, err := s.planBulkChange(db, request, menuItem, targetCategory)
+		if err != nil {
+			result.Result = models.BulkResultFailed
+			result.Error = err.Error()
+			response.Failed++
+			continue
+		}
+
+		if len(after) == 0 {
+			result.Result = models.BulkResultUnchanged
+			response.Unchanged++
+			continue
+		}
+
+		result.Before, result.After = before, after
+		result.Result = models.BulkResultWouldUpdate
+		response.Updated++
+
+		columns := make(map[string]interface{}, len(after)+2)
+		for column, value := range after {
+			columns[column] = value
+		}
+		if targetCategory != nil {
+			// moved items go to the end of their new category, in the order they were matched
+			columns["display_order"] = nextDisplayOrder
+			nextDisplayOrder++
+		}
+		changes[menuItem.ID] = columns
+	}
+
+	// ids that are unknown or already deleted are reported rather than silently dropped
+	found := make(map[int]bool, len(menuItems))
+	for _, menuItem := range menuItems {
+		found[menuItem.ID] = true
+	}
+	for _, id := range request.IDs {
+		if !found[id] {
+			response.Results = append(response.Results, &models.BulkMenuItemResult{
+				ID:     id,
+				Result: models.BulkResultFailed,
+				Error:  gorm.ErrRecordNotFound.Error(),
+			})
+			response.Failed++
+		}
+	}
+
+	if request.DryRun || len(changes) == 0 {
+		return response, nil
+	}
+
+	now := time.Now()
+	err = db.Transaction(func(tx *gorm.DB) error {
+		for _, menuItem := range menuItems {
+			columns, ok := changes[menuItem.ID]
+			if !ok {
+				continue
+			}
+
+			columns["updated_at"] = now
+			if err := tx.Model(&models.MenuItem{}).Where("id = ?", menuItem.ID).Updates(columns).Error; err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, result := range response.Results {
+		if result.Result == models.BulkResultWouldUpdate {
+			result.Result = models.BulkResultUpdated
+		}
+	}
+
+	restaurantIDs := make([]int, 0, 2)
+	for _, menuItem := range menuItems {
+		restaurantIDs = append(restaurantIDs, menuItem.RestaurantID)
+	}
+	if targetCategory != nil {
+		restaurantIDs = append(restaurantIDs, targetCategory.RestaurantID)
+	}
+	s.invalidateMenuCache(ctx, restaurantIDs...)
+
+	return response, nil
+}
+
+func validateBulkRequest(request *models.BulkMenuItemRequest) error {
+	if (len(request.IDs) == 0) == (request.Filter == nil) {
+		return common.ErrInvalidBulkRequest
+	}
+
+	if len(request.IDs) > bulkMenuItemLimit {
+		return common.ErrBulkLimitExceeded
+	}
+
+	switch request.Action {
+	case models.BulkActionSetStatus:
+		if request.Status == nil {
+			return common.ErrInvalidBulkRequest
+		}
+	case models.BulkActionMoveCategory:
+		if request.CategoryID == nil {
+			return common.ErrInvalidBulkRequest
+		}
+	case models.BulkActionAdjustPrice:
+		if request.Price == nil {
+			return common.ErrInvalidBulkRequest
+		}
+	case models.BulkActionSetChefRecommended:
+		if request.ChefRecommended == nil {
+			return common.ErrInvalidBulkRequest
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) getBulkMenuItems(ctx context.Context, request *models.BulkMenuItemRequest) ([]*models.MenuItem, error) {
+	queryParams := models.QueryParams{
+		Limit:     bulkMenuItemLimit + 1,
+		QuerySort: models.QuerySort{Origin: "id.asc"},
+	}
+
+	var menuItems []*models.MenuItem
+	var err error
+	if len(request.IDs) > 0 {
+		menuItems, err = s.menuItemRepo.List(ctx, queryParams, func(tx *gorm.DB) {
+			tx.Where("id IN ? AND is_deleted = FALSE", request.IDs)
+		})
+	} else {
+		menuItems, err = s.menuItemRepo.List(ctx, queryParams, bulkFilterClause(request.Filter))
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	if len(menuItems) > bulkMenuItemLimit {
+		return nil, common.ErrBulkLimitExceeded
+	}
+
+	return menuItems, nil
+}
+
+func bulkFilterClause(filter *models.BulkMenuItemFilter) func(tx *gorm.DB) {
+	return func(tx *gorm.DB) {
+		restaurantID := 1
+		if filter.RestaurantID != nil {
+			restaurantID = *filter.RestaurantID
+		}
+		tx.Where("restaurant_id = ? AND is_deleted = FALSE", restaurantID)
+
+		if filter.CategoryID != nil {
+			tx.Where("category_id = ?", *filter.CategoryID)
+		}
+		if filter.Status != nil {
+			tx.Where("status = ?", *filter.Status)
+		}
+		if filter.Search != nil && *filter.Search != "" {
+			tx.Where("LOWER(name) LIKE ?", "%"+strings.ToLower(*filter.Search)+"%")
+		}
+	}
+}
+
+// planBulkChange works out the columns the action changes on one item. An empty result means
+// the item already matches; an error is the reason the item cannot take the change.
+func (s *Service) planBulkChange(
+	db *gorm.DB,
+	request *models.BulkMenuItemRequest,
+	menuItem *models.MenuItem,
+	targetCategory *models.MenuCategory,
+) (map[string]interface{}, map[string]interface{}, error) {
+	before := make(map[string]interface{})
+	after := make(map[string]interface{})
+
+	switch request.Action {
+	case models.BulkActionSetStatus:
+		status := *request.Status
+		if status == menuItem.Status {
+			break
+		}
+		if status == "available" {
+			if menuItem.StockQuantity != nil && *menuItem.StockQuantity == 0 {
+				return nil, nil, common.ErrInsufficientStock
+			}
+			shortage, err := hasIngredientShortage(db, models.StockEntityMenuItem, menuItem.ID)
+			if err != nil {
+				return nil, nil, err
+			}
+			if shortage {
+				return nil, nil, common.ErrInsufficientStock
+			}
+		}
+		before["status"], after["status"] = menuItem.Status, status
+
+	case models.BulkActionMoveCategory:
+		if menuItem.CategoryID == targetCategory.ID {
+			break
+		}
+		if menuItem.RestaurantID != targetCategory.RestaurantID {
+			return nil, nil, common.ErrInvalidBulkRequest
+		}
+		before["category_id"], after["category_id"] = menuItem.CategoryID, targetCategory.ID
+
+	case models.BulkActionAdjustPrice:
+		price := adjustPrice(menuItem.Price, request.Price)
+		if price <= 0 {
+			return nil, nil, common.ErrInvalidPrice
+		}
+		if price == menuItem.Price {
+			break
+		}
+		before["price"], after["price"] = menuItem.Price, price
+
+	case models.BulkActionSetChefRecommended:
+		if *request.ChefRecommended == menuItem.IsChefRecommended {
+			break
+		}
+		before["is_chef_recommended"], after["is_chef_recommended"] = menuItem.IsChefRecommended, *request.ChefRecommended
+
+	case models.BulkActionDelete:
+		before["is_deleted"], after["is_deleted"] = false, true
+		after["deleted_at"] = time.Now()
+	}
+
+	return before, after, nil
+}
+
+func adjustPrice(price float64, adjustment *models.BulkPriceAdjustment) float64 {
+	switch adjustment.Mode {
+	case models.PriceAdjustPercent:
+		price = price * (1 + adjustment.Value/100)
+	default:
+		price = price + adjustment.Value
+	}
+
+	if adjustment.Rounding == nil {
+		return roundPrice(price)
+	}
+
+	step := adjustment.Rounding.Step
+	if step <= 0 {
+		step = 0.01
+	}
+
+	// the small epsilon keeps 12.30000001 from rounding up to the next step
+	units := price / step
+	switch adjustment.Rounding.Mode {
+	case models.RoundingUp:
+		units = math.Ceil(units - 1e-9)
+	case models.RoundingDown:
+		units = math.Floor(units + 1e-9)
+	default:
+		units = math.Round(units)
+	}
+
+	return roundPrice(units * step)
+}
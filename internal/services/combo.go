@@ -0,0 +1,495 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+func (s *Service) GetCombos(ctx context.Context, request *models.ListComboRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("is_deleted = FALSE")
+		},
+	}
+
+	if request.Status != nil && *request.Status != "" && *request.Status != "all" {
+		status := *request.Status
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("status = ?", status)
+		})
+	}
+
+	if request.Search != nil && *request.Search != "" {
+		search := "%" + strings.ToLower(*request.Search) + "%"
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("LOWER(name) LIKE ?", search)
+		})
+	}
+
+	totalCount, err := s.comboRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalCount == 0 {
+		return &models.BaseListResponse{
+			Total:    0,
+			Page:     page,
+			PageSize: pageSize,
+			Items:    []*models.ComboResponse{},
+		}, nil
+	}
+
+	queryParams := models.QueryParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	}
+
+	sortMap := map[string]string{
+		"default":    "display_order.asc,id.asc",
+		"name":       "name.asc",
+		"price_asc":  "price.asc",
+		"price_desc": "price.desc",
+	}
+
+	if sortOrder, ok := sortMap[request.Sort]; ok {
+		queryParams.QuerySort.Origin = sortOrder
+	} else {
+		queryParams.QuerySort.Origin = sortMap["default"]
+	}
+
+	combos, err := s.comboRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	items, err := s.buildComboResponses(ctx, combos, common.DEFAULT_LOCALE)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    items,
+	}, nil
+}
+
+func (s *Service) GetComboByID(ctx context.Context, id int) (*models.ComboResponse, error) {
+	combo, err := s.comboRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	responses, err := s.buildComboResponses(ctx, []*models.Combo{combo}, common.DEFAULT_LOCALE)
+	if err != nil {
+		return nil, err
+	}
+
+	return responses[0], nil
+}
+
+// GetGuestCombos lists the combos shown next to the guest menu. Combos with a slot that can no
+// longer be filled are still listed, with is_available false.
+func (s *Service) GetGuestCombos(ctx context.Context, restaurantID int, request *models.ListMenuRequest) ([]*models.ComboResponse, error) {
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("restaurant_id = ? AND is_deleted = FALSE AND status = ?", restaurantID, "available")
+		},
+	}
+
+	if request.Search != nil && *request.Search != "" {
+		search := "%" + strings.ToLower(*request.Search) + "%"
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("public.f_unaccent(LOWER(name)) LIKE public.f_unaccent(?)", search)
+		})
+	}
+
+	if request.Category != nil && *request.Category != "" && *request.Category != "all" {
+		categoryName := *request.Category
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("category_id IN (SELECT id FROM menu_categories WHERE LOWER(name) = ?)", strings.ToLower(categoryName))
+		})
+	}
+
+	queryParams := models.QueryParams{
+		QuerySort: models.QuerySort{
+			Origin: "display_order.asc,id.asc",
+		},
+	}
+
+	combos, err := s.comboRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.buildComboResponses(ctx, combos, request.Locale)
+}
+
+func (s *Service) buildComboResponses(ctx context.Context, combos []*models.Combo, locale string) ([]*models.ComboResponse, error) {
+	if len(combos) == 0 {
+		return []*models.ComboResponse{}, nil
+	}
+
+	comboIDs := make([]int, 0, len(combos))
+	categoryIDs := make([]int, 0, len(combos))
+	for _, combo := range combos {
+		comboIDs = append(comboIDs, combo.ID)
+		if combo.CategoryID != nil {
+			categoryIDs = append(categoryIDs, *combo.CategoryID)
+		}
+	}
+
+	slots, err := s.comboSlotRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+		tx.Where("combo_id IN ?", comboIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slotIDs := make([]int, 0, len(slots))
+	for _, slot := range slots {
+		slotIDs = append(slotIDs, slot.ID)
+	}
+
+	choices := []*models.ComboSlotChoice{}
+	if len(slotIDs) > 0 {
+		choices, err = s.comboSlotChoiceRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+			tx.Where("slot_id IN ?", slotIDs)
+		})
+		if err != nil {
+			return nil, err
+		}
+	}
+
+	menuItemIDs := make([]int, 0, len(choices))
+	for _, choice := range choices {
+		menuItemIDs = append(menuItemIDs, choice.MenuItemID)
+	}
+
+	menuItemMap, err := s.getMenuItemMap(ctx, menuItemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryMap, err := s.getCategoryMapByIDs(ctx, categoryIDs)
+	if err != nil {
+		categoryMap = make(map[int]string)
+	}
+
+	comboTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityCombo, comboIDs)
+	itemTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityMenuItem, menuItemIDs)
+	categoryTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityCategory, categoryIDs)
+
+	choicesBySlot := make(map[int][]*models.ComboChoiceResponse)
+	for _, choice := range choices {
+		menuItem, ok := menuItemMap[choice.MenuItemID]
+		if !ok {
+			continue
+		}
+
+		name := menuItem.Name
+		localize(itemTranslations[menuItem.ID], &name, nil)
+
+		choicesBySlot[choice.SlotID] = append(choicesBySlot[choice.SlotID], &models.ComboChoiceResponse{
+			MenuItemID:  menuItem.ID,
+			Name:        name,
+			Upcharge:    choice.Upcharge,
+			IsDefault:   choice.IsDefault,
+			IsAvailable: isMenuItemOrderable(menuItem),
+		})
+	}
+
+	slotsByCombo := make(map[int][]*models.ComboSlotResponse)
+	for _, slot := range slots {
+		slotChoices := choicesBySlot[slot.ID]
+		if slotChoices == nil {
+			slotChoices = []*models.ComboChoiceResponse{}
+		}
+
+		slotsByCombo[slot.ComboID] = append(slotsByCombo[slot.ComboID], &models.ComboSlotResponse{
+			ID:         slot.ID,
+			Name:       slot.Name,
+			MinChoices: slot.MinChoices,
+			MaxChoices: slot.MaxChoices,
+			Choices:    slotChoices,
+		})
+	}
+
+	responses := make([]*models.ComboResponse, 0, len(combos))
+	for _, combo := range combos {
+		comboSlots := slotsByCombo[combo.ID]
+		if comboSlots == nil {
+			comboSlots = []*models.ComboSlotResponse{}
+		}
+
+		categoryName := ""
+		if combo.CategoryID != nil {
+			categoryName = categoryMap[*combo.CategoryID]
+			localize(categoryTranslations[*combo.CategoryID], &categoryName, nil)
+		}
+
+		localize(comboTranslations[combo.ID], &combo.Name, &combo.Description)
+
+		responses = append(responses, &models.ComboResponse{
+			ID:           combo.ID,
+			Name:         combo.Name,
+			Description:  combo.Description,
+			Price:        combo.Price,
+			ImageURL:     combo.ImageURL,
+			Category:     categoryName,
+			Status:       combo.Status,
+			IsAvailable:  isComboAvailable(combo, comboSlots),
+			DisplayOrder: combo.DisplayOrder,
+			Slots:        comboSlots,
+		})
+	}
+
+	return responses, nil
+}
+
+// isComboAvailable needs every slot to still have enough orderable choices to be filled
+func isComboAvailable(combo *models.Combo, slots []*models.ComboSlotResponse) bool {
+	if combo.Status != "available" || combo.IsDeleted {
+		return false
+	}
+
+	for _, slot := range slots {
+		available := 0
+		for _, choice := range slot.Choices {
+			if choice.IsAvailable {
+				available++
+			}
+		}
+		if available < slot.MinChoices {
+			return false
+		}
+	}
+
+	return true
+}
+
+func isMenuItemOrderable(menuItem *models.MenuItem) bool {
+	return !menuItem.IsDeleted &&
+		menuItem.Status == "available" &&
+		(menuItem.StockQuantity == nil || *menuItem.StockQuantity > 0)
+}
+
+func (s *Service) getMenuItemMap(ctx context.Context, menuItemIDs []int) (map[int]*models.MenuItem, error) {
+	menuItemMap := make(map[int]*models.MenuItem)
+	if len(menuItemIDs) == 0 {
+		return menuItemMap, nil
+	}
+
+	menuItems, err := s.menuItemRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("id IN ?", menuItemIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, menuItem := range menuItems {
+		menuItemMap[menuItem.ID] = menuItem
+	}
+
+	return menuItemMap, nil
+}
+
+func (s *Service) CreateCombo(ctx context.Context, request *models.CreateComboRequest) (*models.ComboResponse, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	if err := s.validateComboSlots(ctx, request.Slots); err != nil {
+		return nil, err
+	}
+
+	combo := &models.Combo{
+		RestaurantID: restaurantID,
+		CategoryID:   request.CategoryID,
+		Name:         request.Name,
+		Description:  request.Description,
+		Price:        request.Price,
+		ImageURL:     request.ImageURL,
+		Status:       request.Status,
+		DisplayOrder: request.DisplayOrder,
+	}
+
+	err := s.comboRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(combo).Error; err != nil {
+			return err
+		}
+
+		return createComboSlots(tx, combo.ID, request.Slots)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, restaurantID)
+
+	return s.GetComboByID(ctx, combo.ID)
+}
+
+func (s *Service) UpdateCombo(ctx context.Context, id int, request *models.UpdateComboRequest) (*models.ComboResponse, error) {
+	existing, err := s.comboRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(request.Slots) > 0 {
+		if err := s.validateComboSlots(ctx, request.Slots); err != nil {
+			return nil, err
+		}
+	}
+
+	columns := make(map[string]interface{})
+	if request.CategoryID != nil {
+		columns["category_id"] = *request.CategoryID
+	}
+	if request.Name != nil {
+		columns["name"] = *request.Name
+	}
+	if request.Description != nil {
+		columns["description"] = *request.Description
+	}
+	if request.Price != nil {
+		columns["price"] = *request.Price
+	}
+	if request.ImageURL != nil {
+		columns["image_url"] = *request.ImageURL
+	}
+	if request.Status != nil {
+		columns["status"] = *request.Status
+	}
+	if request.DisplayOrder != nil {
+		columns["display_order"] = *request.DisplayOrder
+	}
+
+	err = s.comboRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if len(columns) > 0 {
+			columns["updated_at"] = time.Now()
+			if err := tx.Model(&models.Combo{}).Where("id = ?", id).Updates(columns).Error; err != nil {
+				return err
+			}
+		}
+
+		if len(request.Slots) == 0 {
+			return nil
+		}
+
+		// choices go with their slots through ON DELETE CASCADE
+		if err := tx.Where("combo_id = ?", id).Delete(&models.ComboSlot{}).Error; err != nil {
+			return err
+		}
+
+		return createComboSlots(tx, id, request.Slots)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, existing.RestaurantID)
+
+	return s.GetComboByID(ctx, id)
+}
+
+func (s *Service) DeleteCombo(ctx context.Context, id int) error {
+	combo, err := s.comboRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+	if err != nil {
+		return err
+	}
+
+	columns := map[string]interface{}{
+		"is_deleted": true,
+		"updated_at": time.Now(),
+	}
+
+	_, err = s.comboRepo.UpdateColumns(ctx, id, columns)
+	if err != nil {
+		return err
+	}
+
+	s.invalidateMenuCache(ctx, combo.RestaurantID)
+	return nil
+}
+
+func (s *Service) validateComboSlots(ctx context.Context, slots []models.CreateComboSlotRequest) error {
+	menuItemIDs := make([]int, 0)
+	for _, slot := range slots {
+		if slot.MinChoices > slot.MaxChoices || slot.MinChoices > len(slot.Choices) {
+			return common.ErrInvalidComboSelection
+		}
+
+		seen := make(map[int]bool)
+		for _, choice := range slot.Choices {
+			if seen[choice.MenuItemID] {
+				return common.ErrInvalidComboSelection
+			}
+			seen[choice.MenuItemID] = true
+			menuItemIDs = append(menuItemIDs, choice.MenuItemID)
+		}
+	}
+
+	menuItemMap, err := s.getMenuItemMap(ctx, menuItemIDs)
+	if err != nil {
+		return err
+	}
+
+	for _, menuItemID := range menuItemIDs {
+		menuItem, ok := menuItemMap[menuItemID]
+		if !ok || menuItem.IsDeleted {
+			return gorm.ErrRecordNotFound
+		}
+	}
+
+	return nil
+}
+
+func createComboSlots(tx *gorm.DB, comboID int, slots []models.CreateComboSlotRequest) error {
+	for _, slotRequest := range slots {
+		slot := &models.ComboSlot{
+			ComboID:      comboID,
+			Name:         slotRequest.Name,
+			MinChoices:   slotRequest.MinChoices,
+			MaxChoices:   slotRequest.MaxChoices,
+			DisplayOrder: slotRequest.DisplayOrder,
+		}
+		if err := tx.Create(slot).Error; err != nil {
+			return err
+		}
+
+		choices := make([]*models.ComboSlotChoice, 0, len(slotRequest.Choices))
+		for _, choiceRequest := range slotRequest.Choices {
+			choices = append(choices, &models.ComboSlotChoice{
+				SlotID:       slot.ID,
+				MenuItemID:   choiceRequest.MenuItemID,
+				Upcharge:     choiceRequest.Upcharge,
+				IsDefault:    choiceRequest.IsDefault,
+				DisplayOrder: choiceRequest.DisplayOrder,
+			})
+		}
+		if err := tx.Create(choices).Error; err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
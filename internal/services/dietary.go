@@ -0,0 +1,81 @@
+package services
+
+import (
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"encoding/json"
+	"strings"
+
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// dietaryFilters turns the guest facing filters into clauses on menu_items.
+// Only the item's own allergens count; optional modifiers are shown with their own flags.
+func dietaryFilters(filter models.DietaryFilter) []repositories.Clause {
+	filters := []repositories.Clause{}
+
+	if allergens := splitFilterValues(filter.ExcludeAllergens); len(allergens) > 0 {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("NOT (menu_items.allergens && ?)", pq.StringArray(allergens))
+		})
+	}
+
+	if tags := splitFilterValues(filter.Dietary); len(tags) > 0 {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("menu_items.dietary_tags @> ?", pq.StringArray(tags))
+		})
+	}
+
+	if filter.MaxSpicyLevel != nil {
+		maxSpicyLevel := *filter.MaxSpicyLevel
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("COALESCE(menu_items.spicy_level, 0) <= ?", maxSpicyLevel)
+		})
+	}
+
+	return filters
+}
+
+func splitFilterValues(value *string) []string {
+	if value == nil || *value == "" {
+		return nil
+	}
+
+	values := make([]string, 0)
+	for _, part := range strings.Split(*value, ",") {
+		part = strings.ToLower(strings.TrimSpace(part))
+		if part != "" {
+			values = append(values, part)
+		}
+	}
+
+	return values
+}
+
+// toStringArray keeps NOT NULL text[] columns from being written as NULL
+func toStringArray(values []string) pq.StringArray {
+	if values == nil {
+		return pq.StringArray{}
+	}
+	return pq.StringArray(values)
+}
+
+func dietaryColumns(info models.DietaryInfo, columns map[string]interface{}) {
+	if info.Allergens != nil {
+		columns["allergens"] = toStringArray(info.Allergens)
+	}
+	if info.DietaryTags != nil {
+		columns["dietary_tags"] = toStringArray(info.DietaryTags)
+	}
+	if info.SpicyLevel != nil {
+		columns["spicy_level"] = *info.SpicyLevel
+	}
+	if info.Nutrition != nil {
+		// map updates skip the model serializer, so the JSON is written by hand
+		if nutrition, err := json.Marshal(info.Nutrition); err == nil {
+			columns["nutrition"] = datatypes.JSON(nutrition)
+		}
+	}
+}
@@ -0,0 +1,320 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"errors"
+	"slices"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Service) GetFloorPlans(ctx context.Context, request *models.ListFloorPlansRequest) ([]*models.FloorPlan, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	return s.floorPlanRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"},
+	}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ?", restaurantID)
+	})
+}
+
+// GetFloorPlanByID returns the plan with its areas and tables, each table with its live status,
+// open orders, upcoming reservations and group
+func (s *Service) GetFloorPlanByID(ctx context.Context, id int) (*models.FloorPlan, error) {
+	plan, err := s.floorPlanRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrFloorPlanNotFound
+		}
+		return nil, err
+	}
+
+	db := s.floorPlanRepo.GetDB().WithContext(ctx)
+	plan.Areas = []*models.FloorPlanArea{}
+	if err := db.Where("floor_plan_id = ?", plan.ID).Order("id").Find(&plan.Areas).Error; err != nil {
+		return nil, err
+	}
+
+	var layouts []*models.TableLayout
+	if err := db.Where("floor_plan_id = ?", plan.ID).Order("table_id").Find(&layouts).Error; err != nil {
+		return nil, err
+	}
+
+	tableIDs := make([]int, 0, len(layouts))
+	for _, layout := range layouts {
+		tableIDs = append(tableIDs, layout.TableID)
+	}
+	tables, err := s.getLiveTables(ctx, tableIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	plan.Tables = make([]*models.FloorPlanTable, 0, len(layouts))
+	for _, layout := range layouts {
+		plan.Tables = append(plan.Tables, &models.FloorPlanTable{
+			TableLayout: *layout,
+			Table:       tables[layout.TableID],
+		})
+	}
+
+	return plan, nil
+}
+
+func (s *Service) CreateFloorPlan(ctx context.Context, request *models.CreateFloorPlanRequest) (*models.FloorPlan, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	plan := &models.FloorPlan{
+		RestaurantID: restaurantID,
+		Name:         request.Name,
+		Width:        request.Width,
+		Height:       request.Height,
+	}
+	if request.DisplayOrder != nil {
+		plan.DisplayOrder = *request.DisplayOrder
+	}
+
+	created, err := s.floorPlanRepo.Create(ctx, plan)
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetFloorPlanByID(ctx, created.ID)
+}
+
+// SaveFloorPlanLayout saves the areas and tables of a plan in one go, as the floor editor saves
+// them. Tables are upserted, so a save with only the moved tables leaves the others in place;
+// areas are only replaced when sent. Everything must fit inside the plan, rotation aside.
+func (s *Service) SaveFloorPlanLayout(ctx context.Context, id int, request *models.SaveFloorPlanLayoutRequest) (*models.FloorPlan, error) {
+	err := s.floorPlanRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var plan models.FloorPlan
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&plan, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common.ErrFloorPlanNotFound
+			}
+			return err
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{
+			"updated_at": now,
+		}
+		if request.Name != nil {
+			columns["name"] = *request.Name
+		}
+		if request.Width != nil {
+			plan.Width = *request.Width
+			columns["width"] = *request.Width
+		}
+		if request.Height != nil {
+			plan.Height = *request.Height
+			columns["height"] = *request.Height
+		}
+		if request.DisplayOrder != nil {
+			columns["display_order"] = *request.DisplayOrder
+		}
+
+		fits := func(x, y, width, height float64) bool {
+			return x+width <= plan.Width && y+height <= plan.Height
+		}
+
+		areas := make([]*models.FloorPlanArea, 0, len(request.Areas))
+		zoneIDs := make([]int, 0, len(request.Areas))
+		for _, area := range request.Areas {
+			if !fits(area.X, area.Y, area.Width, area.Height) {
+				return common.ErrInvalidFloorPlanLayout
+			}
+			if !slices.Contains(zoneIDs, area.ZoneID) {
+				zoneIDs = append(zoneIDs, area.ZoneID)
+			}
+			areas = append(areas, &models.FloorPlanArea{
+				FloorPlanID: plan.ID,
+				ZoneID:      area.ZoneID,
+				Name:        area.Name,
+				X:           area.X,
+				Y:           area.Y,
+				Width:       area.Width,
+				Height:      area.Height,
+				Color:       area.Color,
+			})
+		}
+
+		tableIDs := make([]int, 0, len(request.Tables))
+		layouts := make([]*models.TableLayout, 0, len(request.Tables))
+		seen := make(map[int]bool, len(request.Tables))
+		for _, table := range request.Tables {
+			if seen[table.TableID] || slices.Contains(request.RemovedTableIDs, table.TableID) ||
+				!fits(table.X, table.Y, table.Width, table.Height) {
+				return common.ErrInvalidFloorPlanLayout
+			}
+			seen[table.TableID] = true
+
+			shape := table.Shape
+			if shape == "" {
+				shape = models.TableShapeRectangle
+			}
+			tableIDs = append(tableIDs, table.TableID)
+			layouts = append(layouts, &models.TableLayout{
+				TableID:     table.TableID,
+				FloorPlanID: plan.ID,
+				X:           table.X,
+				Y:           table.Y,
+				Width:       table.Width,
+				Height:      table.Height,
+				Rotation:    table.Rotation,
+				Shape:       shape,
+				UpdatedAt:   &now,
+			})
+		}
+
+		if len(zoneIDs) > 0 {
+			var found int64
+			if err := tx.Model(&models.Zone{}).
+				Where("id IN ? AND restaurant_id = ?", zoneIDs, plan.RestaurantID).
+				Count(&found).Error; err != nil {
+				return err
+			}
+			if int(found) != len(zoneIDs) {
+				return common.ErrZoneNotFound
+			}
+		}
+
+		if len(tableIDs) > 0 {
+			var found int64
+			if err := tx.Model(&models.Table{}).
+				Where("id IN ? AND restaurant_id = ?", tableIDs, plan.RestaurantID).
+				Count(&found).Error; err != nil {
+				return err
+			}
+			if int(found) != len(tableIDs) {
+				return gorm.ErrRecordNotFound
+			}
+		}
+
+		if err := tx.Model(&plan).Updates(columns).Error; err != nil {
+			return err
+		}
+
+		if request.Areas != nil {
+			if err := tx.Where("floor_plan_id = ?", plan.ID).Delete(&models.FloorPlanArea{}).Error; err != nil {
+				return err
+			}
+			if len(areas) > 0 {
+				if err := tx.Create(&areas).Error; err != nil {
+					return err
+				}
+			}
+		}
+
+		if len(request.RemovedTableIDs) > 0 {
+			if err := tx.Where("floor_plan_id = ? AND table_id IN ?", plan.ID, request.RemovedTableIDs).
+				Delete(&models.TableLayout{}).Error; err != nil {
+				return err
+			}
+		}
+		if len(layouts) > 0 {
+			// a table placed on another plan moves here
+			if err := tx.Clauses(clause.OnConflict{
+				Columns:   []clause.Column{{Name: "table_id"}},
+				UpdateAll: true,
+			}).Create(&layouts).Error; err != nil {
+				return err
+			}
+		}
+
+		// what was kept from before must still fit a plan that got smaller
+		if request.Width != nil || request.Height != nil {
+			var outside int64
+			if err := tx.Model(&models.TableLayout{}).
+				Where("floor_plan_id = ? AND (x + width > ? OR y + height > ?)", plan.ID, plan.Width, plan.Height).
+				Count(&outside).Error; err != nil {
+				return err
+			}
+			if outside == 0 {
+				if err := tx.Model(&models.FloorPlanArea{}).
+					Where("floor_plan_id = ? AND (x + width > ? OR y + height > ?)", plan.ID, plan.Width, plan.Height).
+					Count(&outside).Error; err != nil {
+					return err
+				}
+			}
+			if outside > 0 {
+				return common.ErrInvalidFloorPlanLayout
+			}
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetFloorPlanByID(ctx, id)
+}
+
+// DeleteFloorPlan removes the plan with its areas; its tables are no longer placed anywhere
+func (s *Service) DeleteFloorPlan(ctx context.Context, id int) error {
+	if _, err := s.floorPlanRepo.GetByID(ctx, id); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return common.ErrFloorPlanNotFound
+		}
+		return err
+	}
+
+	return s.floorPlanRepo.Delete(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ?", id)
+	})
+}
+
+// getLiveTables loads tables the way the table list shows them: status, open orders of occupied
+// tables, upcoming reservations and group
+func (s *Service) getLiveTables(ctx context.Context, tableIDs []int) (map[int]*models.TableWithOrderData, error) {
+	result := make(map[int]*models.TableWithOrderData, len(tableIDs))
+	if len(tableIDs) == 0 {
+		return result, nil
+	}
+
+	tables, err := s.tableRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("id IN ?", tableIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	reservationMap := s.getUpcomingReservationMap(ctx, tableIDs)
+	groupMap := s.getTableGroupMap(ctx, tableIDs)
+	orderDataMap, err := s.getTableOrderDataMap(ctx, tableIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, table := range tables {
+		item := &models.TableWithOrderData{
+			ID:                   table.ID,
+			RestaurantId:         table.RestaurantId,
+			TableNumber:          table.TableNumber,
+			Capacity:             table.Capacity,
+			ZoneID:               table.ZoneID,
+			Location:             table.Location,
+			Status:               table.Status,
+			UpcomingReservations: reservationMap[table.ID],
+			Group:                groupMap[table.ID],
+		}
+
+		if table.Status == "occupied" {
+			item.OrderData = orderDataMap[table.ID]
+		}
+
+		result[table.ID] = item
+	}
+
+	return result, nil
+}
@@ -0,0 +1,666 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"math"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Service) GetIngredients(ctx context.Context, request *models.ListIngredientRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	filters := []repositories.Clause{}
+
+	if request.Status != nil && *request.Status != "" && *request.Status != "all" {
+		status := *request.Status
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("status = ?", status)
+		})
+	}
+
+	if request.Search != nil && *request.Search != "" {
+		search := "%" + strings.ToLower(*request.Search) + "%"
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("LOWER(name) LIKE ?", search)
+		})
+	}
+
+	if request.LowStock != nil && *request.LowStock {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("low_stock_threshold IS NOT NULL AND stock_quantity <= low_stock_threshold")
+		})
+	}
+
+	totalCount, err := s.ingredientRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalCount == 0 {
+		return &models.BaseListResponse{
+			Total:    0,
+			Page:     page,
+			PageSize: pageSize,
+			Items:    []*models.IngredientResponse{},
+		}, nil
+	}
+
+	queryParams := models.QueryParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+	}
+
+	sortMap := map[string]string{
+		"default": "name.asc",
+		"stock":   "stock_quantity.asc",
+		"recent":  "updated_at.desc",
+	}
+
+	if sortOrder, ok := sortMap[request.Sort]; ok {
+		queryParams.QuerySort.Origin = sortOrder
+	} else {
+		queryParams.QuerySort.Origin = sortMap["default"]
+	}
+
+	ingredients, err := s.ingredientRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	ingredientIDs := make([]int, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		ingredientIDs = append(ingredientIDs, ingredient.ID)
+	}
+
+	usedByMap, err := s.recipeLineRepo.CountGroupByInt(ctx, "ingredient_id", func(tx *gorm.DB) {
+		tx.Where("ingredient_id IN ?", ingredientIDs)
+	})
+	if err != nil {
+		usedByMap = make(map[int]int)
+	}
+
+	items := make([]*models.IngredientResponse, 0, len(ingredients))
+	for _, ingredient := range ingredients {
+		items = append(items, toIngredientResponse(ingredient, usedByMap[ingredient.ID]))
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    items,
+	}, nil
+}
+
+func toIngredientResponse(ingredient *models.Ingredient, usedBy int) *models.IngredientResponse {
+	return &models.IngredientResponse{
+		ID:                ingredient.ID,
+		Name:              ingredient.Name,
+		Unit:              ingredient.Unit,
+		StockQuantity:     ingredient.StockQuantity,
+		LowStockThreshold: ingredient.LowStockThreshold,
+		IsLowStock:        ingredient.LowStockThreshold != nil && ingredient.StockQuantity <= *ingredient.LowStockThreshold,
+		Status:            ingredient.Status,
+		UsedBy:            usedBy,
+	}
+}
+
+func (s *Service) GetIngredientByID(ctx context.Context, id int) (*models.IngredientResponse, error) {
+	ingredient, err := s.ingredientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	usedBy, err := s.recipeLineRepo.Count(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("ingredient_id = ?", id)
+	})
+	if err != nil {
+		usedBy = 0
+	}
+
+	return toIngredientResponse(ingredient, int(usedBy)), nil
+}
+
+func (s *Service) CreateIngredient(ctx context.Context, request *models.CreateIngredientRequest) (*models.Ingredient, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	ingredient := &models.Ingredient{
+		RestaurantID:      restaurantID,
+		Name:              request.Name,
+		Unit:              request.Unit,
+		LowStockThreshold: request.LowStockThreshold,
+		Status:            request.Status,
+	}
+
+	err := s.ingredientRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(ingredient).Error; err != nil {
+			return common.PgErrorTransform(err)
+		}
+
+		if request.StockQuantity <= 0 {
+			return nil
+		}
+
+		reason := "opening stock"
+		movement, err := applyIngredientMovement(tx, ingredient.ID, models.MovementRestock, request.StockQuantity, nil, &reason)
+		if err != nil {
+			return err
+		}
+
+		ingredient.StockQuantity = movement.StockAfter
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return ingredient, nil
+}
+
+func (s *Service) UpdateIngredient(ctx context.Context, id int, request *models.UpdateIngredientRequest) (*models.Ingredient, error) {
+	existing, err := s.ingredientRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	columns := make(map[string]interface{})
+	if request.Name != nil {
+		columns["name"] = *request.Name
+	}
+	if request.Unit != nil {
+		columns["unit"] = *request.Unit
+	}
+	if request.LowStockThreshold != nil {
+		columns["low_stock_threshold"] = *request.LowStockThreshold
+	}
+	if request.Status != nil {
+		columns["status"] = *request.Status
+	}
+
+	if len(columns) == 0 {
+		return existing, nil
+	}
+
+	columns["updated_at"] = time.Now()
+
+	updated, err := s.ingredientRepo.UpdateColumns(ctx, id, columns)
+	if err != nil {
+		return nil, common.PgErrorTransform(err)
+	}
+
+	return updated, nil
+}
+
+func (s *Service) DeleteIngredient(ctx context.Context, id int) error {
+	_, err := s.ingredientRepo.DeleteByID(ctx, id)
+	return err
+}
+
+func (s *Service) GetMenuItemRecipe(ctx context.Context, menuItemID int) ([]*models.RecipeLineResponse, error) {
+	if _, err := s.getActiveMenuItem(ctx, menuItemID); err != nil {
+		return nil, err
+	}
+
+	return s.getRecipe(ctx, models.StockEntityMenuItem, menuItemID)
+}
+
+func (s *Service) GetModifierOptionRecipe(ctx context.Context, optionID int) ([]*models.RecipeLineResponse, error) {
+	if _, err := s.modifierOptionRepo.GetByID(ctx, optionID); err != nil {
+		return nil, err
+	}
+
+	return s.getRecipe(ctx, models.StockEntityModifierOption, optionID)
+}
+
+func (s *Service) UpdateMenuItemRecipe(ctx context.Context, menuItemID int, request *models.UpdateRecipeRequest) ([]*models.RecipeLineResponse, error) {
+	if _, err := s.getActiveMenuItem(ctx, menuItemID); err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceRecipe(ctx, models.StockEntityMenuItem, menuItemID, request.Lines); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+
+	return s.getRecipe(ctx, models.StockEntityMenuItem, menuItemID)
+}
+
+func (s *Service) UpdateModifierOptionRecipe(ctx context.Context, optionID int, request *models.UpdateRecipeRequest) ([]*models.RecipeLineResponse, error) {
+	if _, err := s.modifierOptionRepo.GetByID(ctx, optionID); err != nil {
+		return nil, err
+	}
+
+	if err := s.replaceRecipe(ctx, models.StockEntityModifierOption, optionID, request.Lines); err != nil {
+		return nil, err
+	}
+
+	s.invalidateModifierOptionCache(ctx, optionID)
+
+	return s.getRecipe(ctx, models.StockEntityModifierOption, optionID)
+}
+
+func (s *Service) getRecipe(ctx context.Context, entityType string, entityID int) ([]*models.RecipeLineResponse, error) {
+	column := stockTargets[entityType].recipeColumn
+
+	lines, err := s.recipeLineRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "id.asc"}}, func(tx *gorm.DB) {
+		tx.Where(column+" = ?", entityID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if len(lines) == 0 {
+		return []*models.RecipeLineResponse{}, nil
+	}
+
+	ingredientIDs := make([]int, 0, len(lines))
+	for _, line := range lines {
+		ingredientIDs = append(ingredientIDs, line.IngredientID)
+	}
+
+	ingredients, err := s.ingredientRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("id IN ?", ingredientIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	ingredientMap := make(map[int]*models.Ingredient, len(ingredients))
+	for _, ingredient := range ingredients {
+		ingredientMap[ingredient.ID] = ingredient
+	}
+
+	responses := make([]*models.RecipeLineResponse, 0, len(lines))
+	for _, line := range lines {
+		ingredient, ok := ingredientMap[line.IngredientID]
+		if !ok {
+			continue
+		}
+
+		responses = append(responses, &models.RecipeLineResponse{
+			IngredientID:   ingredient.ID,
+			IngredientName: ingredient.Name,
+			Unit:           ingredient.Unit,
+			Quantity:       line.Quantity,
+			InStock:        ingredient.StockQuantity >= line.Quantity,
+		})
+	}
+
+	return responses, nil
+}
+
+func (s *Service) replaceRecipe(ctx context.Context, entityType string, entityID int, lines []models.RecipeLineRequest) error {
+	column := stockTargets[entityType].recipeColumn
+
+	return s.ingredientRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where(column+" = ?", entityID).Delete(&models.RecipeLine{}).Error; err != nil {
+			return err
+		}
+
+		if len(lines) == 0 {
+			return nil
+		}
+
+		ingredientIDs := make([]int, 0, len(lines))
+		recipe := make([]*models.RecipeLine, 0, len(lines))
+		for _, line := range lines {
+			recipeLine := &models.RecipeLine{
+				IngredientID: line.IngredientID,
+				Quantity:     line.Quantity,
+			}
+			id := entityID
+			if entityType == models.StockEntityMenuItem {
+				recipeLine.MenuItemID = &id
+			} else {
+				recipeLine.ModifierOptionID = &id
+			}
+			recipe = append(recipe, recipeLine)
+			ingredientIDs = append(ingredientIDs, line.IngredientID)
+		}
+
+		if err := tx.Create(recipe).Error; err != nil {
+			return common.PgErrorTransform(err)
+		}
+
+		return refreshIngredientAvailability(tx, ingredientIDs)
+	})
+}
+
+func (s *Service) CreateStockMovement(ctx context.Context, ingredientID int, request *models.CreateStockMovementRequest) (*models.StockMovement, error) {
+	var movement *models.StockMovement
+
+	var restaurantID int
+	err := s.ingredientRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		ingredient, err := lockIngredient(tx, ingredientID)
+		if err != nil {
+			return err
+		}
+		restaurantID = ingredient.RestaurantID
+
+		delta := request.Quantity
+		switch request.MovementType {
+		case models.MovementWaste:
+			delta = -request.Quantity
+		case models.MovementAdjustment:
+			delta = request.Quantity - ingredient.StockQuantity
+		}
+
+		movement, err = applyIngredientMovement(tx, ingredientID, request.MovementType, delta, nil, request.Reason)
+		if err != nil {
+			return err
+		}
+
+		return refreshIngredientAvailability(tx, []int{ingredientID})
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	// the movement may have sold out (or brought back) items made from the ingredient
+	s.invalidateMenuCache(ctx, restaurantID)
+
+	return movement, nil
+}
+
+func (s *Service) GetStockMovements(ctx context.Context, request *models.ListStockMovementRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	filters := []repositories.Clause{}
+
+	if request.IngredientID != nil {
+		ingredientID := *request.IngredientID
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("ingredient_id = ?", ingredientID)
+		})
+	}
+
+	if request.MovementType != nil && *request.MovementType != "" && *request.MovementType != "all" {
+		movementType := *request.MovementType
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("movement_type = ?", movementType)
+		})
+	}
+
+	if request.From != nil {
+		from := *request.From
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("created_at >= ?", from)
+		})
+	}
+
+	if request.To != nil {
+		to := request.To.AddDate(0, 0, 1)
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("created_at < ?", to)
+		})
+	}
+
+	totalCount, err := s.stockMovementRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalCount == 0 {
+		return &models.BaseListResponse{
+			Total:    0,
+			Page:     page,
+			PageSize: pageSize,
+			Items:    []*models.StockMovement{},
+		}, nil
+	}
+
+	queryParams := models.QueryParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+		QuerySort: models.QuerySort{
+			Origin: "created_at.desc",
+		},
+	}
+
+	movements, err := s.stockMovementRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    movements,
+	}, nil
+}
+
+// GetIngredientUsageReport compares what recipes say should have been used (theoretical) with
+// everything that actually left stock: orders, waste and stock count corrections (actual).
+func (s *Service) GetIngredientUsageReport(ctx context.Context, request *models.IngredientUsageReportRequest) (*models.IngredientUsageReport, error) {
+	to := time.Now()
+	if request.To != nil {
+		to = *request.To
+	}
+	from := to.AddDate(0, 0, -7)
+	if request.From != nil {
+		from = *request.From
+	}
+
+	start, _ := common.GetStartEndOfDay(from)
+	_, end := common.GetStartEndOfDay(to)
+	if !start.Before(end) {
+		return nil, common.ErrCodeInvalidTimeRange
+	}
+
+	var usages []*models.IngredientUsageResponse
+	err := s.ingredientRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT
+			i.id AS ingredient_id,
+			i.name,
+			i.unit,
+			COALESCE(-SUM(m.quantity) FILTER (WHERE m.movement_type IN ('order_consumption', 'order_restore')), 0) AS theoretical_usage,
+			COALESCE(-SUM(m.quantity) FILTER (WHERE m.movement_type <> 'restock'), 0) AS actual_usage,
+			COALESCE(-SUM(m.quantity) FILTER (WHERE m.movement_type = 'waste'), 0) AS waste
+		FROM ingredients i
+		LEFT JOIN stock_movements m
+			ON m.ingredient_id = i.id AND m.created_at >= ? AND m.created_at < ?
+		GROUP BY i.id, i.name, i.unit
+		ORDER BY i.name
+	`, start, end).Scan(&usages).Error
+	if err != nil {
+		return nil, err
+	}
+
+	for _, usage := range usages {
+		usage.Variance = roundQuantity(usage.ActualUsage - usage.TheoreticalUsage)
+		if usage.TheoreticalUsage > 0 {
+			usage.VariancePercent = math.Round(usage.Variance/usage.TheoreticalUsage*10000) / 100
+		}
+	}
+
+	return &models.IngredientUsageReport{
+		From:        start.Format("2006-01-02"),
+		To:          end.AddDate(0, 0, -1).Format("2006-01-02"),
+		Ingredients: usages,
+	}, nil
+}
+
+// consumeOrderIngredients takes the ingredients of every item and option in an order out of stock
+func consumeOrderIngredients(tx *gorm.DB, orderID int, menuItems map[int]int, options map[int]int) error {
+	usage := make(map[int]float64)
+
+	if len(menuItems) > 0 {
+		var lines []*models.RecipeLine
+		if err := tx.Where("menu_item_id IN ?", sortedKeys(menuItems)).Find(&lines).Error; err != nil {
+			return err
+		}
+		for _, line := range lines {
+			usage[line.IngredientID] += line.Quantity * float64(menuItems[*line.MenuItemID])
+		}
+	}
+
+	if len(options) > 0 {
+		var lines []*models.RecipeLine
+		if err := tx.Where("modifier_option_id IN ?", sortedKeys(options)).Find(&lines).Error; err != nil {
+			return err
+		}
+		for _, line := range lines {
+			usage[line.IngredientID] += line.Quantity * float64(options[*line.ModifierOptionID])
+		}
+	}
+
+	if len(usage) == 0 {
+		return nil
+	}
+
+	ingredientIDs := sortedKeys(usage)
+	for _, ingredientID := range ingredientIDs {
+		if _, err := applyIngredientMovement(tx, ingredientID, models.MovementOrderConsumption, -usage[ingredientID], &orderID, nil); err != nil {
+			return err
+		}
+	}
+
+	return refreshIngredientAvailability(tx, ingredientIDs)
+}
+
+// restoreOrderIngredients reverses exactly what was consumed for the order, even if recipes changed since
+func restoreOrderIngredients(tx *gorm.DB, orderID int) error {
+	var consumed []struct {
+		IngredientID int     `gorm:"column:ingredient_id"`
+		Quantity     float64 `gorm:"column:quantity"`
+	}
+
+	err := tx.Raw(`
+		SELECT ingredient_id, SUM(quantity) AS quantity
+		FROM stock_movements
+		WHERE order_id = ?
+		GROUP BY ingredient_id
+		ORDER BY ingredient_id
+	`, orderID).Scan(&consumed).Error
+	if err != nil {
+		return err
+	}
+
+	ingredientIDs := make([]int, 0, len(consumed))
+	for _, row := range consumed {
+		if row.Quantity >= 0 {
+			continue
+		}
+		if _, err := applyIngredientMovement(tx, row.IngredientID, models.MovementOrderRestore, -row.Quantity, &orderID, nil); err != nil {
+			return err
+		}
+		ingredientIDs = append(ingredientIDs, row.IngredientID)
+	}
+
+	if len(ingredientIDs) == 0 {
+		return nil
+	}
+
+	return refreshIngredientAvailability(tx, ingredientIDs)
+}
+
+func lockIngredient(tx *gorm.DB, id int) (*models.Ingredient, error) {
+	var ingredient models.Ingredient
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&ingredient, "id = ?", id).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &ingredient, nil
+}
+
+func applyIngredientMovement(tx *gorm.DB, ingredientID int, movementType string, delta float64, orderID *int, reason *string) (*models.StockMovement, error) {
+	ingredient, err := lockIngredient(tx, ingredientID)
+	if err != nil {
+		return nil, err
+	}
+
+	stockAfter := roundQuantity(ingredient.StockQuantity + delta)
+	if stockAfter < 0 {
+		return nil, common.ErrInsufficientStock
+	}
+
+	now := time.Now()
+	columns := map[string]interface{}{
+		"stock_quantity": stockAfter,
+		"updated_at":     now,
+	}
+	if err := tx.Model(ingredient).Updates(columns).Error; err != nil {
+		return nil, err
+	}
+
+	movement := &models.StockMovement{
+		IngredientID: ingredientID,
+		MovementType: movementType,
+		Quantity:     roundQuantity(delta),
+		StockAfter:   stockAfter,
+		OrderID:      orderID,
+		Reason:       reason,
+		CreatedAt:    &now,
+	}
+	if err := tx.Create(movement).Error; err != nil {
+		return nil, err
+	}
+
+	return movement, nil
+}
+
+// refreshIngredientAvailability sells out items and options that can no longer be made from the
+// given ingredients, and brings back the ones that can. Item level stock still has the last word.
+func refreshIngredientAvailability(tx *gorm.DB, ingredientIDs []int) error {
+	for _, entityType := range []string{models.StockEntityMenuItem, models.StockEntityModifierOption} {
+		target := stockTargets[entityType]
+
+		err := tx.Exec(`
+			UPDATE `+target.table+` t
+			SET status = CASE WHEN r.in_stock THEN ? ELSE ? END
+			FROM (
+				SELECT rl.`+target.recipeColumn+` AS entity_id, BOOL_AND(i.stock_quantity >= rl.quantity) AS in_stock
+				FROM recipe_lines rl
+				JOIN ingredients i ON i.id = rl.ingredient_id
+				WHERE rl.`+target.recipeColumn+` IN (
+					SELECT `+target.recipeColumn+` FROM recipe_lines WHERE ingredient_id IN ?
+				)
+				GROUP BY rl.`+target.recipeColumn+`
+			) r
+			WHERE t.id = r.entity_id
+				AND (
+					(NOT r.in_stock AND t.status = ?)
+					OR (r.in_stock AND t.status = ? AND (t.stock_quantity IS NULL OR t.stock_quantity > 0))
+				)
+		`, target.available, target.soldOut, ingredientIDs, target.available, target.soldOut).Error
+		if err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// hasIngredientShortage reports whether any ingredient in the recipe is below one portion
+func hasIngredientShortage(tx *gorm.DB, entityType string, entityID int) (bool, error) {
+	column := stockTargets[entityType].recipeColumn
+
+	var count int64
+	err := tx.Table(common.POSTGRES_TABLE_NAME_RECIPE_LINES+" rl").
+		Joins("JOIN ingredients i ON i.id = rl.ingredient_id").
+		Where("rl."+column+" = ? AND i.stock_quantity < rl.quantity", entityID).
+		Count(&count).Error
+	if err != nil {
+		return false, err
+	}
+
+	return count > 0, nil
+}
+
+func roundQuantity(quantity float64) float64 {
+	return math.Round(quantity*1000) / 1000
+}
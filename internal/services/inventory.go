@@ -0,0 +1,412 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sort"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+type stockTarget struct {
+	table        string
+	recipeColumn string
+	available    string
+	soldOut      string
+}
+
+var stockTargets = map[string]stockTarget{
+	models.StockEntityMenuItem: {
+		table:        common.POSTGRES_TABLE_NAME_MENU_ITEMS,
+		recipeColumn: "menu_item_id",
+		available:    "available",
+		soldOut:      "sold_out",
+	},
+	models.StockEntityModifierOption: {
+		table:        common.POSTGRES_TABLE_NAME_MODIFIER_OPTIONS,
+		recipeColumn: "modifier_option_id",
+		available:    "active",
+		soldOut:      "sold_out",
+	},
+}
+
+type stockRow struct {
+	ID                int    `gorm:"column:id"`
+	Status            string `gorm:"column:status"`
+	StockQuantity     *int   `gorm:"column:stock_quantity"`
+	LowStockThreshold *int   `gorm:"column:low_stock_threshold"`
+}
+
+func (s *Service) UpdateMenuItemStock(ctx context.Context, id int, request *models.UpdateStockRequest) (*models.MenuItem, error) {
+	if _, err := s.getActiveMenuItem(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateStock(ctx, models.StockEntityMenuItem, id, request); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuItemCache(ctx, id)
+
+	return s.menuItemRepo.GetByID(ctx, id)
+}
+
+func (s *Service) UpdateModifierOptionStock(ctx context.Context, id int, request *models.UpdateStockRequest) (*models.ModifierOption, error) {
+	if _, err := s.modifierOptionRepo.GetByID(ctx, id); err != nil {
+		return nil, err
+	}
+
+	if err := s.updateStock(ctx, models.StockEntityModifierOption, id, request); err != nil {
+		return nil, err
+	}
+
+	s.invalidateModifierOptionCache(ctx, id)
+
+	return s.modifierOptionRepo.GetByID(ctx, id)
+}
+
+func (s *Service) updateStock(ctx context.Context, entityType string, id int, request *models.UpdateStockRequest) error {
+	quantity, threshold := request.StockQuantity, request.LowStockThreshold
+	if !request.TrackStock {
+		quantity, threshold = nil, nil
+	} else if quantity == nil {
+		return common.ErrInvalidStockRequest
+	}
+
+	return s.menuItemRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		row, err := lockStockRow(tx, entityType, id)
+		if err != nil {
+			return err
+		}
+
+		return s.setStockLevel(tx, entityType, row, quantity, threshold)
+	})
+}
+
+// consumeStock takes quantities (keyed by entity id) out of stock. Untracked rows are skipped,
+// tracked rows without enough stock fail the whole transaction.
+func (s *Service) consumeStock(tx *gorm.DB, entityType string, quantities map[int]int) error {
+	for _, id := range sortedKeys(quantities) {
+		row, err := lockStockRow(tx, entityType, id)
+		if err != nil {
+			return err
+		}
+
+		if row.StockQuantity == nil {
+			continue
+		}
+
+		if *row.StockQuantity < quantities[id] {
+			return common.ErrInsufficientStock
+		}
+
+		remaining := *row.StockQuantity - quantities[id]
+		if err := s.setStockLevel(tx, entityType, row, &remaining, row.LowStockThreshold); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+// restoreStock puts quantities (keyed by entity id) back into stock.
+func (s *Service) restoreStock(tx *gorm.DB, entityType string, quantities map[int]int) error {
+	for _, id := range sortedKeys(quantities) {
+		row, err := lockStockRow(tx, entityType, id)
+		if err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				continue
+			}
+			return err
+		}
+
+		if row.StockQuantity == nil {
+			continue
+		}
+
+		restored := *row.StockQuantity + quantities[id]
+		if err := s.setStockLevel(tx, entityType, row, &restored, row.LowStockThreshold); err != nil {
+			return err
+		}
+	}
+
+	return nil
+}
+
+func lockStockRow(tx *gorm.DB, entityType string, id int) (*stockRow, error) {
+	target := stockTargets[entityType]
+
+	var row stockRow
+	err := tx.Raw(
+		"SELECT id, status, stock_quantity, low_stock_threshold FROM "+target.table+" WHERE id = ? FOR UPDATE",
+		id,
+	).Scan(&row).Error
+	if err != nil {
+		return nil, err
+	}
+
+	if row.ID == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	return &row, nil
+}
+
+// setStockLevel writes the new stock level and flips the status between available and sold out.
+// Rows that were switched off by hand (unavailable / inactive) keep their status.
+func (s *Service) setStockLevel(tx *gorm.DB, entityType string, row *stockRow, quantity *int, threshold *int) error {
+	target := stockTargets[entityType]
+
+	columns := map[string]interface{}{
+		"stock_quantity":      quantity,
+		"low_stock_threshold": threshold,
+	}
+
+	if quantity != nil {
+		if *quantity == 0 && row.Status == target.available {
+			columns["status"] = target.soldOut
+		} else if *quantity > 0 && row.Status == target.soldOut {
+			shortage, err := hasIngredientShortage(tx, entityType, row.ID)
+			if err != nil {
+				return err
+			}
+			if !shortage {
+				columns["status"] = target.available
+			}
+		}
+	}
+
+	if err := tx.Table(target.table).Where("id = ?", row.ID).Updates(columns).Error; err != nil {
+		return err
+	}
+
+	return s.syncStockAlerts(tx, entityType, row.ID, quantity, threshold)
+}
+
+func (s *Service) syncStockAlerts(tx *gorm.DB, entityType string, entityID int, quantity *int, threshold *int) error {
+	alertType := ""
+	if quantity != nil {
+		if *quantity == 0 {
+			alertType = models.StockAlertOutOfStock
+		} else if threshold != nil && *quantity <= *threshold {
+			alertType = models.StockAlertLowStock
+		}
+	}
+
+	now := time.Now()
+	resolve := tx.Model(&models.StockAlert{}).
+		Where("entity_type = ? AND entity_id = ? AND is_resolved = FALSE", entityType, entityID)
+	if alertType != "" {
+		resolve = resolve.Where("alert_type <> ?", alertType)
+	}
+	if err := resolve.Updates(map[string]interface{}{"is_resolved": true, "resolved_at": now}).Error; err != nil {
+		return err
+	}
+
+	if alertType == "" {
+		return nil
+	}
+
+	result := tx.Model(&models.StockAlert{}).
+		Where("entity_type = ? AND entity_id = ? AND alert_type = ? AND is_resolved = FALSE", entityType, entityID, alertType).
+		Updates(map[string]interface{}{"stock_quantity": *quantity, "low_stock_threshold": threshold})
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+
+	s.logger.Warn("stock alert",
+		zap.String("entity_type", entityType),
+		zap.Int("entity_id", entityID),
+		zap.String("alert_type", alertType),
+		zap.Int("stock_quantity", *quantity),
+	)
+
+	return tx.Create(&models.StockAlert{
+		EntityType:        entityType,
+		EntityID:          entityID,
+		AlertType:         alertType,
+		StockQuantity:     *quantity,
+		LowStockThreshold: threshold,
+		CreatedAt:         &now,
+	}).Error
+}
+
+func (s *Service) GetStockAlerts(ctx context.Context, request *models.ListStockAlertRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	filters := []repositories.Clause{}
+
+	if request.EntityType != nil && *request.EntityType != "" && *request.EntityType != "all" {
+		entityType := *request.EntityType
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("entity_type = ?", entityType)
+		})
+	}
+
+	if request.AlertType != nil && *request.AlertType != "" && *request.AlertType != "all" {
+		alertType := *request.AlertType
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("alert_type = ?", alertType)
+		})
+	}
+
+	resolved := false
+	if request.Resolved != nil {
+		resolved = *request.Resolved
+	}
+	filters = append(filters, func(tx *gorm.DB) {
+		tx.Where("is_resolved = ?", resolved)
+	})
+
+	totalCount, err := s.stockAlertRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalCount == 0 {
+		return &models.BaseListResponse{
+			Total:    0,
+			Page:     page,
+			PageSize: pageSize,
+			Items:    []*models.StockAlertResponse{},
+		}, nil
+	}
+
+	queryParams := models.QueryParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+		QuerySort: models.QuerySort{
+			Origin: "created_at.desc",
+		},
+	}
+
+	alerts, err := s.stockAlertRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make([]int, 0, len(alerts))
+	optionIDs := make([]int, 0, len(alerts))
+	for _, alert := range alerts {
+		if alert.EntityType == models.StockEntityMenuItem {
+			itemIDs = append(itemIDs, alert.EntityID)
+		} else {
+			optionIDs = append(optionIDs, alert.EntityID)
+		}
+	}
+
+	names, err := s.getStockEntityNames(ctx, itemIDs, optionIDs)
+	if err != nil {
+		names = make(map[string]map[int]string)
+	}
+
+	items := make([]*models.StockAlertResponse, 0, len(alerts))
+	for _, alert := range alerts {
+		items = append(items, &models.StockAlertResponse{
+			ID:                alert.ID,
+			EntityType:        alert.EntityType,
+			EntityID:          alert.EntityID,
+			EntityName:        names[alert.EntityType][alert.EntityID],
+			AlertType:         alert.AlertType,
+			StockQuantity:     alert.StockQuantity,
+			LowStockThreshold: alert.LowStockThreshold,
+			IsResolved:        alert.IsResolved,
+			CreatedAt:         alert.CreatedAt,
+			ResolvedAt:        alert.ResolvedAt,
+		})
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    items,
+	}, nil
+}
+
+func (s *Service) getStockEntityNames(ctx context.Context, itemIDs []int, optionIDs []int) (map[string]map[int]string, error) {
+	names := map[string]map[int]string{
+		models.StockEntityMenuItem:       make(map[int]string),
+		models.StockEntityModifierOption: make(map[int]string),
+	}
+
+	if len(itemIDs) > 0 {
+		menuItems, err := s.menuItemRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ?", itemIDs)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, item := range menuItems {
+			names[models.StockEntityMenuItem][item.ID] = item.Name
+		}
+	}
+
+	if len(optionIDs) > 0 {
+		options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ?", optionIDs)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, option := range options {
+			names[models.StockEntityModifierOption][option.ID] = option.Name
+		}
+	}
+
+	return names, nil
+}
+
+// orderStockQuantities sums up how many of each menu item and modifier option an order uses. An
+// item whose meta does not parse fails the whole order rather than skipping its stock.
+func orderStockQuantities(items []*models.OrderItem) (map[int]int, map[int]int, error) {
+	menuItems := make(map[int]int)
+	options := make(map[int]int)
+
+	for _, item := range items {
+		if item.MenuItemID != nil {
+			menuItems[*item.MenuItemID] += item.Quantity
+		}
+
+		if len(item.Meta) == 0 {
+			continue
+		}
+
+		var meta models.OrderItemMeta
+		if err := json.Unmarshal(item.Meta, &meta); err != nil {
+			return nil, nil, fmt.Errorf("order item %d has invalid meta: %w", item.ID, err)
+		}
+		for _, optionID := range meta.ModifierOptionIDs {
+			options[optionID] += item.Quantity
+		}
+		for _, choice := range meta.ComboChoices {
+			menuItems[choice.MenuItemID] += item.Quantity
+			for _, optionID := range choice.ModifierOptionIDs {
+				options[optionID] += item.Quantity
+			}
+		}
+	}
+
+	return menuItems, options, nil
+}
+
+// sortedKeys keeps lock order stable between concurrent transactions
+func sortedKeys[V any](m map[int]V) []int {
+	keys := make([]int, 0, len(m))
+	for k := range m {
+		keys = append(keys, k)
+	}
+	sort.Ints(keys)
+	return keys
+}
@@ -7,16 +7,25 @@ import (
 	"app-noti/pkg/utils"
 	"context"
 	"fmt"
+	"sort"
 	"strconv"
 	"strings"
+	"time"
 
+	"github.com/lib/pq"
+	"go.uber.org/zap"
 	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
 )
 
 func (s *Service) GetMenuCategories(ctx context.Context, request *models.ListMenuCategoryRequest) (*models.BaseListResponse, error) {
 	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
 
-	filters := []repositories.Clause{}
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("is_deleted = FALSE")
+		},
+	}
 
 	if request.Status != nil && *request.Status != "" && *request.Status != "all" {
 		status := *request.Status
@@ -143,7 +152,7 @@ func (s *Service) getCategoryItemCounts(ctx context.Context, categoryIDs []int)
 }
 
 func (s *Service) GetMenuCategoryByID(ctx context.Context, id int) (*models.MenuCategoryDetailResponse, error) {
-	category, err := s.menuCategoryRepo.GetByID(ctx, id)
+	category, err := s.getActiveMenuCategory(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -177,7 +186,7 @@ func (s *Service) CreateMenuCategory(ctx context.Context, request *models.Create
 	} else {
 		filters := []repositories.Clause{
 			func(tx *gorm.DB) {
-				tx.Where("restaurant_id = ?", restaurantID)
+				tx.Where("restaurant_id = ? AND is_deleted = FALSE", restaurantID)
 			},
 		}
 		categories, err := s.menuCategoryRepo.List(ctx, models.QueryParams{
@@ -205,11 +214,13 @@ func (s *Service) CreateMenuCategory(ctx context.Context, request *models.Create
 		return nil, err
 	}
 
+	s.invalidateMenuCache(ctx, restaurantID)
+
 	return created, nil
 }
 
 func (s *Service) UpdateMenuCategory(ctx context.Context, id int, request *models.UpdateMenuCategoryRequest) (*models.MenuCategory, error) {
-	existing, err := s.menuCategoryRepo.GetByID(ctx, id)
+	existing, err := s.getActiveMenuCategory(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -237,11 +248,13 @@ func (s *Service) UpdateMenuCategory(ctx context.Context, id int, request *model
 		return nil, err
 	}
 
+	s.invalidateMenuCache(ctx, existing.RestaurantID)
+
 	return updated, nil
 }
 
 func (s *Service) UpdateMenuCategoryStatus(ctx context.Context, id int, request *models.UpdateMenuCategoryStatusRequest) (*models.MenuCategory, error) {
-	_, err := s.menuCategoryRepo.GetByID(ctx, id)
+	existing, err := s.getActiveMenuCategory(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -260,6 +273,8 @@ func (s *Service) UpdateMenuCategoryStatus(ctx context.Context, id int, request
 		return nil, err
 	}
 
+	s.invalidateMenuCache(ctx, existing.RestaurantID)
+
 	return updated, nil
 }
 
@@ -294,13 +309,13 @@ func (s *Service) GetMenuItems(ctx context.Context, request *models.ListMenuItem
 		})
 	}
 
-	if request.Search != nil && *request.Search != "" {
-		search := "%" + strings.ToLower(*request.Search) + "%"
-		filters = append(filters, func(tx *gorm.DB) {
-			tx.Where("LOWER(menu_items.name) LIKE ?", search)
-		})
+	search := newMenuSearch(request.Search)
+	if search != nil {
+		filters = append(filters, search.filter(""))
 	}
 
+	filters = append(filters, dietaryFilters(request.DietaryFilter)...)
+
 	totalCount, err := s.menuItemRepo.Count(ctx, models.QueryParams{}, filters...)
 	if err != nil {
 		return nil, err
@@ -321,20 +336,25 @@ func (s *Service) GetMenuItems(ctx context.Context, request *models.ListMenuItem
 	}
 
 	sortMap := map[string]string{
-		"default":     "id.asc",
-		"name":        "name.asc",
-		"price_asc":   "price.asc",
-		"price_desc":  "price.desc",
-		"last_update": "updated_at.desc",
+		"default":       "id.asc",
+		"display_order": "display_order.asc,id.asc",
+		"name":          "name.asc",
+		"price_asc":     "price.asc",
+		"price_desc":    "price.desc",
+		"last_update":   "updated_at.desc",
 	}
 
+	listFilters := filters
 	if sortOrder, ok := sortMap[request.Sort]; ok {
 		queryParams.QuerySort.Origin = sortOrder
+	} else if search != nil {
+		// best matches first unless another order was asked for
+		listFilters = append(append([]repositories.Clause{}, filters...), search.order())
 	} else {
 		queryParams.QuerySort.Origin = sortMap["default"]
 	}
 
-	menuItems, err := s.menuItemRepo.List(ctx, queryParams, filters...)
+	menuItems, err := s.menuItemRepo.List(ctx, queryParams, listFilters...)
 	if err != nil {
 		return nil, err
 	}
@@ -351,7 +371,7 @@ func (s *Service) GetMenuItems(ctx context.Context, request *models.ListMenuItem
 		categoryMap = make(map[int]string)
 	}
 
-	primaryImageMap, err := s.getPrimaryImageMap(ctx, itemIDs)
+	primaryImageMap, err := s.getPrimaryImageMap(ctx, itemIDs, "")
 	if err != nil {
 		primaryImageMap = make(map[int]string)
 	}
@@ -383,14 +403,22 @@ func (s *Service) GetMenuItems(ctx context.Context, request *models.ListMenuItem
 			Status:          displayStatus,
 			LastUpdate:      lastUpdate,
 			ChefRecommended: menuItem.IsChefRecommended,
+			DisplayOrder:    menuItem.DisplayOrder,
 			ImageURL:        primaryImageMap[menuItem.ID],
 			Description:     menuItem.Description,
 			PreparationTime: menuItem.PrepTimeMinutes,
+			StockQuantity:   menuItem.StockQuantity,
+			Allergens:       toStringArray(menuItem.Allergens),
+			DietaryTags:     toStringArray(menuItem.DietaryTags),
+			SpicyLevel:      menuItem.SpicyLevel,
+			Nutrition:       menuItem.Nutrition,
 		}
 
 		items = append(items, item)
 	}
 
+	s.setHighlights(ctx, search, items)
+
 	return &models.BaseListResponse{
 		Total:    int(totalCount),
 		Page:     page,
@@ -433,7 +461,8 @@ func (s *Service) getCategoryMapByIDs(ctx context.Context, categoryIDs []int) (m
 	return categoryMap, nil
 }
 
-func (s *Service) getPrimaryImageMap(ctx context.Context, itemIDs []int) (map[int]string, error) {
+// getPrimaryImageMap maps items to the url of their primary photo in the given size
+func (s *Service) getPrimaryImageMap(ctx context.Context, itemIDs []int, size string) (map[int]string, error) {
 	if len(itemIDs) == 0 {
 		return make(map[int]string), nil
 	}
@@ -451,13 +480,13 @@ func (s *Service) getPrimaryImageMap(ctx context.Context, itemIDs []int) (map[in
 
 	imageMap := make(map[int]string)
 	for _, photo := range photos {
-		imageMap[photo.MenuItemID] = photo.Url
+		imageMap[photo.MenuItemID] = photo.SizedURL(size)
 	}
 
 	return imageMap, nil
 }
 
-func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int) ([]models.MenuItemModifier, error) {
+func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int, locale string) ([]models.MenuItemModifier, error) {
 	associationFilters := []repositories.Clause{
 		func(tx *gorm.DB) {
 			tx.Where("menu_item_id = ?", menuItemID)
@@ -476,7 +505,7 @@ func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int) ([]m
 
 	groupFilters := []repositories.Clause{
 		func(tx *gorm.DB) {
-			tx.Where("id IN ?", groupIDs)
+			tx.Where("id IN ? AND is_deleted = FALSE", groupIDs)
 		},
 	}
 
@@ -485,20 +514,46 @@ func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int) ([]m
 		return []models.MenuItemModifier{}, nil
 	}
 
+	overrideMap := make(map[int]modifierGroupOverride, len(associations))
+	for _, group := range groups {
+		for _, assoc := range associations {
+			if assoc.GroupID == group.ID {
+				overrideMap[group.ID] = modifierGroupOverride{assignment: assoc, group: group}
+			}
+		}
+	}
+	sort.SliceStable(groups, func(i, j int) bool {
+		return overrideMap[groups[i].ID].displayOrder() < overrideMap[groups[j].ID].displayOrder()
+	})
+
 	optionFilters := []repositories.Clause{
 		func(tx *gorm.DB) {
 			tx.Where("group_id IN ? AND status = ?", groupIDs, "active")
 		},
 	}
 
-	options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{}, optionFilters...)
+	options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, optionFilters...)
 	if err != nil {
 		options = []*models.ModifierOption{}
 	}
 
+	optionIDs := make([]int, 0, len(options))
+	for _, option := range options {
+		optionIDs = append(optionIDs, option.ID)
+	}
+	optionTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityModifierOption, optionIDs)
+	groupTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityModifierGroup, groupIDs)
+
 	optionsPreviewMap := make(map[int]string)
 	optionsByGroup := make(map[int][]*models.ModifierOption)
 	for _, option := range options {
+		override := overrideMap[option.GroupID]
+		if override.isHidden(option.ID) {
+			continue
+		}
+		option.PriceAdjustment = override.price(option)
+
+		localize(optionTranslations[option.ID], &option.Name, nil)
 		optionsByGroup[option.GroupID] = append(optionsByGroup[option.GroupID], option)
 	}
 
@@ -520,6 +575,8 @@ func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int) ([]m
 
 	modifiers := make([]models.MenuItemModifier, 0, len(groups))
 	for _, group := range groups {
+		localize(groupTranslations[group.ID], &group.Name, nil)
+
 		selectionTypeDisplay := "Single"
 		if group.SelectionType == "multiple" {
 			selectionTypeDisplay = "Multi"
@@ -532,6 +589,10 @@ func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int) ([]m
 			Required:        group.IsRequired,
 			SelectionType:   selectionTypeDisplay,
 			OptionsPreview:  optionsPreviewMap[group.ID],
+			MinSelections:   overrideMap[group.ID].minSelections(),
+			MaxSelections:   overrideMap[group.ID].maxSelections(),
+			DisplayOrder:    overrideMap[group.ID].displayOrder(),
+			Overrides:       overrideMap[group.ID].toOverrides(),
 		}
 		modifiers = append(modifiers, modifier)
 	}
@@ -540,6 +601,27 @@ func (s *Service) getMenuItemModifiers(ctx context.Context, menuItemID int) ([]m
 }
 
 func (s *Service) GetMenuItemByID(ctx context.Context, id int) (*models.MenuItemDetailResponse, error) {
+	return s.getMenuItemDetail(ctx, id, common.DEFAULT_LOCALE, "")
+}
+
+// GetGuestMenuItemByID is the item detail shown to guests, in their locale, with the full modifier tree
+func (s *Service) GetGuestMenuItemByID(ctx context.Context, id int, locale string, size string) (*models.MenuItemDetailResponse, error) {
+	response, err := s.getMenuItemDetail(ctx, id, locale, size)
+	if err != nil {
+		return nil, err
+	}
+
+	tree, err := s.getModifierTrees(ctx, []int{id}, locale)
+	if err != nil {
+		s.logger.Warn("Failed to load modifier tree", zap.Error(err))
+		return response, nil
+	}
+	response.ModifierTree = tree.roots[id]
+
+	return response, nil
+}
+
+func (s *Service) getMenuItemDetail(ctx context.Context, id int, locale string, size string) (*models.MenuItemDetailResponse, error) {
 	filters := []repositories.Clause{
 		func(tx *gorm.DB) {
 			tx.Where("id = ? AND is_deleted = FALSE", id)
@@ -557,6 +639,9 @@ func (s *Service) GetMenuItemByID(ctx context.Context, id int) (*models.MenuItem
 		categoryName = category.Name
 	}
 
+	localize(s.getTranslationMap(ctx, locale, models.TranslationEntityMenuItem, []int{menuItem.ID})[menuItem.ID], &menuItem.Name, &menuItem.Description)
+	localize(s.getTranslationMap(ctx, locale, models.TranslationEntityCategory, []int{menuItem.CategoryID})[menuItem.CategoryID], &categoryName, nil)
+
 	statusMap := map[string]string{
 		"available":   "Available",
 		"unavailable": "Unavailable",
@@ -575,7 +660,9 @@ func (s *Service) GetMenuItemByID(ctx context.Context, id int) (*models.MenuItem
 		},
 	}
 
-	photos, err := s.menuItemPhotoRepo.List(ctx, models.QueryParams{}, photoFilters...)
+	photos, err := s.menuItemPhotoRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"},
+	}, photoFilters...)
 	if err != nil {
 		photos = []*models.MenuItemPhoto{}
 	}
@@ -584,45 +671,58 @@ func (s *Service) GetMenuItemByID(ctx context.Context, id int) (*models.MenuItem
 	primaryImageURL := ""
 	for _, photo := range photos {
 		imageRequests = append(imageRequests, models.MenuItemPhotoRequest{
-			ID:        string(rune(photo.ID)),
+			ID:        strconv.Itoa(photo.ID),
 			URL:       photo.Url,
 			IsPrimary: photo.IsPrimary,
+			Variants:  photo.Variants,
 		})
 		if photo.IsPrimary {
-			primaryImageURL = photo.Url
+			primaryImageURL = photo.SizedURL(size)
 		}
 	}
 
 	// Fetch modifiers with group details
-	modifiers, err := s.getMenuItemModifiers(ctx, id)
+	modifiers, err := s.getMenuItemModifiers(ctx, id, locale)
 	if err != nil {
 		modifiers = []models.MenuItemModifier{}
 	}
 
 	response := &models.MenuItemDetailResponse{
-		ID:              menuItem.ID,
-		Name:            menuItem.Name,
-		Category:        categoryName,
-		Price:           menuItem.Price,
-		Status:          displayStatus,
-		LastUpdate:      lastUpdate,
-		ChefRecommended: menuItem.IsChefRecommended,
-		ImageURL:        primaryImageURL,
-		Description:     menuItem.Description,
-		PreparationTime: menuItem.PrepTimeMinutes,
-		Images:          imageRequests,
-		Modifiers:       modifiers,
+		ID:                menuItem.ID,
+		Name:              menuItem.Name,
+		Category:          categoryName,
+		Price:             menuItem.Price,
+		Status:            displayStatus,
+		LastUpdate:        lastUpdate,
+		ChefRecommended:   menuItem.IsChefRecommended,
+		DisplayOrder:      menuItem.DisplayOrder,
+		ImageURL:          primaryImageURL,
+		Description:       menuItem.Description,
+		PreparationTime:   menuItem.PrepTimeMinutes,
+		StockQuantity:     menuItem.StockQuantity,
+		LowStockThreshold: menuItem.LowStockThreshold,
+		Allergens:         toStringArray(menuItem.Allergens),
+		DietaryTags:       toStringArray(menuItem.DietaryTags),
+		SpicyLevel:        menuItem.SpicyLevel,
+		Nutrition:         menuItem.Nutrition,
+		Images:            imageRequests,
+		Modifiers:         modifiers,
 	}
 
 	return response, nil
 }
 
 func (s *Service) CreateMenuItem(ctx context.Context, request *models.CreateMenuItemRequest) (*models.MenuItem, error) {
-	category, err := s.menuCategoryRepo.GetByID(ctx, request.CategoryID)
+	category, err := s.getActiveMenuCategory(ctx, request.CategoryID)
 	if err != nil {
 		return nil, err
 	}
 
+	status := request.Status
+	if request.StockQuantity != nil && *request.StockQuantity == 0 && status == "available" {
+		status = "sold_out"
+	}
+
 	menuItem := &models.MenuItem{
 		RestaurantID:      category.RestaurantID,
 		CategoryID:        request.CategoryID,
@@ -630,8 +730,15 @@ func (s *Service) CreateMenuItem(ctx context.Context, request *models.CreateMenu
 		Description:       request.Description,
 		Price:             request.Price,
 		PrepTimeMinutes:   request.PrepTimeMinutes,
-		Status:            request.Status,
+		Status:            status,
 		IsChefRecommended: request.IsChefRecommended,
+		DisplayOrder:      s.nextMenuItemDisplayOrder(ctx, request.CategoryID),
+		StockQuantity:     request.StockQuantity,
+		LowStockThreshold: request.LowStockThreshold,
+		Allergens:         toStringArray(request.Allergens),
+		DietaryTags:       toStringArray(request.DietaryTags),
+		SpicyLevel:        request.SpicyLevel,
+		Nutrition:         request.Nutrition,
 		IsDeleted:         false,
 	}
 
@@ -642,15 +749,7 @@ func (s *Service) CreateMenuItem(ctx context.Context, request *models.CreateMenu
 
 	// Create menu item photos
 	if len(request.Images) > 0 {
-		photos := make([]*models.MenuItemPhoto, 0, len(request.Images))
-		for _, img := range request.Images {
-			photo := &models.MenuItemPhoto{
-				MenuItemID: created.ID,
-				Url:        img.URL,
-				IsPrimary:  img.IsPrimary,
-			}
-			photos = append(photos, photo)
-		}
+		photos := newMenuItemPhotos(created.ID, request.Images)
 		if err := s.menuItemPhotoRepo.CreatesMultiple(ctx, photos); err != nil {
 			// Log error but don't fail the whole operation
 		}
@@ -672,9 +771,28 @@ func (s *Service) CreateMenuItem(ctx context.Context, request *models.CreateMenu
 		}
 	}
 
+	s.invalidateMenuCache(ctx, category.RestaurantID)
+
 	return created, nil
 }
 
+// nextMenuItemDisplayOrder puts a new item at the end of its category
+func (s *Service) nextMenuItemDisplayOrder(ctx context.Context, categoryID int) int {
+	menuItems, err := s.menuItemRepo.List(ctx, models.QueryParams{
+		Limit: 1,
+		QuerySort: models.QuerySort{
+			Origin: "display_order.desc",
+		},
+	}, func(tx *gorm.DB) {
+		tx.Where("category_id = ? AND is_deleted = FALSE", categoryID)
+	})
+	if err != nil || len(menuItems) == 0 {
+		return 1
+	}
+
+	return menuItems[0].DisplayOrder + 1
+}
+
 func (s *Service) UpdateMenuItem(ctx context.Context, id int, request *models.UpdateMenuItemRequest) (*models.MenuItem, error) {
 	filters := []repositories.Clause{
 		func(tx *gorm.DB) {
@@ -690,7 +808,7 @@ func (s *Service) UpdateMenuItem(ctx context.Context, id int, request *models.Up
 	columns := make(map[string]interface{})
 
 	if request.CategoryID != nil {
-		_, err := s.menuCategoryRepo.GetByID(ctx, *request.CategoryID)
+		_, err := s.getActiveMenuCategory(ctx, *request.CategoryID)
 		if err != nil {
 			return nil, err
 		}
@@ -715,6 +833,7 @@ func (s *Service) UpdateMenuItem(ctx context.Context, id int, request *models.Up
 	if request.IsChefRecommended != nil {
 		columns["is_chef_recommended"] = *request.IsChefRecommended
 	}
+	dietaryColumns(request.DietaryInfo, columns)
 
 	if len(columns) == 0 && len(request.Images) == 0 && len(request.Modifiers) == 0 {
 		return existing, nil
@@ -738,44 +857,77 @@ func (s *Service) UpdateMenuItem(ctx context.Context, id int, request *models.Up
 				tx.Where("menu_item_id = ?", id)
 			},
 		}
+		oldPhotos, _ := s.menuItemPhotoRepo.List(ctx, models.QueryParams{}, deleteFilters...)
 		s.menuItemPhotoRepo.Delete(ctx, deleteFilters...)
 
 		// Create new photos
-		photos := make([]*models.MenuItemPhoto, 0, len(request.Images))
-		for _, img := range request.Images {
-			photo := &models.MenuItemPhoto{
-				MenuItemID: id,
-				Url:        img.URL,
-				IsPrimary:  img.IsPrimary,
+		photos := newMenuItemPhotos(id, request.Images)
+		s.menuItemPhotoRepo.CreatesMultiple(ctx, photos)
+
+		// drop the stored objects of photos that are not kept
+		kept := make(map[string]bool, len(photos))
+		for _, photo := range photos {
+			kept[photo.Url] = true
+		}
+		removed := make([]*models.MenuItemPhoto, 0, len(oldPhotos))
+		for _, photo := range oldPhotos {
+			if !kept[photo.Url] {
+				removed = append(removed, photo)
 			}
-			photos = append(photos, photo)
 		}
-		s.menuItemPhotoRepo.CreatesMultiple(ctx, photos)
+		s.removePhotoObjects(ctx, removed)
 	}
 
-	// Update modifiers if provided
+	// Update modifiers if provided. Groups that stay assigned keep their row and its per-item
+	// overrides; only removed groups are deleted and new ones added.
 	if len(request.Modifiers) > 0 {
-		// Delete existing modifiers
-		deleteFilters := []repositories.Clause{
-			func(tx *gorm.DB) {
-				tx.Where("menu_item_id = ?", id)
-			},
+		groupIDs := make([]int, 0, len(request.Modifiers))
+		for _, mod := range request.Modifiers {
+			modifierGroupID, err := strconv.Atoi(mod.ModifierGroupID)
+			if err != nil {
+				return nil, err
+			}
+			groupIDs = append(groupIDs, modifierGroupID)
 		}
-		s.menuItemModifierGroupRepo.Delete(ctx, deleteFilters...)
 
-		// Create new modifiers
-		modifiers := make([]*models.MenuItemModifierGroup, 0, len(request.Modifiers))
-		for _, mod := range request.Modifiers {
-			modifierGroupID, _ := strconv.Atoi(mod.ModifierGroupID)
-			modifier := &models.MenuItemModifierGroup{
+		existing, err := s.menuItemModifierGroupRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("menu_item_id = ?", id)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		err = s.menuItemModifierGroupRepo.Delete(ctx, func(tx *gorm.DB) {
+			tx.Where("menu_item_id = ? AND group_id NOT IN ?", id, groupIDs)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		assigned := make(map[int]bool, len(existing))
+		for _, association := range existing {
+			assigned[association.GroupID] = true
+		}
+		modifiers := make([]*models.MenuItemModifierGroup, 0, len(groupIDs))
+		for _, groupID := range groupIDs {
+			if assigned[groupID] {
+				continue
+			}
+			assigned[groupID] = true
+			modifiers = append(modifiers, &models.MenuItemModifierGroup{
 				MenuItemID: id,
-				GroupID:    modifierGroupID,
+				GroupID:    groupID,
+			})
+		}
+		if len(modifiers) > 0 {
+			if err := s.menuItemModifierGroupRepo.CreatesMultiple(ctx, modifiers); err != nil {
+				return nil, err
 			}
-			modifiers = append(modifiers, modifier)
 		}
-		s.menuItemModifierGroupRepo.CreatesMultiple(ctx, modifiers)
 	}
 
+	s.invalidateMenuItemCache(ctx, id)
+
 	return updated, nil
 }
 
@@ -788,13 +940,13 @@ func (s *Service) GetMenuItemsByRestaurant(ctx context.Context, restaurantID int
 		},
 	}
 
-	if request.Search != nil && *request.Search != "" {
-		search := "%" + strings.ToLower(*request.Search) + "%"
-		filters = append(filters, func(tx *gorm.DB) {
-			tx.Where("LOWER(menu_items.name) LIKE ?", search)
-		})
+	search := newMenuSearch(request.Search)
+	if search != nil {
+		filters = append(filters, search.filter(request.Locale))
 	}
 
+	filters = append(filters, dietaryFilters(request.DietaryFilter)...)
+
 	if request.Category != nil && *request.Category != "" && *request.Category != "all" {
 		categoryName := *request.Category
 		filters = append(filters, func(tx *gorm.DB) {
@@ -823,20 +975,24 @@ func (s *Service) GetMenuItemsByRestaurant(ctx context.Context, restaurantID int
 	}
 
 	sortMap := map[string]string{
-		"default":     "id.asc",
 		"name":        "name.asc",
 		"price_asc":   "price.asc",
 		"price_desc":  "price.desc",
 		"last_update": "updated_at.desc",
 	}
 
+	listFilters := filters
 	if sortOrder, ok := sortMap[request.Sort]; ok {
 		queryParams.QuerySort.Origin = sortOrder
+	} else if search != nil {
+		// best matches first unless another order was asked for
+		listFilters = append(append([]repositories.Clause{}, filters...), search.order())
 	} else {
-		queryParams.QuerySort.Origin = sortMap["default"]
+		// as arranged by the restaurant: category order, then item order
+		listFilters = append(append([]repositories.Clause{}, filters...), menuDisplayOrder)
 	}
 
-	menuItems, err := s.menuItemRepo.List(ctx, queryParams, filters...)
+	menuItems, err := s.menuItemRepo.List(ctx, queryParams, listFilters...)
 	if err != nil {
 		return nil, err
 	}
@@ -854,11 +1010,24 @@ func (s *Service) GetMenuItemsByRestaurant(ctx context.Context, restaurantID int
 		categoryMap = make(map[int]string)
 	}
 
-	primaryImageMap, err := s.getPrimaryImageMap(ctx, itemIDs)
+	primaryImageMap, err := s.getPrimaryImageMap(ctx, itemIDs, request.Size)
 	if err != nil {
 		primaryImageMap = make(map[int]string)
 	}
 
+	modifierTrees, err := s.getModifierTrees(ctx, itemIDs, request.Locale)
+	if err != nil {
+		s.logger.Warn("Failed to load modifier trees", zap.Error(err))
+		modifierTrees = &modifierTree{}
+	}
+
+	itemTranslations := s.getTranslationMap(ctx, request.Locale, models.TranslationEntityMenuItem, itemIDs)
+	categoryTranslations := s.getTranslationMap(ctx, request.Locale, models.TranslationEntityCategory, categoryIDs)
+	for categoryID, categoryName := range categoryMap {
+		localize(categoryTranslations[categoryID], &categoryName, nil)
+		categoryMap[categoryID] = categoryName
+	}
+
 	statusMap := map[string]string{
 		"available":   "Available",
 		"unavailable": "Unavailable",
@@ -868,6 +1037,8 @@ func (s *Service) GetMenuItemsByRestaurant(ctx context.Context, restaurantID int
 	responses := make([]*models.MenuItemResponse, 0, len(menuItems))
 
 	for _, menuItem := range menuItems {
+		localize(itemTranslations[menuItem.ID], &menuItem.Name, &menuItem.Description)
+
 		displayStatus := statusMap[menuItem.Status]
 		if displayStatus == "" {
 			displayStatus = menuItem.Status
@@ -886,12 +1057,21 @@ func (s *Service) GetMenuItemsByRestaurant(ctx context.Context, restaurantID int
 			Status:          displayStatus,
 			LastUpdate:      lastUpdate,
 			ChefRecommended: menuItem.IsChefRecommended,
+			DisplayOrder:    menuItem.DisplayOrder,
 			ImageURL:        primaryImageMap[menuItem.ID],
 			Description:     menuItem.Description,
 			PreparationTime: menuItem.PrepTimeMinutes,
+			StockQuantity:   menuItem.StockQuantity,
+			Allergens:       toStringArray(menuItem.Allergens),
+			DietaryTags:     toStringArray(menuItem.DietaryTags),
+			SpicyLevel:      menuItem.SpicyLevel,
+			Nutrition:       menuItem.Nutrition,
+			ModifierTree:    modifierTrees.roots[menuItem.ID],
 		})
 	}
 
+	s.setHighlights(ctx, search, responses)
+
 	return &models.BaseListResponse{
 		Total:    int(totalCount),
 		Page:     page,
@@ -900,24 +1080,30 @@ func (s *Service) GetMenuItemsByRestaurant(ctx context.Context, restaurantID int
 	}, nil
 }
 
-func (s *Service) DeleteMenuItem(ctx context.Context, id int) error {
-	filters := []repositories.Clause{
-		func(tx *gorm.DB) {
-			tx.Where("id = ? AND is_deleted = FALSE", id)
-		},
-	}
+func menuDisplayOrder(tx *gorm.DB) {
+	tx.Order(clause.Expr{SQL: "(SELECT c.display_order FROM menu_categories c WHERE c.id = menu_items.category_id), menu_items.display_order, menu_items.id"})
+}
 
-	_, err := s.menuItemRepo.GetDetailByConditions(ctx, filters...)
+func (s *Service) DeleteMenuItem(ctx context.Context, id int) error {
+	menuItem, err := s.getActiveMenuItem(ctx, id)
 	if err != nil {
 		return err
 	}
 
+	now := time.Now()
 	columns := map[string]interface{}{
 		"is_deleted": true,
+		"deleted_at": now,
+		"updated_at": now,
 	}
 
 	_, err = s.menuItemRepo.UpdateColumns(ctx, id, columns)
-	return err
+	if err != nil {
+		return err
+	}
+
+	s.invalidateMenuCache(ctx, menuItem.RestaurantID)
+	return nil
 }
 
 func (s *Service) AssignMenuItemModifierGroup(
@@ -925,6 +1111,10 @@ func (s *Service) AssignMenuItemModifierGroup(
 	request *models.AssignModifierToMenuItemRequest,
 ) (*models.MenuItemModifierGroup, error) {
 
+	if _, err := s.getActiveMenuItem(ctx, request.MenuItemID); err != nil {
+		return nil, err
+	}
+
 	existing, err := s.menuItemModifierGroupRepo.
 		FindByMenuItemIDAndGroupID(ctx, request.MenuItemID, request.GroupID)
 	if err != nil {
@@ -935,9 +1125,28 @@ func (s *Service) AssignMenuItemModifierGroup(
 		return existing, nil
 	}
 
+	if err := s.validateModifierOverrides(ctx, request.GroupID, &request.ModifierGroupOverrides); err != nil {
+		return nil, err
+	}
+
+	hiddenOptionIDs := make(pq.Int64Array, 0, len(request.HiddenOptionIDs))
+	for _, optionID := range request.HiddenOptionIDs {
+		hiddenOptionIDs = append(hiddenOptionIDs, int64(optionID))
+	}
+
+	priceOverrides := request.PriceOverrides
+	if priceOverrides == nil {
+		priceOverrides = map[int]float64{}
+	}
+
 	menuItemModifierGroup := &models.MenuItemModifierGroup{
-		MenuItemID: request.MenuItemID,
-		GroupID:    request.GroupID,
+		MenuItemID:      request.MenuItemID,
+		GroupID:         request.GroupID,
+		DisplayOrder:    request.DisplayOrder,
+		MinSelections:   request.MinSelections,
+		MaxSelections:   request.MaxSelections,
+		HiddenOptionIDs: hiddenOptionIDs,
+		PriceOverrides:  priceOverrides,
 	}
 
 	created, err := s.menuItemModifierGroupRepo.Create(ctx, menuItemModifierGroup)
@@ -945,6 +1154,8 @@ func (s *Service) AssignMenuItemModifierGroup(
 		return nil, err
 	}
 
+	s.invalidateMenuItemCache(ctx, request.MenuItemID)
+
 	return created, nil
 }
 
@@ -954,6 +1165,12 @@ func (s *Service) DeleteMenuItemModifierGroup(
 	groupID int,
 ) error {
 
-	return s.menuItemModifierGroupRepo.
+	err := s.menuItemModifierGroupRepo.
 		DeleteByMenuItemIDAndGroupID(ctx, menuItemID, groupID)
+	if err != nil {
+		return err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+	return nil
 }
@@ -0,0 +1,281 @@
+package services
+
+import (
+	"app-noti/config"
+	"app-noti/internal/models"
+	prometheus2 "app-noti/pkg/prometheus"
+	redis2 "app-noti/pkg/redis"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync"
+	"time"
+
+	prm "github.com/prometheus/client_golang/prometheus"
+	"go.uber.org/zap"
+)
+
+const (
+	defaultMenuCacheTTL = 300
+
+	menuCacheStoreRedis  = "redis"
+	menuCacheStoreMemory = "memory"
+)
+
+var menuCacheMetric = prometheus2.NewCacheCounterMetric("menu_cache_requests_total")
+
+func init() {
+	prm.MustRegister(menuCacheMetric.Get(context.Background()))
+}
+
+// menuCacheStore is the part of the redis client the menu cache needs, so an in-memory
+// store can stand in when redis is not configured
+type menuCacheStore interface {
+	GetByte(ctx context.Context, key string) ([]byte, error)
+	SetByte(ctx context.Context, key string, value []byte, expiry int64) error
+}
+
+// menuCache keeps the assembled guest menus per restaurant. Every key carries the restaurant's
+// generation: invalidating bumps it, so all cached variants of that restaurant (locales, filters,
+// pages) are dropped at once and left to expire, while other restaurants keep theirs.
+type menuCache struct {
+	store     menuCacheStore
+	storeName string
+	ttl       int64
+}
+
+func newMenuCache(client redis2.ClientI) *menuCache {
+	ttl := int64(config.Config.Menu.CacheTTL)
+	if ttl <= 0 {
+		ttl = defaultMenuCacheTTL
+	}
+
+	if client != nil {
+		return &menuCache{store: client, storeName: menuCacheStoreRedis, ttl: ttl}
+	}
+
+	return &menuCache{store: newMemoryCacheStore(), storeName: menuCacheStoreMemory, ttl: ttl}
+}
+
+func menuGenerationKey(restaurantID int) string {
+	return fmt.Sprintf("menu:%d:generation", restaurantID)
+}
+
+func (c *menuCache) generation(ctx context.Context, restaurantID int) (string, error) {
+	value, err := c.store.GetByte(ctx, menuGenerationKey(restaurantID))
+	if errors.Is(err, redis2.ErrRecordNotFound) {
+		return "0", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return string(value), nil
+}
+
+func (c *menuCache) invalidate(ctx context.Context, restaurantID int) error {
+	generation := strconv.FormatInt(time.Now().UnixNano(), 10)
+	// no expiry: the generation must outlive every key built on the previous one
+	return c.store.SetByte(ctx, menuGenerationKey(restaurantID), []byte(generation), 0)
+}
+
+// cachedMenu returns the cached value of a menu variant, building and storing it on a miss.
+// A failing store never fails the request, the menu is built from the database instead.
+func cachedMenu[T any](ctx context.Context, s *Service, restaurantID int, cacheName string, variant string, build func() (*T, error)) (*T, error) {
+	cache := s.menuCache
+
+	generation, err := cache.generation(ctx, restaurantID)
+	if err != nil {
+		s.logger.Warn("Failed to read menu cache generation", zap.Error(err))
+		menuCacheMetric.Add(ctx, cacheName, cache.storeName, prometheus2.CacheResultError)
+		return build()
+	}
+
+	key := fmt.Sprintf("menu:%d:%s:%s:%s", restaurantID, generation, cacheName, variant)
+	if body, err := cache.store.GetByte(ctx, key); err == nil {
+		var value T
+		if err := json.Unmarshal(body, &value); err == nil {
+			menuCacheMetric.Add(ctx, cacheName, cache.storeName, prometheus2.CacheResultHit)
+			return &value, nil
+		}
+	} else if !errors.Is(err, redis2.ErrRecordNotFound) {
+		s.logger.Warn("Failed to read menu cache", zap.Error(err))
+	}
+	menuCacheMetric.Add(ctx, cacheName, cache.storeName, prometheus2.CacheResultMiss)
+
+	value, err := build()
+	if err != nil {
+		return nil, err
+	}
+
+	if body, err := json.Marshal(value); err == nil {
+		if err := cache.store.SetByte(ctx, key, body, cache.ttl); err != nil {
+			s.logger.Warn("Failed to write menu cache", zap.Error(err))
+		}
+	}
+
+	return value, nil
+}
+
+// GetGuestMenu is the paginated guest menu (items and combos) behind the QR scan
+func (s *Service) GetGuestMenu(ctx context.Context, restaurantID int, request *models.ListMenuRequest) (*models.GuestMenuResponse, error) {
+	params, err := json.Marshal(request)
+	if err != nil {
+		return nil, err
+	}
+	sum := sha256.Sum256(params)
+	variant := request.Locale + ":" + hex.EncodeToString(sum[:8])
+
+	return cachedMenu(ctx, s, restaurantID, "guest_menu", variant, func() (*models.GuestMenuResponse, error) {
+		items, err := s.GetMenuItemsByRestaurant(ctx, restaurantID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		combos, err := s.GetGuestCombos(ctx, restaurantID, request)
+		if err != nil {
+			return nil, err
+		}
+
+		return &models.GuestMenuResponse{
+			BaseListResponse: *items,
+			Combos:           combos,
+		}, nil
+	})
+}
+
+// invalidateMenuCache drops the cached menus of the restaurants. Errors are only logged: the
+// cached copies still expire on their own.
+func (s *Service) invalidateMenuCache(ctx context.Context, restaurantIDs ...int) {
+	seen := make(map[int]bool, len(restaurantIDs))
+	for _, restaurantID := range restaurantIDs {
+		if restaurantID == 0 || seen[restaurantID] {
+			continue
+		}
+		seen[restaurantID] = true
+
+		if err := s.menuCache.invalidate(ctx, restaurantID); err != nil {
+			s.logger.Warn("Failed to invalidate menu cache", zap.Int("restaurant_id", restaurantID), zap.Error(err))
+		}
+	}
+}
+
+func (s *Service) invalidateMenuItemCache(ctx context.Context, menuItemID int) {
+	menuItem, err := s.menuItemRepo.GetByID(ctx, menuItemID)
+	if err != nil {
+		s.logger.Warn("Failed to resolve menu item for cache invalidation", zap.Error(err))
+		return
+	}
+	s.invalidateMenuCache(ctx, menuItem.RestaurantID)
+}
+
+func (s *Service) invalidateModifierGroupCache(ctx context.Context, groupID int) {
+	group, err := s.modifierGroupRepo.GetByID(ctx, groupID)
+	if err != nil {
+		s.logger.Warn("Failed to resolve modifier group for cache invalidation", zap.Error(err))
+		return
+	}
+	s.invalidateMenuCache(ctx, group.RestaurantID)
+}
+
+func (s *Service) invalidateModifierOptionCache(ctx context.Context, optionID int) {
+	option, err := s.modifierOptionRepo.GetByID(ctx, optionID)
+	if err != nil {
+		s.logger.Warn("Failed to resolve modifier option for cache invalidation", zap.Error(err))
+		return
+	}
+	s.invalidateModifierGroupCache(ctx, option.GroupID)
+}
+
+func (s *Service) invalidateTranslationCache(ctx context.Context, entityType string, entityID int) {
+	switch entityType {
+	case models.TranslationEntityCategory:
+		category, err := s.menuCategoryRepo.GetByID(ctx, entityID)
+		if err != nil {
+			s.logger.Warn("Failed to resolve category for cache invalidation", zap.Error(err))
+			return
+		}
+		s.invalidateMenuCache(ctx, category.RestaurantID)
+	case models.TranslationEntityMenuItem:
+		s.invalidateMenuItemCache(ctx, entityID)
+	case models.TranslationEntityModifierGroup:
+		s.invalidateModifierGroupCache(ctx, entityID)
+	case models.TranslationEntityModifierOption:
+		s.invalidateModifierOptionCache(ctx, entityID)
+	case models.TranslationEntityCombo:
+		combo, err := s.comboRepo.GetByID(ctx, entityID)
+		if err != nil {
+			s.logger.Warn("Failed to resolve combo for cache invalidation", zap.Error(err))
+			return
+		}
+		s.invalidateMenuCache(ctx, combo.RestaurantID)
+	}
+}
+
+// invalidateOrderMenuCache is used when an order moves stock, which can sell items out
+func (s *Service) invalidateOrderMenuCache(ctx context.Context, order *models.Order) {
+	if order.RestaurantID != nil {
+		s.invalidateMenuCache(ctx, *order.RestaurantID)
+		return
+	}
+
+	table, err := s.tableRepo.GetByID(ctx, order.TableID)
+	if err != nil {
+		s.logger.Warn("Failed to resolve table for cache invalidation", zap.Error(err))
+		return
+	}
+	s.invalidateMenuCache(ctx, table.RestaurantId)
+}
+
+type memoryCacheEntry struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// memoryCacheStore is the single instance fallback: entries live in the process and expire like redis keys
+type memoryCacheStore struct {
+	mu        sync.Mutex
+	entries   map[string]memoryCacheEntry
+	lastSweep time.Time
+}
+
+func newMemoryCacheStore() *memoryCacheStore {
+	return &memoryCacheStore{entries: make(map[string]memoryCacheEntry), lastSweep: time.Now()}
+}
+
+func (m *memoryCacheStore) GetByte(ctx context.Context, key string) ([]byte, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	entry, ok := m.entries[key]
+	if !ok || (!entry.expiresAt.IsZero() && time.Now().After(entry.expiresAt)) {
+		return nil, redis2.ErrRecordNotFound
+	}
+	return entry.value, nil
+}
+
+func (m *memoryCacheStore) SetByte(ctx context.Context, key string, value []byte, expiry int64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	entry := memoryCacheEntry{value: value}
+	if expiry > 0 {
+		entry.expiresAt = now.Add(time.Duration(expiry) * time.Second)
+	}
+	m.entries[key] = entry
+
+	if now.Sub(m.lastSweep) > time.Minute {
+		for entryKey, cached := range m.entries {
+			if !cached.expiresAt.IsZero() && now.After(cached.expiresAt) {
+				delete(m.entries, entryKey)
+			}
+		}
+		m.lastSweep = now
+	}
+
+	return nil
+}
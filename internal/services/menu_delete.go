@@ -0,0 +1,186 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// DeleteMenuCategory soft deletes a category. A category that still has items is refused, listing
+// them, unless move_to_category_id names another category of the restaurant to take them. Items in
+// the trash move along so they can still be restored.
+func (s *Service) DeleteMenuCategory(ctx context.Context, id int, request *models.DeleteMenuCategoryRequest) error {
+	category, err := s.getActiveMenuCategory(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	var target *models.MenuCategory
+	if request.MoveToCategoryID != nil {
+		if *request.MoveToCategoryID == id {
+			return common.ErrInvalidTargetCategory
+		}
+
+		target, err = s.getActiveMenuCategory(ctx, *request.MoveToCategoryID)
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return common.ErrInvalidTargetCategory
+		}
+		if err != nil {
+			return err
+		}
+
+		if target.RestaurantID != category.RestaurantID {
+			return common.ErrInvalidTargetCategory
+		}
+	}
+
+	err = s.menuItemRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var menuItems []*models.MenuItem
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("category_id = ? AND is_deleted = FALSE", id).
+			Order("display_order, id").
+			Find(&menuItems).Error
+		if err != nil {
+			return err
+		}
+
+		if len(menuItems) > 0 && target == nil {
+			dependents := make([]*models.MenuDependent, 0, len(menuItems))
+			for _, menuItem := range menuItems {
+				dependents = append(dependents, &models.MenuDependent{
+					Type: models.DependentMenuItem,
+					ID:   menuItem.ID,
+					Name: menuItem.Name,
+				})
+			}
+			return common.AllErrors.New(common.ErrCategoryHasItems, "vi").SetDependents(dependents)
+		}
+
+		now := time.Now()
+		if target != nil {
+			// moved items keep their relative order after the items already in the target
+			err := tx.Exec(`
+				UPDATE menu_items m
+				SET category_id = @target,
+					display_order = (SELECT COALESCE(MAX(display_order), 0) FROM menu_items WHERE category_id = @target AND is_deleted = FALSE) + o.position,
+					updated_at = @now
+				FROM (
+					SELECT id, ROW_NUMBER() OVER (ORDER BY display_order, id) AS position
+					FROM menu_items
+					WHERE category_id = @source AND is_deleted = FALSE
+				) o
+				WHERE m.id = o.id`,
+				map[string]interface{}{"target": target.ID, "source": id, "now": now},
+			).Error
+			if err != nil {
+				return err
+			}
+
+			err = tx.Model(&models.MenuItem{}).
+				Where("category_id = ? AND is_deleted = TRUE", id).
+				Update("category_id", target.ID).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(&models.MenuCategory{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": now,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateMenuCache(ctx, category.RestaurantID)
+	return nil
+}
+
+// DeleteModifierGroup soft deletes a group. A group still attached to items, or offered as the
+// child group of another option, is refused with the list of those unless detach is set, in which
+// case the links are removed with it.
+func (s *Service) DeleteModifierGroup(ctx context.Context, id int, request *models.DeleteModifierGroupRequest) error {
+	group, err := s.getActiveModifierGroup(ctx, id)
+	if err != nil {
+		return err
+	}
+
+	err = s.menuItemRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ?", id).
+			First(&models.ModifierGroup{}).Error
+		if err != nil {
+			return err
+		}
+
+		dependents, err := getModifierGroupDependents(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if len(dependents) > 0 && !request.Detach {
+			return common.AllErrors.New(common.ErrModifierGroupInUse, "vi").SetDependents(dependents)
+		}
+
+		// links from items in the trash go too, so a restored item does not bring the group back
+		if err := tx.Where("group_id = ?", id).Delete(&models.MenuItemModifierGroup{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("group_id = ?", id).Delete(&models.ModifierOptionChildGroup{}).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		return tx.Model(&models.ModifierGroup{}).Where("id = ?", id).Updates(map[string]interface{}{
+			"is_deleted": true,
+			"deleted_at": now,
+			"updated_at": now,
+		}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateMenuCache(ctx, group.RestaurantID)
+	return nil
+}
+
+func getModifierGroupDependents(tx *gorm.DB, groupID int) ([]*models.MenuDependent, error) {
+	var dependents []*models.MenuDependent
+	err := tx.Raw(`
+		SELECT ? AS type, mi.id, mi.name
+		FROM menu_item_modifier_groups l
+		JOIN menu_items mi ON mi.id = l.menu_item_id
+		WHERE l.group_id = ? AND mi.is_deleted = FALSE
+		UNION ALL
+		SELECT ? AS type, o.id, o.name
+		FROM modifier_option_child_groups c
+		JOIN modifier_options o ON o.id = c.option_id
+		JOIN modifier_groups g ON g.id = o.group_id
+		WHERE c.group_id = ? AND g.is_deleted = FALSE
+		ORDER BY type, id`,
+		models.DependentMenuItem, groupID, models.DependentModifierOption, groupID,
+	).Scan(&dependents).Error
+
+	return dependents, err
+}
+
+func (s *Service) getActiveMenuCategory(ctx context.Context, id int) (*models.MenuCategory, error) {
+	return s.menuCategoryRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+}
+
+func (s *Service) getActiveModifierGroup(ctx context.Context, id int) (*models.ModifierGroup, error) {
+	return s.modifierGroupRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+}
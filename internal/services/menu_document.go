@@ -0,0 +1,192 @@
+package services
+
+import (
+	"app-noti/internal/models"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+)
+
+// GetMenuDocument is the full guest menu in one payload, served from the menu cache. size picks
+// the photo variant used for each item's image_url.
+func (s *Service) GetMenuDocument(ctx context.Context, restaurantID int, locale string, size string) (*models.MenuDocumentResponse, error) {
+	return cachedMenu(ctx, s, restaurantID, "menu_document", locale+":"+size, func() (*models.MenuDocumentResponse, error) {
+		return s.buildMenuDocument(ctx, restaurantID, locale, size)
+	})
+}
+
+// buildMenuDocument builds the full guest menu in one go so the app does not have to fetch every
+// item for its modifiers. Items of inactive categories and deleted items are left out.
+func (s *Service) buildMenuDocument(ctx context.Context, restaurantID int, locale string, size string) (*models.MenuDocumentResponse, error) {
+	document := &models.MenuDocumentResponse{
+		RestaurantID: restaurantID,
+		Locale:       locale,
+		Categories:   []*models.MenuDocumentCategory{},
+		Combos:       []*models.ComboResponse{},
+	}
+
+	lastModified, err := s.getMenuLastModified(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	if !lastModified.IsZero() {
+		document.UpdatedAt = &lastModified
+	}
+
+	categories, err := s.menuCategoryRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ? AND status = ? AND is_deleted = FALSE", restaurantID, "active")
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	combos, err := s.GetGuestCombos(ctx, restaurantID, &models.ListMenuRequest{Locale: locale})
+	if err != nil {
+		return nil, err
+	}
+	document.Combos = combos
+
+	if len(categories) == 0 {
+		return document, nil
+	}
+
+	categoryIDs := make([]int, 0, len(categories))
+	for _, category := range categories {
+		categoryIDs = append(categoryIDs, category.ID)
+	}
+
+	menuItems, err := s.menuItemRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ? AND category_id IN ? AND is_deleted = FALSE", restaurantID, categoryIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	itemIDs := make([]int, 0, len(menuItems))
+	for _, menuItem := range menuItems {
+		itemIDs = append(itemIDs, menuItem.ID)
+	}
+
+	photoMap, err := s.getMenuItemPhotoMap(ctx, itemIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	modifierTrees, err := s.getModifierTrees(ctx, itemIDs, locale)
+	if err != nil {
+		return nil, err
+	}
+
+	itemTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityMenuItem, itemIDs)
+	categoryTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityCategory, categoryIDs)
+
+	itemsByCategory := make(map[int][]*models.MenuDocumentItem)
+	for _, menuItem := range menuItems {
+		localize(itemTranslations[menuItem.ID], &menuItem.Name, &menuItem.Description)
+
+		item := &models.MenuDocumentItem{
+			ID:              menuItem.ID,
+			Name:            menuItem.Name,
+			Description:     menuItem.Description,
+			Price:           menuItem.Price,
+			Status:          menuItem.Status,
+			IsAvailable:     isMenuItemOrderable(menuItem),
+			ChefRecommended: menuItem.IsChefRecommended,
+			PreparationTime: menuItem.PrepTimeMinutes,
+			Allergens:       toStringArray(menuItem.Allergens),
+			DietaryTags:     toStringArray(menuItem.DietaryTags),
+			SpicyLevel:      menuItem.SpicyLevel,
+			Nutrition:       menuItem.Nutrition,
+			Photos:          []*models.MenuDocumentPhoto{},
+			ModifierGroups:  []*models.ModifierGroupNode{},
+		}
+
+		for _, photo := range photoMap[menuItem.ID] {
+			item.Photos = append(item.Photos, &models.MenuDocumentPhoto{
+				ID:        photo.ID,
+				URL:       photo.Url,
+				IsPrimary: photo.IsPrimary,
+				Variants:  photo.Variants,
+			})
+			if photo.IsPrimary {
+				item.ImageURL = photo.SizedURL(size)
+			}
+		}
+
+		if roots := modifierTrees.roots[menuItem.ID]; roots != nil {
+			item.ModifierGroups = roots
+		}
+
+		itemsByCategory[menuItem.CategoryID] = append(itemsByCategory[menuItem.CategoryID], item)
+	}
+
+	for _, category := range categories {
+		localize(categoryTranslations[category.ID], &category.Name, &category.Description)
+
+		items := itemsByCategory[category.ID]
+		if items == nil {
+			items = []*models.MenuDocumentItem{}
+		}
+
+		document.Categories = append(document.Categories, &models.MenuDocumentCategory{
+			ID:           category.ID,
+			Name:         category.Name,
+			Description:  category.Description,
+			DisplayOrder: category.DisplayOrder,
+			Items:        items,
+		})
+	}
+
+	return document, nil
+}
+
+func (s *Service) getMenuItemPhotoMap(ctx context.Context, itemIDs []int) (map[int][]*models.MenuItemPhoto, error) {
+	photoMap := make(map[int][]*models.MenuItemPhoto)
+	if len(itemIDs) == 0 {
+		return photoMap, nil
+	}
+
+	photos, err := s.menuItemPhotoRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+		tx.Where("menu_item_id IN ?", itemIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	for _, photo := range photos {
+		photoMap[photo.MenuItemID] = append(photoMap[photo.MenuItemID], photo)
+	}
+
+	return photoMap, nil
+}
+
+// getMenuLastModified is the latest change to anything shown in the menu document. Option edits,
+// deletions and status changes from stock leave no timestamp behind, so it is only informational:
+// conditional requests are answered from the ETag.
+func (s *Service) getMenuLastModified(ctx context.Context, restaurantID int) (time.Time, error) {
+	var lastModified *time.Time
+
+	err := s.menuItemRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT GREATEST(
+			(SELECT MAX(updated_at) FROM menu_categories WHERE restaurant_id = @restaurant_id),
+			(SELECT MAX(updated_at) FROM menu_items WHERE restaurant_id = @restaurant_id),
+			(SELECT MAX(created_at) FROM menu_item_photos),
+			(SELECT MAX(updated_at) FROM modifier_groups),
+			(SELECT MAX(created_at) FROM modifier_options),
+			(SELECT MAX(created_at) FROM menu_item_modifier_groups),
+			(SELECT MAX(created_at) FROM modifier_option_child_groups),
+			(SELECT MAX(updated_at) FROM combos WHERE restaurant_id = @restaurant_id),
+			(SELECT MAX(updated_at) FROM menu_translations)
+		)
+	`, map[string]interface{}{"restaurant_id": restaurantID}).Scan(&lastModified).Error
+	if err != nil {
+		return time.Time{}, err
+	}
+
+	if lastModified == nil {
+		return time.Time{}, nil
+	}
+
+	return lastModified.UTC(), nil
+}
@@ -13,7 +13,11 @@ import (
 
 func (s *Service) GetModifierGroup(ctx context.Context, request *models.ListModifierGroupRequest) (*models.BaseListResponse, error) {
 	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
-	filters := []repositories.Clause{}
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("is_deleted = FALSE")
+		},
+	}
 
 	if request.Status != nil && *request.Status != "" && *request.Status != "all" {
 		status := *request.Status
@@ -110,7 +114,7 @@ func (s *Service) CreateModifierGroup(ctx context.Context, request *models.Creat
 }
 
 func (s *Service) UpdateModifierGroup(ctx context.Context, id int, request *models.UpdateModifierGroupRequest) (*models.ModifierGroup, error) {
-	existing, err := s.modifierGroupRepo.GetByID(ctx, id)
+	existing, err := s.getActiveModifierGroup(ctx, id)
 	if err != nil {
 		return nil, err
 	}
@@ -144,28 +148,26 @@ func (s *Service) UpdateModifierGroup(ctx context.Context, id int, request *mode
 
 	updated, err := s.modifierGroupRepo.UpdateColumns(ctx, id, columns)
 
+	s.invalidateMenuCache(ctx, existing.RestaurantID)
+
 	return updated, nil
 }
 
-func (s *Service) DeleteModifierGroup(ctx context.Context, id int) error {
-	rows, err := s.modifierGroupRepo.DeleteByID(ctx, id)
-	if err != nil {
-		return err
-	}
-
-	if rows == 0 {
-		return errors.New("modifier group not found")
+func (s *Service) CreateModifierOptions(ctx context.Context, groupId int, request *models.CreateModifierOptionRequest) (*models.ModifierOption, error) {
+	if _, err := s.getActiveModifierGroup(ctx, groupId); err != nil {
+		return nil, err
 	}
 
-	return nil
-}
-
-func (s *Service) CreateModifierOptions(ctx context.Context, groupId int, request *models.CreateModifierOptionRequest) (*models.ModifierOption, error) {
 	modifierOption := &models.ModifierOption{
 		GroupID:         groupId,
 		Name:            request.Name,
 		PriceAdjustment: request.PriceAdjustment,
 		Status:          request.Status,
+		DisplayOrder:    s.nextModifierOptionDisplayOrder(ctx, groupId),
+		Allergens:       toStringArray(request.Allergens),
+		DietaryTags:     toStringArray(request.DietaryTags),
+		SpicyLevel:      request.SpicyLevel,
+		Nutrition:       request.Nutrition,
 	}
 
 	created, err := s.modifierOptionRepo.Create(ctx, modifierOption)
@@ -173,9 +175,28 @@ func (s *Service) CreateModifierOptions(ctx context.Context, groupId int, reques
 		return nil, err
 	}
 
+	s.invalidateModifierGroupCache(ctx, groupId)
+
 	return created, nil
 }
 
+// nextModifierOptionDisplayOrder puts a new option at the end of its group
+func (s *Service) nextModifierOptionDisplayOrder(ctx context.Context, groupID int) int {
+	options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{
+		Limit: 1,
+		QuerySort: models.QuerySort{
+			Origin: "display_order.desc",
+		},
+	}, func(tx *gorm.DB) {
+		tx.Where("group_id = ?", groupID)
+	})
+	if err != nil || len(options) == 0 {
+		return 1
+	}
+
+	return options[0].DisplayOrder + 1
+}
+
 func (s *Service) UpdateModifierOptions(ctx context.Context, id int, request *models.UpdateModifierOptionRequest) (*models.ModifierOption, error) {
 	existing, err := s.modifierOptionRepo.GetByID(ctx, id)
 	if err != nil {
@@ -192,6 +213,7 @@ func (s *Service) UpdateModifierOptions(ctx context.Context, id int, request *mo
 	if request.Status != nil {
 		columns["status"] = *request.Status
 	}
+	dietaryColumns(request.DietaryInfo, columns)
 
 	if len(columns) == 0 {
 		return existing, nil
@@ -199,10 +221,17 @@ func (s *Service) UpdateModifierOptions(ctx context.Context, id int, request *mo
 
 	updated, err := s.modifierOptionRepo.UpdateColumns(ctx, id, columns)
 
+	s.invalidateModifierGroupCache(ctx, existing.GroupID)
+
 	return updated, nil
 }
 
 func (s *Service) DeleteModifierOptions(ctx context.Context, id int) error {
+	option, err := s.modifierOptionRepo.GetByID(ctx, id)
+	if err != nil {
+		return err
+	}
+
 	rows, err := s.modifierOptionRepo.DeleteByID(ctx, id)
 	if err != nil {
 		return err
@@ -212,5 +241,7 @@ func (s *Service) DeleteModifierOptions(ctx context.Context, id int) error {
 		return errors.New("modifier group not found")
 	}
 
+	s.invalidateModifierGroupCache(ctx, option.GroupID)
+
 	return nil
 }
@@ -0,0 +1,201 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"encoding/json"
+
+	"github.com/lib/pq"
+	"gorm.io/datatypes"
+	"gorm.io/gorm"
+)
+
+// UpdateMenuItemModifierGroup replaces the overrides of a group on one item
+func (s *Service) UpdateMenuItemModifierGroup(
+	ctx context.Context,
+	menuItemID int,
+	groupID int,
+	request *models.UpdateMenuItemModifierGroupRequest,
+) (*models.MenuItemModifierGroup, error) {
+
+	existing, err := s.menuItemModifierGroupRepo.FindByMenuItemIDAndGroupID(ctx, menuItemID, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	if existing == nil {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	if err := s.validateModifierOverrides(ctx, groupID, &request.ModifierGroupOverrides); err != nil {
+		return nil, err
+	}
+
+	updated, err := s.menuItemModifierGroupRepo.UpdateColumns(ctx, existing.ID, overrideColumns(&request.ModifierGroupOverrides))
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+
+	return updated, nil
+}
+
+// validateModifierOverrides makes sure the overridden options belong to the group and the
+// selection limits still make sense once merged with the group's own values
+func (s *Service) validateModifierOverrides(ctx context.Context, groupID int, overrides *models.ModifierGroupOverrides) error {
+	group, err := s.getActiveModifierGroup(ctx, groupID)
+	if err != nil {
+		return err
+	}
+
+	optionIDs := make([]int, 0, len(overrides.HiddenOptionIDs)+len(overrides.PriceOverrides))
+	optionIDs = append(optionIDs, overrides.HiddenOptionIDs...)
+	for optionID := range overrides.PriceOverrides {
+		optionIDs = append(optionIDs, optionID)
+	}
+
+	if len(optionIDs) > 0 {
+		options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ? AND group_id = ?", optionIDs, groupID)
+		})
+		if err != nil {
+			return err
+		}
+
+		found := make(map[int]bool, len(options))
+		for _, option := range options {
+			found[option.ID] = true
+		}
+		for _, optionID := range optionIDs {
+			if !found[optionID] {
+				return common.ErrInvalidModifierOverride
+			}
+		}
+	}
+
+	minSelections := group.MinSelections
+	if overrides.MinSelections != nil {
+		minSelections = *overrides.MinSelections
+	}
+	maxSelections := group.MaxSelections
+	if overrides.MaxSelections != nil {
+		maxSelections = *overrides.MaxSelections
+	}
+	if maxSelections > 0 && minSelections > maxSelections {
+		return common.ErrInvalidModifierOverride
+	}
+
+	return nil
+}
+
+func overrideColumns(overrides *models.ModifierGroupOverrides) map[string]interface{} {
+	hiddenOptionIDs := make(pq.Int64Array, 0, len(overrides.HiddenOptionIDs))
+	for _, optionID := range overrides.HiddenOptionIDs {
+		hiddenOptionIDs = append(hiddenOptionIDs, int64(optionID))
+	}
+
+	priceOverrides := overrides.PriceOverrides
+	if priceOverrides == nil {
+		priceOverrides = map[int]float64{}
+	}
+
+	columns := map[string]interface{}{
+		"display_order":     overrides.DisplayOrder,
+		"min_selections":    overrides.MinSelections,
+		"max_selections":    overrides.MaxSelections,
+		"hidden_option_ids": hiddenOptionIDs,
+	}
+
+	// map updates skip the model serializer, so the JSON is written by hand
+	if prices, err := json.Marshal(priceOverrides); err == nil {
+		columns["price_overrides"] = datatypes.JSON(prices)
+	}
+
+	return columns
+}
+
+// modifierGroupOverride is an assignment's overrides resolved against its group
+type modifierGroupOverride struct {
+	assignment *models.MenuItemModifierGroup
+	group      *models.ModifierGroup
+}
+
+func (o modifierGroupOverride) displayOrder() int {
+	if o.assignment.DisplayOrder != nil {
+		return *o.assignment.DisplayOrder
+	}
+	return o.group.DisplayOrder
+}
+
+func (o modifierGroupOverride) minSelections() int {
+	if o.assignment.MinSelections != nil {
+		return *o.assignment.MinSelections
+	}
+	return o.group.MinSelections
+}
+
+func (o modifierGroupOverride) maxSelections() int {
+	if o.assignment.MaxSelections != nil {
+		return *o.assignment.MaxSelections
+	}
+	return o.group.MaxSelections
+}
+
+func (o modifierGroupOverride) isHidden(optionID int) bool {
+	for _, hiddenID := range o.assignment.HiddenOptionIDs {
+		if int(hiddenID) == optionID {
+			return true
+		}
+	}
+	return false
+}
+
+func (o modifierGroupOverride) price(option *models.ModifierOption) float64 {
+	if price, ok := o.assignment.PriceOverrides[option.ID]; ok {
+		return price
+	}
+	return option.PriceAdjustment
+}
+
+// apply rewrites a freshly built top level node with the item's overrides. Child groups opened
+// by the options are shared and keep their own settings.
+func (o modifierGroupOverride) apply(node *models.ModifierGroupNode, options map[int]*models.ModifierOption) {
+	node.MinSelections = o.minSelections()
+	node.MaxSelections = o.maxSelections()
+
+	visible := make([]*models.ModifierOptionNode, 0, len(node.Options))
+	for _, optionNode := range node.Options {
+		if o.isHidden(optionNode.ID) {
+			continue
+		}
+		if option, ok := options[optionNode.ID]; ok {
+			optionNode.PriceAdjustment = o.price(option)
+		}
+		visible = append(visible, optionNode)
+	}
+	node.Options = visible
+}
+
+// toOverrides is the assignment as the admin edits it, nil when nothing is overridden
+func (o modifierGroupOverride) toOverrides() *models.ModifierGroupOverrides {
+	assignment := o.assignment
+	if assignment.DisplayOrder == nil && assignment.MinSelections == nil && assignment.MaxSelections == nil &&
+		len(assignment.HiddenOptionIDs) == 0 && len(assignment.PriceOverrides) == 0 {
+		return nil
+	}
+
+	hiddenOptionIDs := make([]int, 0, len(assignment.HiddenOptionIDs))
+	for _, optionID := range assignment.HiddenOptionIDs {
+		hiddenOptionIDs = append(hiddenOptionIDs, int(optionID))
+	}
+
+	return &models.ModifierGroupOverrides{
+		DisplayOrder:    assignment.DisplayOrder,
+		MinSelections:   assignment.MinSelections,
+		MaxSelections:   assignment.MaxSelections,
+		HiddenOptionIDs: hiddenOptionIDs,
+		PriceOverrides:  assignment.PriceOverrides,
+	}
+}
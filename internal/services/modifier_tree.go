@@ -0,0 +1,374 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/config"
+	"app-noti/internal/models"
+	"context"
+	"sort"
+
+	"gorm.io/gorm"
+)
+
+const defaultModifierMaxDepth = 3
+
+func modifierMaxDepth() int {
+	if config.Config.Menu.ModifierMaxDepth > 0 {
+		return config.Config.Menu.ModifierMaxDepth
+	}
+	return defaultModifierMaxDepth
+}
+
+// modifierTree holds the modifier trees of a set of menu items, plus every option met on the way
+type modifierTree struct {
+	roots   map[int][]*models.ModifierGroupNode
+	options map[int]*models.ModifierOption
+}
+
+// getModifierTrees loads the groups assigned to the items and walks down the options' child
+// groups one level at a time, up to the configured depth. Inactive groups and options are left out,
+// sold out options stay in with is_available false.
+func (s *Service) getModifierTrees(ctx context.Context, menuItemIDs []int, locale string) (*modifierTree, error) {
+	tree := &modifierTree{
+		roots:   make(map[int][]*models.ModifierGroupNode),
+		options: make(map[int]*models.ModifierOption),
+	}
+	if len(menuItemIDs) == 0 {
+		return tree, nil
+	}
+
+	associations, err := s.menuItemModifierGroupRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("menu_item_id IN ?", menuItemIDs)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	groupMap := make(map[int]*models.ModifierGroup)
+	optionsByGroup := make(map[int][]*models.ModifierOption)
+	childLinks := make(map[int][]*models.ModifierOptionChildGroup)
+
+	frontier := make([]int, 0, len(associations))
+	for _, association := range associations {
+		frontier = append(frontier, association.GroupID)
+	}
+
+	for depth := 1; depth <= modifierMaxDepth() && len(frontier) > 0; depth++ {
+		groups, err := s.modifierGroupRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ? AND status = ? AND is_deleted = FALSE", frontier, "active")
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		groupIDs := make([]int, 0, len(groups))
+		for _, group := range groups {
+			groupMap[group.ID] = group
+			groupIDs = append(groupIDs, group.ID)
+		}
+		if len(groupIDs) == 0 {
+			break
+		}
+
+		options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+			tx.Where("group_id IN ? AND status IN ?", groupIDs, []string{"active", "sold_out"})
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		optionIDs := make([]int, 0, len(options))
+		for _, option := range options {
+			optionsByGroup[option.GroupID] = append(optionsByGroup[option.GroupID], option)
+			tree.options[option.ID] = option
+			optionIDs = append(optionIDs, option.ID)
+		}
+		if len(optionIDs) == 0 {
+			break
+		}
+
+		links, err := s.modifierChildGroupRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+			tx.Where("option_id IN ?", optionIDs)
+		})
+		if err != nil {
+			return nil, err
+		}
+
+		frontier = make([]int, 0, len(links))
+		for _, link := range links {
+			childLinks[link.OptionID] = append(childLinks[link.OptionID], link)
+			if _, loaded := groupMap[link.GroupID]; !loaded {
+				frontier = append(frontier, link.GroupID)
+			}
+		}
+	}
+
+	allGroupIDs := make([]int, 0, len(groupMap))
+	for groupID := range groupMap {
+		allGroupIDs = append(allGroupIDs, groupID)
+	}
+	allOptionIDs := make([]int, 0, len(tree.options))
+	for optionID := range tree.options {
+		allOptionIDs = append(allOptionIDs, optionID)
+	}
+	groupTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityModifierGroup, allGroupIDs)
+	optionTranslations := s.getTranslationMap(ctx, locale, models.TranslationEntityModifierOption, allOptionIDs)
+
+	var buildGroup func(groupID int, depth int) *models.ModifierGroupNode
+	buildGroup = func(groupID int, depth int) *models.ModifierGroupNode {
+		group, ok := groupMap[groupID]
+		if !ok || depth > modifierMaxDepth() {
+			return nil
+		}
+
+		name := group.Name
+		localize(groupTranslations[group.ID], &name, nil)
+
+		node := &models.ModifierGroupNode{
+			ID:            group.ID,
+			Name:          name,
+			SelectionType: group.SelectionType,
+			IsRequired:    group.IsRequired,
+			MinSelections: group.MinSelections,
+			MaxSelections: group.MaxSelections,
+			Options:       make([]*models.ModifierOptionNode, 0, len(optionsByGroup[group.ID])),
+		}
+
+		for _, option := range optionsByGroup[group.ID] {
+			optionName := option.Name
+			localize(optionTranslations[option.ID], &optionName, nil)
+
+			optionNode := &models.ModifierOptionNode{
+				ID:              option.ID,
+				Name:            optionName,
+				PriceAdjustment: option.PriceAdjustment,
+				IsAvailable:     isModifierOptionOrderable(option),
+				Allergens:       option.Allergens,
+				DietaryTags:     option.DietaryTags,
+			}
+			for _, link := range childLinks[option.ID] {
+				if child := buildGroup(link.GroupID, depth+1); child != nil {
+					optionNode.Children = append(optionNode.Children, child)
+				}
+			}
+			node.Options = append(node.Options, optionNode)
+		}
+
+		return node
+	}
+
+	rootOrder := make(map[*models.ModifierGroupNode]int, len(associations))
+	for _, association := range associations {
+		node := buildGroup(association.GroupID, 1)
+		if node == nil {
+			continue
+		}
+
+		override := modifierGroupOverride{assignment: association, group: groupMap[association.GroupID]}
+		override.apply(node, tree.options)
+		rootOrder[node] = override.displayOrder()
+		tree.roots[association.MenuItemID] = append(tree.roots[association.MenuItemID], node)
+	}
+
+	for menuItemID, roots := range tree.roots {
+		sort.SliceStable(roots, func(i, j int) bool {
+			return rootOrder[roots[i]] < rootOrder[roots[j]]
+		})
+		tree.roots[menuItemID] = roots
+	}
+
+	return tree, nil
+}
+
+func isModifierOptionOrderable(option *models.ModifierOption) bool {
+	return option.Status == "active" && (option.StockQuantity == nil || *option.StockQuantity > 0)
+}
+
+// validateModifierNodes walks the groups the guest could see: every group must satisfy its rules,
+// and the children of a picked option are validated in turn
+func validateModifierNodes(groups []*models.ModifierGroupNode, selected map[int]bool, visited map[int]*models.ModifierOptionNode) error {
+	for _, group := range groups {
+		count := 0
+		for _, option := range group.Options {
+			if !selected[option.ID] {
+				continue
+			}
+
+			if !option.IsAvailable {
+				return common.ErrItemUnavailable
+			}
+
+			count++
+			visited[option.ID] = option
+			if err := validateModifierNodes(option.Children, selected, visited); err != nil {
+				return err
+			}
+		}
+
+		if !isGroupSelectionValid(group, count) {
+			return common.ErrInvalidModifierSelection
+		}
+	}
+
+	return nil
+}
+
+func (s *Service) GetModifierOptionChildGroups(ctx context.Context, optionID int) ([]*models.ModifierOptionChildGroup, error) {
+	if _, err := s.modifierOptionRepo.GetByID(ctx, optionID); err != nil {
+		return nil, err
+	}
+
+	return s.modifierChildGroupRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+		tx.Where("option_id = ?", optionID)
+	})
+}
+
+// AttachChildGroup makes the option open another group. The link is refused when the group can
+// already reach the option's own group (a loop) or when the deepest path would pass the max depth.
+func (s *Service) AttachChildGroup(ctx context.Context, optionID int, request *models.AttachChildGroupRequest) (*models.ModifierOptionChildGroup, error) {
+	option, err := s.modifierOptionRepo.GetByID(ctx, optionID)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getActiveModifierGroup(ctx, request.GroupID); err != nil {
+		return nil, err
+	}
+
+	link := &models.ModifierOptionChildGroup{
+		OptionID:     optionID,
+		GroupID:      request.GroupID,
+		DisplayOrder: request.DisplayOrder,
+	}
+
+	err = s.modifierChildGroupRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		// one writer at a time so two links cannot form a loop together
+		if err := tx.Exec("LOCK TABLE modifier_option_child_groups IN SHARE ROW EXCLUSIVE MODE").Error; err != nil {
+			return err
+		}
+
+		edges, err := loadModifierGroupEdges(tx)
+		if err != nil {
+			return err
+		}
+
+		parentGroupID := option.GroupID
+		if parentGroupID == request.GroupID || canReachGroup(edges.children, request.GroupID, parentGroupID) {
+			return common.ErrModifierCycle
+		}
+
+		if depthAbove(edges.parents, parentGroupID)+heightBelow(edges.children, request.GroupID) > modifierMaxDepth() {
+			return common.ErrModifierDepthExceeded
+		}
+
+		if err := tx.Create(link).Error; err != nil {
+			return common.PgErrorTransform(err)
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateModifierGroupCache(ctx, option.GroupID)
+
+	return link, nil
+}
+
+func (s *Service) DetachChildGroup(ctx context.Context, optionID int, groupID int) error {
+	result := s.modifierChildGroupRepo.GetDB().WithContext(ctx).
+		Where("option_id = ? AND group_id = ?", optionID, groupID).
+		Delete(&models.ModifierOptionChildGroup{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.invalidateModifierOptionCache(ctx, optionID)
+
+	return nil
+}
+
+// modifierGroupEdges is the group graph: a group points to the groups its options open
+type modifierGroupEdges struct {
+	children map[int][]int
+	parents  map[int][]int
+}
+
+func loadModifierGroupEdges(tx *gorm.DB) (*modifierGroupEdges, error) {
+	var rows []struct {
+		ParentGroupID int `gorm:"column:parent_group_id"`
+		ChildGroupID  int `gorm:"column:child_group_id"`
+	}
+
+	err := tx.Raw(`
+		SELECT DISTINCT mo.group_id AS parent_group_id, l.group_id AS child_group_id
+		FROM modifier_option_child_groups l
+		JOIN modifier_options mo ON mo.id = l.option_id
+	`).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	edges := &modifierGroupEdges{
+		children: make(map[int][]int),
+		parents:  make(map[int][]int),
+	}
+	for _, row := range rows {
+		edges.children[row.ParentGroupID] = append(edges.children[row.ParentGroupID], row.ChildGroupID)
+		edges.parents[row.ChildGroupID] = append(edges.parents[row.ChildGroupID], row.ParentGroupID)
+	}
+
+	return edges, nil
+}
+
+func canReachGroup(children map[int][]int, from int, target int) bool {
+	seen := make(map[int]bool)
+	stack := []int{from}
+	for len(stack) > 0 {
+		groupID := stack[len(stack)-1]
+		stack = stack[:len(stack)-1]
+		if groupID == target {
+			return true
+		}
+		if seen[groupID] {
+			continue
+		}
+		seen[groupID] = true
+		stack = append(stack, children[groupID]...)
+	}
+	return false
+}
+
+// depthAbove is the level of the group in its deepest placement, a top level group being 1
+func depthAbove(parents map[int][]int, groupID int) int {
+	return longestPath(parents, groupID, make(map[int]int))
+}
+
+// heightBelow is the number of levels from the group down to its deepest child group, itself included
+func heightBelow(children map[int][]int, groupID int) int {
+	return longestPath(children, groupID, make(map[int]int))
+}
+
+func longestPath(edges map[int][]int, groupID int, memo map[int]int) int {
+	if length, ok := memo[groupID]; ok {
+		return length
+	}
+
+	// the graph is kept acyclic, the marker only protects against bad data
+	memo[groupID] = 1
+
+	longest := 0
+	for _, next := range edges[groupID] {
+		if length := longestPath(edges, next, memo); length > longest {
+			longest = length
+		}
+	}
+
+	memo[groupID] = longest + 1
+	return longest + 1
+}
@@ -0,0 +1,172 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"errors"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// AcceptOrder moves a pending order to processing and takes its items out of stock
+func (s *Service) AcceptOrder(ctx context.Context, id int, request *models.OrderActionRequest) (*models.Order, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	var accepted *models.Order
+	err := s.orderRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id, restaurantID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status != models.OrderStatusPending {
+			return common.ErrInvalidOrderStatus
+		}
+
+		if !order.StockConsumed {
+			if err := s.consumeOrderStock(tx, order.ID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{
+			"status":         models.OrderStatusProcessing,
+			"stock_consumed": true,
+			"accepted_at":    now,
+			"updated_at":     now,
+		}
+		if err := tx.Model(order).Updates(columns).Error; err != nil {
+			return err
+		}
+
+		accepted = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateOrderMenuCache(ctx, accepted)
+
+	return accepted, nil
+}
+
+// CancelOrder cancels an open order and puts back whatever stock it consumed
+func (s *Service) CancelOrder(ctx context.Context, id int, request *models.OrderActionRequest) (*models.Order, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	var cancelled *models.Order
+	err := s.orderRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		order, err := lockOrder(tx, id, restaurantID)
+		if err != nil {
+			return err
+		}
+
+		if order.Status != models.OrderStatusPending && order.Status != models.OrderStatusProcessing {
+			return common.ErrInvalidOrderStatus
+		}
+
+		if order.StockConsumed {
+			if err := s.restoreOrderStock(tx, order.ID); err != nil {
+				return err
+			}
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{
+			"status":         models.OrderStatusCancelled,
+			"stock_consumed": false,
+			"cancelled_at":   now,
+			"updated_at":     now,
+		}
+		if err := tx.Model(order).Updates(columns).Error; err != nil {
+			return err
+		}
+
+		cancelled = order
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateOrderMenuCache(ctx, cancelled)
+
+	return cancelled, nil
+}
+
+// lockOrder loads an order of the restaurant for update. An order of another restaurant is not
+// found, the way other restaurant-scoped admin paths answer.
+func lockOrder(tx *gorm.DB, id int, restaurantID int) (*models.Order, error) {
+	var order models.Order
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+		First(&order, "id = ? AND COALESCE(restaurant_id, 1) = ?", id, restaurantID).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrOrderNotFound
+		}
+		return nil, err
+	}
+
+	return &order, nil
+}
+
+func getOrderItems(tx *gorm.DB, orderID int) ([]*models.OrderItem, error) {
+	var items []*models.OrderItem
+	err := tx.Where("order_id = ?", orderID).Find(&items).Error
+	return items, err
+}
+
+func (s *Service) consumeOrderStock(tx *gorm.DB, orderID int) error {
+	items, err := getOrderItems(tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	menuItems, options, err := orderStockQuantities(items)
+	if err != nil {
+		return err
+	}
+	if err := s.consumeStock(tx, models.StockEntityMenuItem, menuItems); err != nil {
+		return err
+	}
+
+	if err := s.consumeStock(tx, models.StockEntityModifierOption, options); err != nil {
+		return err
+	}
+
+	return consumeOrderIngredients(tx, orderID, menuItems, options)
+}
+
+func (s *Service) restoreOrderStock(tx *gorm.DB, orderID int) error {
+	items, err := getOrderItems(tx, orderID)
+	if err != nil {
+		return err
+	}
+
+	menuItems, options, err := orderStockQuantities(items)
+	if err != nil {
+		return err
+	}
+
+	// ingredients go back first so restocked items are not held sold out by a missing ingredient
+	if err := restoreOrderIngredients(tx, orderID); err != nil {
+		return err
+	}
+
+	if err := s.restoreStock(tx, models.StockEntityMenuItem, menuItems); err != nil {
+		return err
+	}
+
+	return s.restoreStock(tx, models.StockEntityModifierOption, options)
+}
@@ -0,0 +1,355 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/pkg/imageproc"
+	"context"
+	"fmt"
+	"io"
+	"mime/multipart"
+	"slices"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+func (s *Service) GetMenuItemPhotos(ctx context.Context, menuItemID int) ([]*models.MenuItemPhoto, error) {
+	if _, err := s.getActiveMenuItem(ctx, menuItemID); err != nil {
+		return nil, err
+	}
+
+	return s.menuItemPhotoRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"},
+	}, func(tx *gorm.DB) {
+		tx.Where("menu_item_id = ?", menuItemID)
+	})
+}
+
+// AddMenuItemPhoto appends a photo to the item. The first photo of an item is always primary.
+func (s *Service) AddMenuItemPhoto(ctx context.Context, menuItemID int, request *models.CreateMenuItemPhotoRequest) (*models.MenuItemPhoto, error) {
+	photo := &models.MenuItemPhoto{
+		MenuItemID: menuItemID,
+		Url:        request.URL,
+		Variants:   photoVariants(request.URL),
+	}
+
+	err := s.withLockedMenuItem(ctx, menuItemID, func(tx *gorm.DB) error {
+		var current struct {
+			Count        int
+			DisplayOrder int
+		}
+		err := tx.Model(&models.MenuItemPhoto{}).
+			Select("COUNT(*) AS count, COALESCE(MAX(display_order), 0) AS display_order").
+			Where("menu_item_id = ?", menuItemID).
+			Scan(&current).Error
+		if err != nil {
+			return err
+		}
+
+		photo.DisplayOrder = current.DisplayOrder + 1
+		photo.IsPrimary = request.IsPrimary || current.Count == 0
+		if photo.IsPrimary {
+			if err := clearPrimaryPhoto(tx, menuItemID); err != nil {
+				return err
+			}
+		}
+
+		return tx.Create(photo).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+	return photo, nil
+}
+
+// DeleteMenuItemPhoto removes a photo and its stored object. When the primary photo goes, the
+// next one in order takes its place.
+func (s *Service) DeleteMenuItemPhoto(ctx context.Context, menuItemID int, photoID int) error {
+	var photo models.MenuItemPhoto
+	err := s.withLockedMenuItem(ctx, menuItemID, func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND menu_item_id = ?", photoID, menuItemID).First(&photo).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Delete(&photo).Error; err != nil {
+			return err
+		}
+
+		if !photo.IsPrimary {
+			return nil
+		}
+
+		return tx.Exec(`
+			UPDATE menu_item_photos SET is_primary = TRUE
+			WHERE id = (
+				SELECT id FROM menu_item_photos
+				WHERE menu_item_id = ?
+				ORDER BY display_order, id
+				LIMIT 1
+			)`, menuItemID).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+	s.removePhotoObjects(ctx, []*models.MenuItemPhoto{&photo})
+	return nil
+}
+
+func (s *Service) SetPrimaryMenuItemPhoto(ctx context.Context, menuItemID int, photoID int) (*models.MenuItemPhoto, error) {
+	var photo models.MenuItemPhoto
+	err := s.withLockedMenuItem(ctx, menuItemID, func(tx *gorm.DB) error {
+		if err := tx.Where("id = ? AND menu_item_id = ?", photoID, menuItemID).First(&photo).Error; err != nil {
+			return err
+		}
+
+		if photo.IsPrimary {
+			return nil
+		}
+
+		if err := clearPrimaryPhoto(tx, menuItemID); err != nil {
+			return err
+		}
+
+		photo.IsPrimary = true
+		return tx.Model(&photo).Update("is_primary", true).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+	return &photo, nil
+}
+
+func (s *Service) ReorderMenuItemPhotos(ctx context.Context, menuItemID int, request *models.ReorderRequest) ([]*models.ReorderResponse, error) {
+	if _, err := s.getActiveMenuItem(ctx, menuItemID); err != nil {
+		return nil, err
+	}
+
+	scope := reorderScope{
+		table:     "menu_item_photos",
+		condition: "menu_item_id = ?",
+		args:      []interface{}{menuItemID},
+	}
+	if err := s.applyReorder(ctx, scope, request.IDs); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuItemCache(ctx, menuItemID)
+
+	return toReorderResponses(request.IDs), nil
+}
+
+// withLockedMenuItem runs fn in a transaction holding the item row, so two photo changes on the
+// same item cannot both leave a primary photo behind
+func (s *Service) withLockedMenuItem(ctx context.Context, menuItemID int, fn func(tx *gorm.DB) error) error {
+	return s.menuItemRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id = ? AND is_deleted = FALSE", menuItemID).
+			First(&models.MenuItem{}).Error
+		if err != nil {
+			return err
+		}
+
+		return fn(tx)
+	})
+}
+
+func clearPrimaryPhoto(tx *gorm.DB, menuItemID int) error {
+	return tx.Model(&models.MenuItemPhoto{}).
+		Where("menu_item_id = ? AND is_primary = TRUE", menuItemID).
+		Update("is_primary", false).Error
+}
+
+// newMenuItemPhotos turns the images of a create or update request into rows in the given order,
+// keeping exactly one primary: the first one flagged, or the first photo when none is.
+func newMenuItemPhotos(menuItemID int, images []models.CreateMenuItemPhotoRequest) []*models.MenuItemPhoto {
+	photos := make([]*models.MenuItemPhoto, 0, len(images))
+	primary := 0
+	for i, image := range images {
+		if image.IsPrimary {
+			primary = i
+			break
+		}
+	}
+
+	for i, image := range images {
+		photos = append(photos, &models.MenuItemPhoto{
+			MenuItemID:   menuItemID,
+			Url:          image.URL,
+			IsPrimary:    i == primary,
+			DisplayOrder: i + 1,
+			Variants:     photoVariants(image.URL),
+		})
+	}
+
+	return photos
+}
+
+// removePhotoObjects deletes uploaded photos from storage once their rows are gone. A url can be
+// attached to several items or a combo, so photos still shown anywhere keep their objects.
+// Failures are only logged: an orphaned object costs storage, not correctness.
+func (s *Service) removePhotoObjects(ctx context.Context, photos []*models.MenuItemPhoto) {
+	if len(photos) == 0 {
+		return
+	}
+
+	candidates := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		candidates = append(candidates, photo.Url)
+	}
+	var inUse []string
+	err := s.menuItemPhotoRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT url FROM menu_item_photos WHERE url IN ?
+		UNION
+		SELECT image_url FROM combos WHERE image_url IN ?
+	`, candidates, candidates).Scan(&inUse).Error
+	if err != nil {
+		s.logger.Warn("Failed to check photo references", zap.Error(err))
+		return
+	}
+
+	unused := make([]*models.MenuItemPhoto, 0, len(photos))
+	for _, photo := range photos {
+		if !slices.Contains(inUse, photo.Url) {
+			unused = append(unused, photo)
+		}
+	}
+
+	for _, url := range photoObjectURLs(unused) {
+		// urls from anywhere else are not ours to delete
+		key, ok := s.storage.Key(url)
+		if !ok {
+			continue
+		}
+		if err := s.storage.Delete(context.Background(), key); err != nil {
+			s.logger.Warn("Failed to delete photo object", zap.String("url", url), zap.Error(err))
+		}
+	}
+}
+
+// photoVariantSizes are the variants made for every upload: the longer side in pixels and the
+// JPEG quality. There is no WebP: the standard library cannot encode it and the only encoders
+// need cgo and libwebp.
+var photoVariantSizes = []struct {
+	name    string
+	size    int
+	quality int
+}{
+	{name: models.PhotoSizeThumb, size: 320, quality: 75},
+	{name: models.PhotoSizeCard, size: 800, quality: 80},
+	{name: models.PhotoSizeFull, size: 1600, quality: 85},
+}
+
+// UploadMenuPhoto checks the upload by its content rather than its declared type, turns it
+// upright, strips its metadata and stores a JPEG per variant size under one folder. The full
+// variant's url is the one to add to an item.
+func (s *Service) UploadMenuPhoto(ctx context.Context, file *multipart.FileHeader) (*models.UploadedPhotoResponse, error) {
+	src, err := file.Open()
+	if err != nil {
+		return nil, err
+	}
+	defer src.Close()
+
+	data, err := io.ReadAll(src)
+	if err != nil {
+		return nil, err
+	}
+
+	response, err := s.storeMenuPhoto(ctx, data, file.Filename)
+	if err != nil {
+		return nil, err
+	}
+
+	// registered like a confirmed direct upload, so it is cleaned up if never attached
+	now := time.Now()
+	_, err = s.uploadRepo.Create(ctx, &models.Upload{
+		ObjectKey:   response.key,
+		ContentType: "image/jpeg",
+		SizeBytes:   int64(len(data)),
+		Status:      models.UploadStatusConfirmed,
+		URL:         response.URL,
+		Variants:    response.Variants,
+		ConfirmedAt: &now,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &response.UploadedPhotoResponse, nil
+}
+
+type storedPhoto struct {
+	models.UploadedPhotoResponse
+	key string
+}
+
+// storeMenuPhoto makes and stores the variants of an image. source only names the image in logs.
+func (s *Service) storeMenuPhoto(ctx context.Context, data []byte, source string) (*storedPhoto, error) {
+	img, err := imageproc.Decode(data)
+	if err != nil {
+		s.logger.Warn("Rejected photo upload", zap.String("source", source), zap.Error(err))
+		return nil, common.ErrInvalidImage
+	}
+
+	folder := fmt.Sprintf("%s/%s", common.MENU_PHOTO_FOLDER, uuid.New().String())
+	response := &storedPhoto{}
+	response.Variants = make(map[string]string, len(photoVariantSizes))
+	for _, variant := range photoVariantSizes {
+		encoded, err := imageproc.EncodeJPEG(imageproc.Fit(img, variant.size), variant.quality)
+		if err != nil {
+			return nil, err
+		}
+
+		key := fmt.Sprintf("%s/%s.jpg", folder, variant.name)
+		if err := s.storage.Put(ctx, key, encoded, "image/jpeg"); err != nil {
+			return nil, err
+		}
+		response.Variants[variant.name] = s.storage.URL(key)
+		if variant.name == models.PhotoSizeFull {
+			response.key = key
+		}
+	}
+	response.URL = response.Variants[models.PhotoSizeFull]
+
+	return response, nil
+}
+
+// photoVariants finds the variants of a photo from its url. Uploads store every variant next to
+// the full one; photos from anywhere else have none.
+func photoVariants(url string) map[string]string {
+	suffix := "/" + models.PhotoSizeFull + ".jpg"
+	if !strings.Contains(url, "/"+common.MENU_PHOTO_FOLDER+"/") || !strings.HasSuffix(url, suffix) {
+		return nil
+	}
+
+	folder := strings.TrimSuffix(url, suffix)
+	variants := make(map[string]string, len(photoVariantSizes))
+	for _, variant := range photoVariantSizes {
+		variants[variant.name] = fmt.Sprintf("%s/%s.jpg", folder, variant.name)
+	}
+	return variants
+}
+
+// photoObjectURLs lists every stored object of the photos, variants included
+func photoObjectURLs(photos []*models.MenuItemPhoto) []string {
+	urls := make([]string, 0, len(photos))
+	for _, photo := range photos {
+		urls = append(urls, photo.Url)
+		for _, url := range photo.Variants {
+			if url != photo.Url {
+				urls = append(urls, url)
+			}
+		}
+	}
+	return urls
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"math"
+
+	"gorm.io/gorm"
+)
+
+// Quote prices a cart the same way an order would be priced: item price plus modifier
+// adjustments, or combo price plus choice upcharges. Everything is checked against the
+// current menu, availability and stock.
+func (s *Service) Quote(ctx context.Context, request *models.QuoteRequest) (*models.QuoteResponse, error) {
+	response := &models.QuoteResponse{
+		Items: make([]*models.QuoteLineResponse, 0, len(request.Items)),
+	}
+
+	// what the whole cart takes out of stock
+	menuItemUsage := make(map[int]int)
+	optionUsage := make(map[int]int)
+
+	for _, item := range request.Items {
+		var line *models.QuoteLineResponse
+		var err error
+
+		switch {
+		case item.MenuItemID != nil && item.ComboID == nil:
+			line, err = s.quoteMenuItemLine(ctx, item, menuItemUsage, optionUsage)
+		case item.ComboID != nil && item.MenuItemID == nil:
+			line, err = s.quoteComboLine(ctx, item, menuItemUsage, optionUsage)
+		default:
+			err = common.ErrInvalidQuoteItem
+		}
+		if err != nil {
+			return nil, err
+		}
+
+		response.Items = append(response.Items, line)
+		response.Subtotal += line.Subtotal
+	}
+
+	if err := s.checkQuoteStock(ctx, menuItemUsage, optionUsage); err != nil {
+		return nil, err
+	}
+
+	response.Subtotal = roundPrice(response.Subtotal)
+	response.Total = response.Subtotal
+
+	return response, nil
+}
+
+func (s *Service) quoteMenuItemLine(ctx context.Context, item models.QuoteItemRequest, menuItemUsage map[int]int, optionUsage map[int]int) (*models.QuoteLineResponse, error) {
+	menuItem, err := s.getOrderableMenuItem(ctx, *item.MenuItemID)
+	if err != nil {
+		return nil, err
+	}
+
+	options, err := s.validateModifierSelection(ctx, menuItem.ID, item.ModifierOptionIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	unitPrice := menuItem.Price
+	for _, option := range options {
+		unitPrice += option.PriceAdjustment
+		optionUsage[option.ID] += item.Quantity
+	}
+	menuItemUsage[menuItem.ID] += item.Quantity
+
+	menuItemID := menuItem.ID
+	return &models.QuoteLineResponse{
+		MenuItemID: &menuItemID,
+		Name:       menuItem.Name,
+		Quantity:   item.Quantity,
+		BasePrice:  menuItem.Price,
+		UnitPrice:  roundPrice(unitPrice),
+		Subtotal:   roundPrice(unitPrice * float64(item.Quantity)),
+		Options:    toQuoteOptions(options),
+	}, nil
+}
+
+func (s *Service) quoteComboLine(ctx context.Context, item models.QuoteItemRequest, menuItemUsage map[int]int, optionUsage map[int]int) (*models.QuoteLineResponse, error) {
+	combo, err := s.comboRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", *item.ComboID)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if combo.Status != "available" {
+		return nil, common.ErrItemUnavailable
+	}
+
+	slots, err := s.comboSlotRepo.List(ctx, models.QueryParams{QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"}}, func(tx *gorm.DB) {
+		tx.Where("combo_id = ?", combo.ID)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	slotMap := make(map[int]*models.ComboSlot, len(slots))
+	slotIDs := make([]int, 0, len(slots))
+	for _, slot := range slots {
+		slotMap[slot.ID] = slot
+		slotIDs = append(slotIDs, slot.ID)
+	}
+
+	choiceMap := make(map[int]map[int]*models.ComboSlotChoice)
+	if len(slotIDs) > 0 {
+		choices, err := s.comboSlotChoiceRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("slot_id IN ?", slotIDs)
+		})
+		if err != nil {
+			return nil, err
+		}
+		for _, choice := range choices {
+			if choiceMap[choice.SlotID] == nil {
+				choiceMap[choice.SlotID] = make(map[int]*models.ComboSlotChoice)
+			}
+			choiceMap[choice.SlotID][choice.MenuItemID] = choice
+		}
+	}
+
+	unitPrice := combo.Price
+	picked := make(map[int]int)
+	quoteChoices := make([]*models.QuoteChoiceResponse, 0, len(item.Choices))
+
+	for _, choiceRequest := range item.Choices {
+		slot, ok := slotMap[choiceRequest.SlotID]
+		if !ok {
+			return nil, common.ErrInvalidComboSelection
+		}
+
+		choice, ok := choiceMap[slot.ID][choiceRequest.MenuItemID]
+		if !ok {
+			return nil, common.ErrInvalidComboSelection
+		}
+
+		menuItem, err := s.getOrderableMenuItem(ctx, choice.MenuItemID)
+		if err != nil {
+			return nil, err
+		}
+
+		options, err := s.validateModifierSelection(ctx, menuItem.ID, choiceRequest.ModifierOptionIDs)
+		if err != nil {
+			return nil, err
+		}
+
+		unitPrice += choice.Upcharge
+		for _, option := range options {
+			unitPrice += option.PriceAdjustment
+			optionUsage[option.ID] += item.Quantity
+		}
+		menuItemUsage[menuItem.ID] += item.Quantity
+		picked[slot.ID]++
+
+		quoteChoices = append(quoteChoices, &models.QuoteChoiceResponse{
+			SlotID:     slot.ID,
+			SlotName:   slot.Name,
+			MenuItemID: menuItem.ID,
+			Name:       menuItem.Name,
+			Upcharge:   choice.Upcharge,
+			Options:    toQuoteOptions(options),
+		})
+	}
+
+	for _, slot := range slots {
+		if picked[slot.ID] < slot.MinChoices || picked[slot.ID] > slot.MaxChoices {
+			return nil, common.ErrInvalidComboSelection
+		}
+	}
+
+	comboID := combo.ID
+	return &models.QuoteLineResponse{
+		ComboID:   &comboID,
+		Name:      combo.Name,
+		Quantity:  item.Quantity,
+		BasePrice: combo.Price,
+		UnitPrice: roundPrice(unitPrice),
+		Subtotal:  roundPrice(unitPrice * float64(item.Quantity)),
+		Choices:   quoteChoices,
+	}, nil
+}
+
+func (s *Service) getOrderableMenuItem(ctx context.Context, id int) (*models.MenuItem, error) {
+	menuItem, err := s.menuItemRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if !isMenuItemOrderable(menuItem) {
+		return nil, common.ErrItemUnavailable
+	}
+
+	return menuItem, nil
+}
+
+// validateModifierSelection checks the picked options against the item's modifier tree: every
+// option must sit in a group the guest can reach (a top level group, or a group opened by another
+// picked option) and be available, and each reachable group's required / min / max rules must hold.
+// Returns the options in the order they were picked, priced with the item's overrides.
+func (s *Service) validateModifierSelection(ctx context.Context, menuItemID int, optionIDs []int) ([]*models.ModifierOption, error) {
+	tree, err := s.getModifierTrees(ctx, []int{menuItemID}, common.DEFAULT_LOCALE)
+	if err != nil {
+		return nil, err
+	}
+
+	selected := make(map[int]bool, len(optionIDs))
+	for _, optionID := range optionIDs {
+		if selected[optionID] {
+			return nil, common.ErrInvalidModifierSelection
+		}
+		selected[optionID] = true
+	}
+
+	visited := make(map[int]*models.ModifierOptionNode, len(optionIDs))
+	if err := validateModifierNodes(tree.roots[menuItemID], selected, visited); err != nil {
+		return nil, err
+	}
+
+	options := make([]*models.ModifierOption, 0, len(optionIDs))
+	for _, optionID := range optionIDs {
+		node, ok := visited[optionID]
+		if !ok {
+			return nil, common.ErrInvalidModifierSelection
+		}
+
+		// the node carries the price this item charges for the option
+		option := *tree.options[optionID]
+		option.PriceAdjustment = node.PriceAdjustment
+		options = append(options, &option)
+	}
+
+	return options, nil
+}
+
+func isGroupSelectionValid(group *models.ModifierGroupNode, count int) bool {
+	minSelections := group.MinSelections
+	if group.IsRequired && minSelections < 1 {
+		minSelections = 1
+	}
+	if count < minSelections {
+		return false
+	}
+
+	maxSelections := group.MaxSelections
+	if group.SelectionType == "single" {
+		maxSelections = 1
+	}
+	if maxSelections > 0 && count > maxSelections {
+		return false
+	}
+
+	return true
+}
+
+// checkQuoteStock makes sure tracked items and options can cover the whole cart
+func (s *Service) checkQuoteStock(ctx context.Context, menuItemUsage map[int]int, optionUsage map[int]int) error {
+	if len(menuItemUsage) > 0 {
+		menuItems, err := s.menuItemRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ? AND stock_quantity IS NOT NULL", sortedKeys(menuItemUsage))
+		})
+		if err != nil {
+			return err
+		}
+		for _, menuItem := range menuItems {
+			if *menuItem.StockQuantity < menuItemUsage[menuItem.ID] {
+				return common.ErrInsufficientStock
+			}
+		}
+	}
+
+	if len(optionUsage) > 0 {
+		options, err := s.modifierOptionRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+			tx.Where("id IN ? AND stock_quantity IS NOT NULL", sortedKeys(optionUsage))
+		})
+		if err != nil {
+			return err
+		}
+		for _, option := range options {
+			if *option.StockQuantity < optionUsage[option.ID] {
+				return common.ErrInsufficientStock
+			}
+		}
+	}
+
+	return nil
+}
+
+func toQuoteOptions(options []*models.ModifierOption) []*models.QuoteOptionResponse {
+	if len(options) == 0 {
+		return nil
+	}
+
+	quoteOptions := make([]*models.QuoteOptionResponse, 0, len(options))
+	for _, option := range options {
+		quoteOptions = append(quoteOptions, &models.QuoteOptionResponse{
+			ID:              option.ID,
+			Name:            option.Name,
+			PriceAdjustment: option.PriceAdjustment,
+		})
+	}
+
+	return quoteOptions
+}
+
+func roundPrice(price float64) float64 {
+	return math.Round(price*100) / 100
+}
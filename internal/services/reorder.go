@@ -0,0 +1,156 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"fmt"
+
+	"github.com/lib/pq"
+	"gorm.io/gorm"
+)
+
+// reorderScope is the set of rows one reorder request has to cover, e.g. the items of a category
+type reorderScope struct {
+	table          string
+	condition      string
+	args           []interface{}
+	touchUpdatedAt bool
+}
+
+func (s *Service) ReorderMenuCategories(ctx context.Context, request *models.ReorderRequest) ([]*models.ReorderResponse, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	scope := reorderScope{
+		table:          "menu_categories",
+		condition:      "restaurant_id = ? AND is_deleted = FALSE",
+		args:           []interface{}{restaurantID},
+		touchUpdatedAt: true,
+	}
+	if err := s.applyReorder(ctx, scope, request.IDs); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, restaurantID)
+
+	return toReorderResponses(request.IDs), nil
+}
+
+func (s *Service) ReorderCategoryItems(ctx context.Context, categoryID int, request *models.ReorderRequest) ([]*models.ReorderResponse, error) {
+	category, err := s.getActiveMenuCategory(ctx, categoryID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := reorderScope{
+		table:          "menu_items",
+		condition:      "category_id = ? AND is_deleted = FALSE",
+		args:           []interface{}{categoryID},
+		touchUpdatedAt: true,
+	}
+	if err := s.applyReorder(ctx, scope, request.IDs); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, category.RestaurantID)
+
+	return toReorderResponses(request.IDs), nil
+}
+
+// ReorderModifierGroups covers every group of the restaurant, like the admin group list
+func (s *Service) ReorderModifierGroups(ctx context.Context, request *models.ReorderRequest) ([]*models.ReorderResponse, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	scope := reorderScope{
+		table:          "modifier_groups",
+		condition:      "restaurant_id = ? AND is_deleted = FALSE",
+		args:           []interface{}{restaurantID},
+		touchUpdatedAt: true,
+	}
+	if err := s.applyReorder(ctx, scope, request.IDs); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, restaurantID)
+
+	return toReorderResponses(request.IDs), nil
+}
+
+func (s *Service) ReorderModifierOptions(ctx context.Context, groupID int, request *models.ReorderRequest) ([]*models.ReorderResponse, error) {
+	group, err := s.getActiveModifierGroup(ctx, groupID)
+	if err != nil {
+		return nil, err
+	}
+
+	scope := reorderScope{
+		table:     "modifier_options",
+		condition: "group_id = ?",
+		args:      []interface{}{groupID},
+	}
+	if err := s.applyReorder(ctx, scope, request.IDs); err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, group.RestaurantID)
+
+	return toReorderResponses(request.IDs), nil
+}
+
+// applyReorder numbers the rows 1..n in the order of ids, in one transaction. The ids must be
+// exactly the rows of the scope: a stale list from another admin's screen is refused rather than
+// leaving two rows on the same position.
+func (s *Service) applyReorder(ctx context.Context, scope reorderScope, ids []int) error {
+	return s.menuItemRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var currentIDs []int
+		err := tx.Raw(fmt.Sprintf("SELECT id FROM %s WHERE %s FOR UPDATE", scope.table, scope.condition), scope.args...).
+			Scan(&currentIDs).Error
+		if err != nil {
+			return err
+		}
+
+		if len(currentIDs) != len(ids) {
+			return common.ErrInvalidReorder
+		}
+
+		current := make(map[int]bool, len(currentIDs))
+		for _, id := range currentIDs {
+			current[id] = true
+		}
+		for _, id := range ids {
+			if !current[id] {
+				return common.ErrInvalidReorder
+			}
+		}
+
+		orderedIDs := make(pq.Int64Array, 0, len(ids))
+		for _, id := range ids {
+			orderedIDs = append(orderedIDs, int64(id))
+		}
+
+		set := "display_order = u.position"
+		if scope.touchUpdatedAt {
+			set += ", updated_at = NOW()"
+		}
+
+		return tx.Exec(fmt.Sprintf(`
+			UPDATE %s t
+			SET %s
+			FROM unnest(?::int[]) WITH ORDINALITY AS u(id, position)
+			WHERE t.id = u.id
+		`, scope.table, set), orderedIDs).Error
+	})
+}
+
+func toReorderResponses(ids []int) []*models.ReorderResponse {
+	responses := make([]*models.ReorderResponse, 0, len(ids))
+	for i, id := range ids {
+		responses = append(responses, &models.ReorderResponse{ID: id, DisplayOrder: i + 1})
+	}
+	return responses
+}
@@ -0,0 +1,570 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/config"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultReservationDurationMinutes = 90
+	defaultUpcomingReservationHours   = 24
+)
+
+// reservationTransitions lists the statuses a reservation can move to from each status
+var reservationTransitions = map[string][]string{
+	models.ReservationStatusBooked: {
+		models.ReservationStatusSeated,
+		models.ReservationStatusNoShow,
+		models.ReservationStatusCancelled,
+	},
+	models.ReservationStatusSeated: {
+		models.ReservationStatusCompleted,
+	},
+}
+
+// reservationDuration is the requested duration, or else the turn time for the party size
+func reservationDuration(requested *int, partySize int) int {
+	if requested != nil {
+		return *requested
+	}
+	for _, turnTime := range config.Config.Reservation.TurnTimes {
+		if (turnTime.MaxPartySize == 0 || partySize <= turnTime.MaxPartySize) && turnTime.Minutes > 0 {
+			return turnTime.Minutes
+		}
+	}
+	if config.Config.Reservation.DefaultDurationMinutes > 0 {
+		return config.Config.Reservation.DefaultDurationMinutes
+	}
+	return defaultReservationDurationMinutes
+}
+
+// reservationBuffer is how long a table stays blocked after a reservation, to clear and reset it
+func reservationBuffer() time.Duration {
+	return time.Duration(max(config.Config.Reservation.BufferMinutes, 0)) * time.Minute
+}
+
+func upcomingReservationWindow() time.Duration {
+	hours := config.Config.Reservation.UpcomingHours
+	if hours <= 0 {
+		hours = defaultUpcomingReservationHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+func maxCombinedTables() int {
+	if config.Config.Reservation.MaxCombinedTables > 0 {
+		return config.Config.Reservation.MaxCombinedTables
+	}
+	return defaultMaxCombinedTables
+}
+
+// localTime is t in the server's zone. Timestamp columns and tsranges keep only the wall clock,
+// and every other timestamp is written from time.Now(), so a time sent with any offset is
+// converted first: the same instant then always lands on the same clock reading.
+func localTime(t time.Time) time.Time {
+	return t.In(time.Local)
+}
+
+// fromTimestamp reads a timestamp column, which comes back with its clock reading labelled UTC,
+// as a time in the server's zone so it compares with localTime and time.Now()
+func fromTimestamp(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.Local)
+}
+
+// tsRange formats [start, end) the way postgres reads a tsrange. Like every timestamp column,
+// it keeps the wall clock of the times, so times from requests go through localTime first.
+func tsRange(start time.Time, end time.Time) string {
+	const layout = "2006-01-02 15:04:05"
+	return fmt.Sprintf("[%s,%s)", start.Format(layout), end.Format(layout))
+}
+
+func (s *Service) GetReservations(ctx context.Context, request *models.ListReservationRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("restaurant_id = ?", restaurantID)
+		},
+	}
+	if request.Date != nil && *request.Date != "" {
+		date, err := time.Parse("2006-01-02", *request.Date)
+		if err != nil {
+			return nil, common.ErrInvalidReservationTime
+		}
+		start, end := common.GetStartEndOfDay(date)
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("starts_at >= ? AND starts_at < ?", start, end)
+		})
+	}
+	if request.Status != nil && *request.Status != "" && *request.Status != "all" {
+		status := *request.Status
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("status = ?", status)
+		})
+	}
+	if request.TableID != nil {
+		tableID := *request.TableID
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("id IN (SELECT reservation_id FROM reservation_tables WHERE table_id = ?)", tableID)
+		})
+	}
+	if request.Search != nil && *request.Search != "" {
+		search := "%" + strings.ToLower(*request.Search) + "%"
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("LOWER(guest_name) LIKE ? OR guest_phone LIKE ?", search, search)
+		})
+	}
+
+	totalCount, err := s.reservationRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams := models.QueryParams{
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+		QuerySort: models.QuerySort{Origin: "starts_at.asc,id.asc"},
+	}
+
+	reservations, err := s.reservationRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachReservationTables(ctx, reservations); err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    reservations,
+	}, nil
+}
+
+func (s *Service) GetReservationByID(ctx context.Context, id int) (*models.Reservation, error) {
+	reservation, err := s.reservationRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.attachReservationTables(ctx, []*models.Reservation{reservation}); err != nil {
+		return nil, err
+	}
+
+	return reservation, nil
+}
+
+// CreateReservation books the party and holds its tables for the whole duration. Like
+// availability, the party must be able to finish within the opening hours.
+func (s *Service) CreateReservation(ctx context.Context, request *models.CreateReservationRequest) (*models.Reservation, error) {
+	if request.StartsAt.Before(time.Now()) {
+		return nil, common.ErrInvalidReservationTime
+	}
+
+	startsAt := localTime(request.StartsAt)
+	durationMinutes := reservationDuration(request.DurationMinutes, request.PartySize)
+	if !s.withinOpeningHours(startsAt, time.Duration(durationMinutes)*time.Minute) {
+		return nil, common.ErrOutsideOpeningHours
+	}
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	reservation := &models.Reservation{
+		RestaurantID:    restaurantID,
+		GuestName:       request.GuestName,
+		GuestPhone:      request.GuestPhone,
+		PartySize:       request.PartySize,
+		StartsAt:        startsAt,
+		DurationMinutes: durationMinutes,
+		Status:          models.ReservationStatusBooked,
+		Notes:           request.Notes,
+	}
+
+	err := s.reservationRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Create(reservation).Error; err != nil {
+			return err
+		}
+
+		return assignReservationTables(tx, reservation, request.TableIDs, request.Location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReservationByID(ctx, reservation.ID)
+}
+
+// UpdateReservation changes a booked reservation. A new time, duration or party size checks its
+// tables again, and the update fails if they no longer fit. A new time or duration must also fit
+// the opening hours.
+func (s *Service) UpdateReservation(ctx context.Context, id int, request *models.UpdateReservationRequest) (*models.Reservation, error) {
+	if request.StartsAt != nil && request.StartsAt.Before(time.Now()) {
+		return nil, common.ErrInvalidReservationTime
+	}
+
+	err := s.reservationRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reservation, err := lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if reservation.Status != models.ReservationStatusBooked {
+			return common.ErrInvalidReservationStatus
+		}
+
+		columns := map[string]interface{}{
+			"updated_at": time.Now(),
+		}
+		if request.GuestName != nil {
+			columns["guest_name"] = *request.GuestName
+		}
+		if request.GuestPhone != nil {
+			columns["guest_phone"] = *request.GuestPhone
+		}
+		if request.Notes != nil {
+			columns["notes"] = *request.Notes
+		}
+		if request.PartySize != nil {
+			reservation.PartySize = *request.PartySize
+			columns["party_size"] = *request.PartySize
+		}
+		if request.StartsAt != nil {
+			reservation.StartsAt = localTime(*request.StartsAt)
+			columns["starts_at"] = reservation.StartsAt
+		}
+		if request.DurationMinutes != nil {
+			reservation.DurationMinutes = *request.DurationMinutes
+			columns["duration_minutes"] = *request.DurationMinutes
+		}
+
+		if request.StartsAt != nil || request.DurationMinutes != nil {
+			duration := time.Duration(reservation.DurationMinutes) * time.Minute
+			if !s.withinOpeningHours(fromTimestamp(reservation.StartsAt), duration) {
+				return common.ErrOutsideOpeningHours
+			}
+		}
+
+		if err := tx.Model(reservation).Updates(columns).Error; err != nil {
+			return err
+		}
+
+		reassign := request.PartySize != nil || request.StartsAt != nil || request.DurationMinutes != nil ||
+			len(request.TableIDs) > 0 || request.Location != nil
+		if !reassign {
+			return nil
+		}
+
+		tableIDs := request.TableIDs
+		if len(tableIDs) == 0 && request.Location == nil {
+			if err := tx.Model(&models.ReservationTable{}).
+				Where("reservation_id = ? AND is_active = TRUE", reservation.ID).
+				Pluck("table_id", &tableIDs).Error; err != nil {
+				return err
+			}
+		}
+
+		if err := tx.Where("reservation_id = ?", reservation.ID).Delete(&models.ReservationTable{}).Error; err != nil {
+			return err
+		}
+
+		return assignReservationTables(tx, reservation, tableIDs, request.Location)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReservationByID(ctx, id)
+}
+
+// UpdateReservationStatus moves a reservation along. Seating marks its tables occupied and
+// completing frees them; a reservation that ends in any way stops holding its tables.
+func (s *Service) UpdateReservationStatus(ctx context.Context, id int, request *models.UpdateReservationStatusRequest) (*models.Reservation, error) {
+	err := s.reservationRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		reservation, err := lockReservation(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(reservationTransitions[reservation.Status], request.Status) {
+			return common.ErrInvalidReservationStatus
+		}
+
+		var tableIDs []int
+		if err := tx.Model(&models.ReservationTable{}).
+			Where("reservation_id = ? AND is_active = TRUE", reservation.ID).
+			Pluck("table_id", &tableIDs).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{
+			"status":     request.Status,
+			"updated_at": now,
+		}
+
+		switch request.Status {
+		case models.ReservationStatusSeated:
+			var unavailable int64
+			if err := tx.Model(&models.Table{}).
+				Where("id IN ? AND status <> ?", tableIDs, "active").
+				Count(&unavailable).Error; err != nil {
+				return err
+			}
+			if unavailable > 0 {
+				return common.ErrTableNotAvailable
+			}
+
+			if err := setTablesStatus(tx, tableIDs, "occupied", now); err != nil {
+				return err
+			}
+			columns["seated_at"] = now
+		case models.ReservationStatusCompleted:
+			if err := setTablesStatus(tx, tableIDs, "active", now); err != nil {
+				return err
+			}
+			columns["completed_at"] = now
+		case models.ReservationStatusCancelled:
+			columns["cancelled_at"] = now
+		}
+
+		if request.Status != models.ReservationStatusSeated {
+			if err := tx.Model(&models.ReservationTable{}).
+				Where("reservation_id = ?", reservation.ID).
+				Update("is_active", false).Error; err != nil {
+				return err
+			}
+		}
+
+		return tx.Model(reservation).Updates(columns).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetReservationByID(ctx, id)
+}
+
+// assignReservationTables holds tableIDs for the reservation, or else the first free option
+// availability would offer, in location when given: the smallest single table that seats the
+// party, or the smallest combination of tables from one zone. Tables are held until the buffer
+// after the reservation has passed. The exclusion constraint on reservation_tables has the last
+// word on overlaps.
+func assignReservationTables(tx *gorm.DB, reservation *models.Reservation, tableIDs []int, location *string) error {
+	start := reservation.StartsAt
+	end := start.Add(time.Duration(reservation.DurationMinutes)*time.Minute + reservationBuffer())
+	during := tsRange(start, end)
+
+	var tables []*models.Table
+	if len(tableIDs) > 0 {
+		ids := slices.Clone(tableIDs)
+		slices.Sort(ids)
+		ids = slices.Compact(ids)
+		if err := tx.Where("id IN ? AND restaurant_id = ?", ids, reservation.RestaurantID).Find(&tables).Error; err != nil {
+			return err
+		}
+		if len(tables) != len(ids) {
+			return gorm.ErrRecordNotFound
+		}
+
+		capacity := 0
+		for _, table := range tables {
+			if table.Status == "inactive" {
+				return common.ErrTableNotAvailable
+			}
+			capacity += table.Capacity
+		}
+
+		// zones are checked the way auto-assign picks tables: by zone_id, not the location copy
+		var active int64
+		if err := tx.Model(&models.Table{}).Where("id IN ?", ids).Where(activeZoneCondition).Count(&active).Error; err != nil {
+			return err
+		}
+		if int(active) != len(ids) {
+			return common.ErrTableNotAvailable
+		}
+		if location != nil {
+			var inZone int64
+			if err := tx.Model(&models.Table{}).Where("id IN ?", ids).Where(zoneNameCondition, *location).Count(&inZone).Error; err != nil {
+				return err
+			}
+			if int(inZone) != len(ids) {
+				return common.ErrTableLocationMismatch
+			}
+		}
+
+		if capacity < reservation.PartySize {
+			return common.ErrInsufficientTableCapacity
+		}
+	} else {
+		query := tx.Where("restaurant_id = ? AND status <> ?", reservation.RestaurantID, "inactive").
+			Where(activeZoneCondition).
+			Where(`NOT EXISTS (
+				SELECT 1 FROM reservation_tables rt
+				WHERE rt.table_id = tables.id AND rt.is_active = TRUE AND rt.during && ?::tsrange
+			)`, during)
+		if location != nil {
+			query = query.Where(zoneNameCondition, *location)
+		}
+
+		var free []*models.Table
+		if err := query.Order("capacity ASC, id ASC").Find(&free).Error; err != nil {
+			return err
+		}
+
+		options := tableOptions(free, reservation.PartySize, maxCombinedTables())
+		if len(options) == 0 {
+			return common.ErrNoTableAvailable
+		}
+		for _, table := range free {
+			if slices.Contains(options[0].TableIDs, table.ID) {
+				tables = append(tables, table)
+			}
+		}
+	}
+
+	rows := make([]*models.ReservationTable, 0, len(tables))
+	for _, table := range tables {
+		rows = append(rows, &models.ReservationTable{
+			ReservationID: reservation.ID,
+			TableID:       table.ID,
+			During:        during,
+			IsActive:      true,
+		})
+	}
+
+	if err := tx.Create(&rows).Error; err != nil {
+		if strings.Contains(err.Error(), "reservation_tables_no_overlap") {
+			return common.ErrTableAlreadyBooked
+		}
+		return err
+	}
+
+	return nil
+}
+
+func setTablesStatus(tx *gorm.DB, tableIDs []int, status string, now time.Time) error {
+	if len(tableIDs) == 0 {
+		return nil
+	}
+
+	return tx.Model(&models.Table{}).
+		Where("id IN ?", tableIDs).
+		Updates(map[string]interface{}{"status": status, "updated_at": now}).Error
+}
+
+func lockReservation(tx *gorm.DB, id int) (*models.Reservation, error) {
+	var reservation models.Reservation
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&reservation, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrReservationNotFound
+		}
+		return nil, err
+	}
+
+	return &reservation, nil
+}
+
+// attachReservationTables loads the tables of each reservation, including tables it has given up.
+// Every read of reservations goes through it, so it also reads their times as server-local: a
+// starts_at sent back unchanged then books the same time.
+func (s *Service) attachReservationTables(ctx context.Context, reservations []*models.Reservation) error {
+	if len(reservations) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(reservations))
+	for _, reservation := range reservations {
+		ids = append(ids, reservation.ID)
+		reservation.StartsAt = fromTimestamp(reservation.StartsAt)
+		reservation.EndsAt = fromTimestamp(reservation.EndsAt)
+		reservation.Tables = []*models.ReservedTable{}
+	}
+
+	var rows []struct {
+		ReservationID int
+		models.ReservedTable
+	}
+	err := s.reservationRepo.GetDB().WithContext(ctx).
+		Table("reservation_tables rt").
+		Select("rt.reservation_id, t.id, t.table_number, t.capacity, COALESCE(t.location, '') AS location").
+		Joins("JOIN tables t ON t.id = rt.table_id").
+		Where("rt.reservation_id IN ?", ids).
+		Order("t.table_number").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]*models.Reservation, len(reservations))
+	for _, reservation := range reservations {
+		byID[reservation.ID] = reservation
+	}
+	for _, row := range rows {
+		table := row.ReservedTable
+		byID[row.ReservationID].Tables = append(byID[row.ReservationID].Tables, &table)
+	}
+
+	return nil
+}
+
+// getUpcomingReservationMap lists, per table, the reservations holding it from now until the end
+// of the upcoming window
+func (s *Service) getUpcomingReservationMap(ctx context.Context, tableIDs []int) map[int][]*models.TableReservationSummary {
+	result := make(map[int][]*models.TableReservationSummary)
+	if len(tableIDs) == 0 {
+		return result
+	}
+
+	now := time.Now()
+	var rows []struct {
+		TableID int
+		models.TableReservationSummary
+	}
+	err := s.reservationRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT rt.table_id, r.id, r.guest_name, r.party_size, r.starts_at, r.ends_at, r.status
+		FROM reservation_tables rt
+		JOIN reservations r ON r.id = rt.reservation_id
+		WHERE rt.table_id IN ? AND rt.is_active = TRUE AND r.ends_at > ? AND r.starts_at < ?
+		ORDER BY r.starts_at, r.id
+	`, tableIDs, now, now.Add(upcomingReservationWindow())).Scan(&rows).Error
+	if err != nil {
+		s.logger.Warn("Failed to load upcoming reservations", zap.Error(err))
+		return result
+	}
+
+	for _, row := range rows {
+		summary := row.TableReservationSummary
+		summary.StartsAt = fromTimestamp(summary.StartsAt)
+		summary.EndsAt = fromTimestamp(summary.EndsAt)
+		result[row.TableID] = append(result[row.TableID], &summary)
+	}
+
+	return result
+}
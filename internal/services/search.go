@@ -0,0 +1,165 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"context"
+	"strings"
+	"unicode"
+
+	"github.com/lib/pq"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const menuSearchHeadlineOptions = "StartSel=<mark>, StopSel=</mark>, MaxWords=20, MinWords=8, MaxFragments=1, HighlightAll=FALSE"
+
+// menuSearch matches items on their name and description (full text, accent insensitive, prefix
+// matching), on near misses of the name (trigram), on the category name and on dietary tags.
+type menuSearch struct {
+	text    string
+	tsQuery string
+	tags    []string
+}
+
+func newMenuSearch(search *string) *menuSearch {
+	if search == nil {
+		return nil
+	}
+
+	text := strings.ToLower(strings.TrimSpace(*search))
+	terms := strings.FieldsFunc(text, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(terms) == 0 {
+		return nil
+	}
+
+	prefixes := make([]string, 0, len(terms))
+	for _, term := range terms {
+		prefixes = append(prefixes, term+":*")
+	}
+
+	return &menuSearch{
+		text:    strings.Join(terms, " "),
+		tsQuery: strings.Join(prefixes, " & "),
+		tags:    searchDietaryTags(terms),
+	}
+}
+
+// searchDietaryTags picks dietary tags out of the search words, "gluten free" included
+func searchDietaryTags(terms []string) []string {
+	tags := make([]string, 0)
+	joined := " " + strings.Join(terms, " ") + " "
+	for _, tag := range []string{models.DietaryVegan, models.DietaryVegetarian, models.DietaryHalal, models.DietaryGlutenFree} {
+		words := " " + strings.ReplaceAll(tag, "_", " ") + " "
+		if strings.Contains(joined, words) || strings.Contains(joined, " "+strings.ReplaceAll(tag, "_", "")+" ") {
+			tags = append(tags, tag)
+		}
+	}
+	return tags
+}
+
+func (m *menuSearch) filter(locale string) repositories.Clause {
+	return func(tx *gorm.DB) {
+		conditions := []string{
+			"menu_items.search_vector @@ to_tsquery('public.menu_search', @query)",
+			"public.f_unaccent(@text) <% public.f_unaccent(lower(menu_items.name))",
+			`menu_items.category_id IN (
+				SELECT mc.id FROM menu_categories mc
+				WHERE to_tsvector('public.menu_search', mc.name) @@ to_tsquery('public.menu_search', @query)
+					OR public.f_unaccent(@text) <% public.f_unaccent(lower(mc.name))
+			)`,
+		}
+		if len(m.tags) > 0 {
+			conditions = append(conditions, "menu_items.dietary_tags && @tags")
+		}
+		if locale != "" && locale != common.DEFAULT_LOCALE {
+			conditions = append(conditions, `EXISTS (
+				SELECT 1 FROM menu_translations mt
+				WHERE mt.entity_type = @entity_type AND mt.entity_id = menu_items.id AND mt.locale = @locale
+					AND (
+						to_tsvector('public.menu_search', mt.name || ' ' || coalesce(mt.description, '')) @@ to_tsquery('public.menu_search', @query)
+						OR public.f_unaccent(@text) <% public.f_unaccent(lower(mt.name))
+					)
+			)`)
+		}
+
+		tx.Where("("+strings.Join(conditions, " OR ")+")", map[string]interface{}{
+			"query":       m.tsQuery,
+			"text":        m.text,
+			"tags":        pq.StringArray(m.tags),
+			"entity_type": models.TranslationEntityMenuItem,
+			"locale":      locale,
+		})
+	}
+}
+
+// order ranks full text hits first and lets trigram similarity break ties and order typo matches
+func (m *menuSearch) order() repositories.Clause {
+	return func(tx *gorm.DB) {
+		tx.Order(clause.Expr{
+			SQL: `ts_rank(menu_items.search_vector, to_tsquery('public.menu_search', ?))
+				+ similarity(public.f_unaccent(?), public.f_unaccent(lower(menu_items.name))) DESC, menu_items.id`,
+			Vars:               []interface{}{m.tsQuery, m.text},
+			WithoutParentheses: true,
+		})
+	}
+}
+
+// setHighlights marks the matched words in the (already localized) name and description
+func (s *Service) setHighlights(ctx context.Context, search *menuSearch, items []*models.MenuItemResponse) {
+	if search == nil || len(items) == 0 {
+		return
+	}
+
+	ids := make([]int64, 0, len(items))
+	names := make([]string, 0, len(items))
+	descriptions := make([]string, 0, len(items))
+	for _, item := range items {
+		ids = append(ids, int64(item.ID))
+		names = append(names, item.Name)
+		description := ""
+		if item.Description != nil {
+			description = *item.Description
+		}
+		descriptions = append(descriptions, description)
+	}
+
+	var highlights []struct {
+		ID          int    `gorm:"column:id"`
+		Name        string `gorm:"column:name"`
+		Description string `gorm:"column:description"`
+	}
+
+	err := s.menuItemRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT
+			u.id,
+			ts_headline('public.menu_search', u.name, q.query, ?) AS name,
+			ts_headline('public.menu_search', u.description, q.query, ?) AS description
+		FROM unnest(?::int[], ?::text[], ?::text[]) AS u(id, name, description),
+			to_tsquery('public.menu_search', ?) AS q(query)
+	`, menuSearchHeadlineOptions, menuSearchHeadlineOptions,
+		pq.Int64Array(ids), pq.StringArray(names), pq.StringArray(descriptions), search.tsQuery).
+		Scan(&highlights).Error
+	if err != nil {
+		s.logger.Warn("menu search highlight failed", zap.Error(err))
+		return
+	}
+
+	highlightMap := make(map[int]*models.MenuItemHighlight, len(highlights))
+	for _, highlight := range highlights {
+		h := &models.MenuItemHighlight{Name: highlight.Name}
+		if highlight.Description != "" {
+			description := highlight.Description
+			h.Description = &description
+		}
+		highlightMap[highlight.ID] = h
+	}
+
+	for _, item := range items {
+		item.Highlight = highlightMap[item.ID]
+	}
+}
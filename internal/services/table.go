@@ -1,6 +1,7 @@
 package services
 
 import (
+	"app-noti/common"
 	"app-noti/internal/models"
 	"app-noti/internal/repositories"
 	"app-noti/pkg/utils"
@@ -29,11 +30,16 @@ func (s *Service) GetTables(ctx context.Context, request *models.ListTablesReque
 		})
 	}
 
-	// Filter by zone/location
-	if request.Zone != nil && *request.Zone != "" && *request.Zone != "all" {
+	// Filter by zone
+	if request.ZoneID != nil {
+		zoneID := *request.ZoneID
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("zone_id = ?", zoneID)
+		})
+	} else if request.Zone != nil && *request.Zone != "" && *request.Zone != "all" {
 		zone := *request.Zone
 		filters = append(filters, func(tx *gorm.DB) {
-			tx.Where("location = ?", zone)
+			tx.Where(zoneNameCondition, zone)
 		})
 	}
 
@@ -88,15 +94,25 @@ func (s *Service) GetTables(ctx context.Context, request *models.ListTablesReque
 		return nil, err
 	}
 
+	tableIDs := make([]int, 0, len(tables))
+	for _, table := range tables {
+		tableIDs = append(tableIDs, table.ID)
+	}
+	reservationMap := s.getUpcomingReservationMap(ctx, tableIDs)
+	groupMap := s.getTableGroupMap(ctx, tableIDs)
+
 	// Build response items
 	items := make([]*models.TableWithOrderData, 0, len(tables))
 	for _, table := range tables {
 		item := &models.TableWithOrderData{
-			ID:          table.ID,
-			TableNumber: table.TableNumber,
-			Capacity:    table.Capacity,
-			Location:    table.Location,
-			Status:      table.Status,
+			ID:                   table.ID,
+			TableNumber:          table.TableNumber,
+			Capacity:             table.Capacity,
+			ZoneID:               table.ZoneID,
+			Location:             table.Location,
+			Status:               table.Status,
+			UpcomingReservations: reservationMap[table.ID],
+			Group:                groupMap[table.ID],
 		}
 
 		// If table is occupied, get order data
@@ -119,31 +135,65 @@ func (s *Service) GetTables(ctx context.Context, request *models.ListTablesReque
 }
 
 func (s *Service) getTableOrderData(ctx context.Context, tableID int) (*models.TableOrderData, error) {
-	var result struct {
-		ActiveOrders int     `gorm:"column:active_orders"`
-		TotalBill    float64 `gorm:"column:total_bill"`
+	orderData, err := s.getTableOrderDataMap(ctx, []int{tableID})
+	if err != nil {
+		return nil, err
 	}
 
-	err := s.tableRepo.GetDB().Raw(`
-		SELECT 
-			COUNT(*) as active_orders,
-			COALESCE(SUM(total), 0) as total_bill
-		FROM orders
-		WHERE table_id = ? AND status IN ('pending', 'processing')
-	`, tableID).Scan(&result).Error
+	return orderData[tableID], nil
+}
+
+// getTableOrderDataMap sums the open orders of each table, with the rules of its zone applied,
+// in one query. Tables without open orders are left out.
+func (s *Service) getTableOrderDataMap(ctx context.Context, tableIDs []int) (map[int]*models.TableOrderData, error) {
+	result := make(map[int]*models.TableOrderData)
+	if len(tableIDs) == 0 {
+		return result, nil
+	}
 
+	var rows []struct {
+		TableID              int      `gorm:"column:table_id"`
+		ActiveOrders         int      `gorm:"column:active_orders"`
+		TotalBill            float64  `gorm:"column:total_bill"`
+		ServiceChargePercent *float64 `gorm:"column:service_charge_percent"`
+		MinimumSpend         *float64 `gorm:"column:minimum_spend"`
+	}
+	err := s.tableRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT
+			o.table_id,
+			COUNT(*) as active_orders,
+			COALESCE(SUM(o.total), 0) as total_bill,
+			z.service_charge_percent,
+			z.minimum_spend
+		FROM orders o
+		JOIN tables t ON t.id = o.table_id
+		LEFT JOIN zones z ON z.id = t.zone_id
+		WHERE o.table_id IN ? AND o.status IN ('pending', 'processing')
+		GROUP BY o.table_id, z.id
+	`, tableIDs).Scan(&rows).Error
 	if err != nil {
 		return nil, err
 	}
 
-	if result.ActiveOrders == 0 {
-		return nil, nil
+	for _, row := range rows {
+		orderData := &models.TableOrderData{
+			ActiveOrders: row.ActiveOrders,
+			TotalBill:    row.TotalBill,
+		}
+
+		// Apply the rules of the table's zone
+		if row.ServiceChargePercent != nil {
+			orderData.ServiceCharge = roundPrice(row.TotalBill * *row.ServiceChargePercent / 100)
+		}
+		if row.MinimumSpend != nil {
+			orderData.MinimumSpend = row.MinimumSpend
+			orderData.MinimumSpendShortfall = max(*row.MinimumSpend-row.TotalBill, 0)
+		}
+
+		result[row.TableID] = orderData
 	}
 
-	return &models.TableOrderData{
-		ActiveOrders: result.ActiveOrders,
-		TotalBill:    result.TotalBill,
-	}, nil
+	return result, nil
 }
 
 // GetTableByID retrieves a single table by ID
@@ -158,11 +208,15 @@ func (s *Service) GetTableByID(ctx context.Context, id int) (*models.TableWithOr
 		RestaurantId:     table.RestaurantId,
 		TableNumber:      table.TableNumber,
 		Capacity:         table.Capacity,
+		ZoneID:           table.ZoneID,
 		Location:         table.Location,
 		Status:           table.Status,
 		QrToken:          table.QrToken,
 		QrTokenCreatedAt: table.QrTokenCreatedAt,
 		QrTokenExpiresAt: table.QrTokenExpiresAt,
+
+		UpcomingReservations: s.getUpcomingReservationMap(ctx, []int{table.ID})[table.ID],
+		Group:                s.getTableGroupMap(ctx, []int{table.ID})[table.ID],
 	}
 
 	// If table is occupied, get order data
@@ -176,13 +230,29 @@ func (s *Service) GetTableByID(ctx context.Context, id int) (*models.TableWithOr
 	return response, nil
 }
 
-// CreateTable creates a new table
+// CreateTable creates a new table in the zone given by id or name, which must be a zone of the
+// restaurant. Its location is kept as a copy of the zone name for clients that still read it.
 func (s *Service) CreateTable(ctx context.Context, request *models.CreateTableRequest) (*models.Table, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	zone, err := resolveZone(s.tableRepo.GetDB().WithContext(ctx), restaurantID, request.ZoneID, request.Location)
+	if err != nil {
+		return nil, err
+	}
+	if zone.RestaurantID != restaurantID {
+		return nil, common.ErrZoneNotFound
+	}
+
 	table := &models.Table{
-		TableNumber: request.TableNumber,
-		Capacity:    request.Capacity,
-		Location:    request.Location,
-		Status:      request.Status,
+		RestaurantId: restaurantID,
+		TableNumber:  request.TableNumber,
+		Capacity:     request.Capacity,
+		ZoneID:       &zone.ID,
+		Location:     zone.Name,
+		Status:       request.Status,
 	}
 
 	created, err := s.tableRepo.Create(ctx, table)
@@ -207,8 +277,16 @@ func (s *Service) UpdateTable(ctx context.Context, id int, request *models.Updat
 	if request.Capacity != nil {
 		columns["capacity"] = *request.Capacity
 	}
-	if request.Location != nil {
-		columns["location"] = *request.Location
+	if request.ZoneID != nil || request.Location != nil {
+		zone, err := resolveZone(s.tableRepo.GetDB().WithContext(ctx), existing.RestaurantId, request.ZoneID, request.Location)
+		if err != nil {
+			return nil, err
+		}
+		if zone.RestaurantID != existing.RestaurantId {
+			return nil, common.ErrZoneNotFound
+		}
+		columns["zone_id"] = zone.ID
+		columns["location"] = zone.Name
 	}
 	if request.Status != nil {
 		columns["status"] = *request.Status
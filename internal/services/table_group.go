@@ -0,0 +1,354 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"errors"
+	"slices"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// openOrderStatuses are the orders still on a table's bill
+var openOrderStatuses = []string{models.OrderStatusPending, models.OrderStatusProcessing}
+
+func (s *Service) GetTableGroups(ctx context.Context, request *models.ListTableGroupsRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("restaurant_id = ?", restaurantID)
+		},
+	}
+	status := models.TableGroupStatusActive
+	if request.Status != nil && *request.Status != "" {
+		status = *request.Status
+	}
+	if status != "all" {
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("status = ?", status)
+		})
+	}
+
+	totalCount, err := s.tableGroupRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams := models.QueryParams{
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+		QuerySort: models.QuerySort{Origin: "created_at.desc,id.desc"},
+	}
+
+	groups, err := s.tableGroupRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachGroupTables(ctx, groups); err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    groups,
+	}, nil
+}
+
+func (s *Service) GetTableGroupByID(ctx context.Context, id int) (*models.TableGroup, error) {
+	group, err := s.tableGroupRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrTableGroupNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.attachGroupTables(ctx, []*models.TableGroup{group}); err != nil {
+		return nil, err
+	}
+
+	return group, nil
+}
+
+// MergeTables puts tables from one zone into a group for one party. The group uses the
+// session of its session table: open orders of the other tables move onto it, and the orders
+// placed later from any of their QR codes land on it too. Every table in the group is occupied.
+func (s *Service) MergeTables(ctx context.Context, request *models.MergeTablesRequest) (*models.TableGroup, error) {
+	ids := slices.Clone(request.TableIDs)
+	slices.Sort(ids)
+	ids = slices.Compact(ids)
+
+	sessionTableID := request.TableIDs[0]
+	if request.SessionTableID != nil {
+		sessionTableID = *request.SessionTableID
+	}
+	if len(ids) < 2 || !slices.Contains(ids, sessionTableID) {
+		return nil, common.ErrInvalidTableGroup
+	}
+
+	group := &models.TableGroup{
+		SessionTableID: sessionTableID,
+		Status:         models.TableGroupStatusActive,
+	}
+
+	err := s.tableGroupRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tables []*models.Table
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", ids).
+			Order("id").
+			Find(&tables).Error; err != nil {
+			return err
+		}
+		if len(tables) != len(ids) {
+			return gorm.ErrRecordNotFound
+		}
+
+		session := tables[slices.IndexFunc(tables, func(table *models.Table) bool { return table.ID == sessionTableID })]
+		for _, table := range tables {
+			if table.RestaurantId != session.RestaurantId {
+				return common.ErrInvalidTableGroup
+			}
+			if table.Status == "inactive" {
+				return common.ErrTableNotAvailable
+			}
+			if !sameZone(table.ZoneID, session.ZoneID) {
+				return common.ErrTableLocationMismatch
+			}
+		}
+
+		var merged int64
+		if err := tx.Model(&models.TableGroupMember{}).
+			Where("table_id IN ? AND is_active = TRUE", ids).
+			Count(&merged).Error; err != nil {
+			return err
+		}
+		if merged > 0 {
+			return common.ErrTableAlreadyMerged
+		}
+
+		group.RestaurantID = session.RestaurantId
+		if err := tx.Create(group).Error; err != nil {
+			return err
+		}
+
+		members := make([]*models.TableGroupMember, 0, len(ids))
+		for _, id := range ids {
+			members = append(members, &models.TableGroupMember{GroupID: group.ID, TableID: id, IsActive: true})
+		}
+		if err := tx.Create(&members).Error; err != nil {
+			// two merges of the same table raced
+			if strings.Contains(err.Error(), "table_group_members_one_active") {
+				return common.ErrTableAlreadyMerged
+			}
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Order{}).
+			Where("table_id IN ? AND table_id <> ? AND status IN ?", ids, sessionTableID, openOrderStatuses).
+			Updates(map[string]interface{}{
+				"source_table_id": gorm.Expr("table_id"),
+				"table_id":        sessionTableID,
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+
+		return setTablesStatus(tx, ids, "occupied", now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTableGroupByID(ctx, group.ID)
+}
+
+// SplitTableGroup breaks a group back into its tables. Open orders placed from another table
+// go back to it. Tables left with open orders stay occupied and the others become active.
+func (s *Service) SplitTableGroup(ctx context.Context, id int) (*models.TableGroup, error) {
+	err := s.tableGroupRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		group, err := lockTableGroup(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if group.Status != models.TableGroupStatusActive {
+			return common.ErrTableGroupSplit
+		}
+
+		var tableIDs []int
+		if err := tx.Model(&models.TableGroupMember{}).
+			Where("group_id = ? AND is_active = TRUE", group.ID).
+			Pluck("table_id", &tableIDs).Error; err != nil {
+			return err
+		}
+
+		now := time.Now()
+		if err := tx.Model(&models.Order{}).
+			Where("table_id = ? AND source_table_id IN ? AND status IN ?", group.SessionTableID, tableIDs, openOrderStatuses).
+			Updates(map[string]interface{}{
+				"table_id":        gorm.Expr("source_table_id"),
+				"source_table_id": nil,
+				"updated_at":      now,
+			}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(&models.TableGroupMember{}).
+			Where("group_id = ?", group.ID).
+			Update("is_active", false).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Model(group).Updates(map[string]interface{}{
+			"status":     models.TableGroupStatusSplit,
+			"split_at":   now,
+			"updated_at": now,
+		}).Error; err != nil {
+			return err
+		}
+
+		var busy []int
+		if err := tx.Model(&models.Order{}).
+			Where("table_id IN ? AND status IN ?", tableIDs, openOrderStatuses).
+			Distinct().
+			Pluck("table_id", &busy).Error; err != nil {
+			return err
+		}
+
+		free := slices.DeleteFunc(slices.Clone(tableIDs), func(id int) bool { return slices.Contains(busy, id) })
+		if err := setTablesStatus(tx, busy, "occupied", now); err != nil {
+			return err
+		}
+		return setTablesStatus(tx, free, "active", now)
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetTableGroupByID(ctx, id)
+}
+
+func lockTableGroup(tx *gorm.DB, id int) (*models.TableGroup, error) {
+	var group models.TableGroup
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&group, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrTableGroupNotFound
+		}
+		return nil, err
+	}
+
+	return &group, nil
+}
+
+// attachGroupTables loads the tables of each group, including those of split groups, and the
+// open bill of the active ones
+func (s *Service) attachGroupTables(ctx context.Context, groups []*models.TableGroup) error {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(groups))
+	for _, group := range groups {
+		ids = append(ids, group.ID)
+		group.Tables = []*models.GroupedTable{}
+	}
+
+	var rows []struct {
+		GroupID int
+		models.GroupedTable
+	}
+	err := s.tableGroupRepo.GetDB().WithContext(ctx).
+		Table("table_group_members m").
+		Select("m.group_id, t.id, t.table_number, t.capacity, COALESCE(t.location, '') AS location, t.status").
+		Joins("JOIN tables t ON t.id = m.table_id").
+		Where("m.group_id IN ?", ids).
+		Order("t.table_number").
+		Scan(&rows).Error
+	if err != nil {
+		return err
+	}
+
+	byID := make(map[int]*models.TableGroup, len(groups))
+	for _, group := range groups {
+		byID[group.ID] = group
+	}
+	for _, row := range rows {
+		table := row.GroupedTable
+		byID[row.GroupID].Tables = append(byID[row.GroupID].Tables, &table)
+		byID[row.GroupID].Capacity += table.Capacity
+	}
+
+	for _, group := range groups {
+		if group.Status != models.TableGroupStatusActive {
+			continue
+		}
+		orderData, err := s.getTableOrderData(ctx, group.SessionTableID)
+		if err != nil {
+			return err
+		}
+		group.OrderData = orderData
+	}
+
+	return nil
+}
+
+// getTableGroupMap finds the active group each of the tables is merged into
+func (s *Service) getTableGroupMap(ctx context.Context, tableIDs []int) map[int]*models.TableGroupSummary {
+	result := make(map[int]*models.TableGroupSummary)
+	if len(tableIDs) == 0 {
+		return result
+	}
+
+	var rows []struct {
+		GroupID        int
+		SessionTableID int
+		TableID        int
+		Capacity       int
+	}
+	err := s.tableGroupRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT g.id AS group_id, g.session_table_id, m.table_id, t.capacity
+		FROM table_groups g
+		JOIN table_group_members m ON m.group_id = g.id AND m.is_active = TRUE
+		JOIN tables t ON t.id = m.table_id
+		WHERE g.status = ? AND g.id IN (
+			SELECT group_id FROM table_group_members WHERE is_active = TRUE AND table_id IN ?
+		)
+		ORDER BY m.table_id
+	`, models.TableGroupStatusActive, tableIDs).Scan(&rows).Error
+	if err != nil {
+		s.logger.Warn("Failed to load table groups", zap.Error(err))
+		return result
+	}
+
+	groups := make(map[int]*models.TableGroupSummary)
+	for _, row := range rows {
+		group, ok := groups[row.GroupID]
+		if !ok {
+			group = &models.TableGroupSummary{ID: row.GroupID, SessionTableID: row.SessionTableID}
+			groups[row.GroupID] = group
+		}
+		group.TableIDs = append(group.TableIDs, row.TableID)
+		group.Capacity += row.Capacity
+		result[row.TableID] = group
+	}
+
+	return result
+}
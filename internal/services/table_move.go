@@ -0,0 +1,229 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/pkg/utils"
+	"context"
+	"slices"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// MoveTable moves guests to another table mid-meal. Without order ids the whole session moves:
+// every open order, and the old table becomes active. With order ids only those orders move,
+// and the old table stays occupied while it has open orders left. The new table becomes
+// occupied; when it already is, the orders join its session only if merging was asked for.
+// A whole session also takes along the seated reservation or waitlist party on the old table.
+// Tables merged into a group cannot be moved from or to.
+func (s *Service) MoveTable(ctx context.Context, fromTableID int, request *models.MoveTableRequest) (*models.MoveTableResponse, error) {
+	if request.ToTableID == fromTableID {
+		return nil, common.ErrInvalidTableMove
+	}
+
+	orderIDs := slices.Clone(request.OrderIDs)
+	slices.Sort(orderIDs)
+	orderIDs = slices.Compact(orderIDs)
+	wholeSession := len(orderIDs) == 0
+
+	move := &models.TableMove{
+		FromTableID:  fromTableID,
+		ToTableID:    request.ToTableID,
+		WholeSession: wholeSession,
+		Reason:       request.Reason,
+	}
+
+	err := s.tableMoveRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var tables []*models.Table
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			Where("id IN ?", []int{fromTableID, request.ToTableID}).
+			Order("id").
+			Find(&tables).Error; err != nil {
+			return err
+		}
+		if len(tables) != 2 {
+			return gorm.ErrRecordNotFound
+		}
+
+		from, to := tables[0], tables[1]
+		if from.ID != fromTableID {
+			from, to = to, from
+		}
+		if from.RestaurantId != to.RestaurantId {
+			return common.ErrInvalidTableMove
+		}
+
+		switch to.Status {
+		case "inactive":
+			return common.ErrTableNotAvailable
+		case "occupied":
+			if !request.Merge {
+				return common.ErrTableOccupied
+			}
+			move.Merged = true
+		}
+
+		var merged int64
+		if err := tx.Model(&models.TableGroupMember{}).
+			Where("table_id IN ? AND is_active = TRUE", []int{from.ID, to.ID}).
+			Count(&merged).Error; err != nil {
+			return err
+		}
+		if merged > 0 {
+			return common.ErrTableAlreadyMerged
+		}
+
+		var orders []*models.Order
+		query := tx.Clauses(clause.Locking{Strength: "UPDATE"}).Where("table_id = ?", from.ID)
+		if wholeSession {
+			query = query.Where("status IN ?", openOrderStatuses)
+		} else {
+			query = query.Where("id IN ?", orderIDs)
+		}
+		if err := query.Order("id").Find(&orders).Error; err != nil {
+			return err
+		}
+
+		if !wholeSession {
+			if len(orders) != len(orderIDs) {
+				return common.ErrOrderNotFound
+			}
+			for _, order := range orders {
+				if !slices.Contains(openOrderStatuses, order.Status) {
+					return common.ErrInvalidOrderStatus
+				}
+			}
+		}
+		// guests who have not ordered yet can still move, as long as they are seated
+		if len(orders) == 0 && from.Status != "occupied" {
+			return common.ErrInvalidTableMove
+		}
+
+		now := time.Now()
+		move.RestaurantID = from.RestaurantId
+		move.OrderIDs = make([]int, 0, len(orders))
+		for _, order := range orders {
+			move.OrderIDs = append(move.OrderIDs, order.ID)
+		}
+
+		if len(move.OrderIDs) > 0 {
+			if err := tx.Model(&models.Order{}).
+				Where("id IN ?", move.OrderIDs).
+				Updates(map[string]interface{}{"table_id": to.ID, "updated_at": now}).Error; err != nil {
+				return err
+			}
+		}
+
+		if wholeSession {
+			if err := moveSeatedParty(tx, from.ID, to.ID, now); err != nil {
+				return err
+			}
+		}
+
+		releaseFrom := wholeSession
+		if !releaseFrom {
+			var remaining int64
+			if err := tx.Model(&models.Order{}).
+				Where("table_id = ? AND status IN ?", from.ID, openOrderStatuses).
+				Count(&remaining).Error; err != nil {
+				return err
+			}
+			releaseFrom = remaining == 0
+		}
+		if releaseFrom {
+			if err := setTablesStatus(tx, []int{from.ID}, "active", now); err != nil {
+				return err
+			}
+		}
+		if err := setTablesStatus(tx, []int{to.ID}, "occupied", now); err != nil {
+			return err
+		}
+
+		return tx.Create(move).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	fromTable, err := s.GetTableByID(ctx, fromTableID)
+	if err != nil {
+		return nil, err
+	}
+	toTable, err := s.GetTableByID(ctx, request.ToTableID)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.MoveTableResponse{
+		Move:      move,
+		FromTable: fromTable,
+		ToTable:   toTable,
+	}, nil
+}
+
+// moveSeatedParty moves what the party seated at a table holds to another table: the
+// reservation_tables rows of its seated reservation, so completing it frees the right table, and
+// the table of its seated waitlist entry. When the new table is already held by a seated
+// reservation, that hold covers the merged session and the old rows are released instead.
+func moveSeatedParty(tx *gorm.DB, fromTableID int, toTableID int, now time.Time) error {
+	seated := tx.Model(&models.Reservation{}).Select("id").Where("status = ?", models.ReservationStatusSeated)
+
+	var held int64
+	if err := tx.Model(&models.ReservationTable{}).
+		Where("table_id = ? AND is_active = TRUE AND reservation_id IN (?)", toTableID, seated).
+		Count(&held).Error; err != nil {
+		return err
+	}
+
+	holds := tx.Model(&models.ReservationTable{}).
+		Where("table_id = ? AND is_active = TRUE AND reservation_id IN (?)", fromTableID, seated)
+	var err error
+	if held > 0 {
+		err = holds.Update("is_active", false).Error
+	} else {
+		err = holds.Update("table_id", toTableID).Error
+	}
+	if err != nil {
+		if strings.Contains(err.Error(), "reservation_tables_no_overlap") {
+			return common.ErrTableAlreadyBooked
+		}
+		return err
+	}
+
+	return tx.Model(&models.WaitlistEntry{}).
+		Where("table_id = ? AND status = ?", fromTableID, models.WaitlistStatusSeated).
+		Updates(map[string]interface{}{"table_id": toTableID, "updated_at": now}).Error
+}
+
+// GetTableMoves lists the moves from or to a table, latest first
+func (s *Service) GetTableMoves(ctx context.Context, tableID int, request *models.ListTableMovesRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	filter := func(tx *gorm.DB) {
+		tx.Where("from_table_id = ? OR to_table_id = ?", tableID, tableID)
+	}
+
+	totalCount, err := s.tableMoveRepo.Count(ctx, models.QueryParams{}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	moves, err := s.tableMoveRepo.List(ctx, models.QueryParams{
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+		QuerySort: models.QuerySort{Origin: "created_at.desc,id.desc"},
+	}, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    moves,
+	}, nil
+}
@@ -0,0 +1,248 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+type translationSource struct {
+	table     string
+	condition string
+}
+
+// translationSources lists the base rows that can be translated, skipping soft deleted items
+var translationSources = map[string]translationSource{
+	models.TranslationEntityCategory:       {table: "menu_categories", condition: "is_deleted = FALSE"},
+	models.TranslationEntityMenuItem:       {table: "menu_items", condition: "is_deleted = FALSE"},
+	models.TranslationEntityModifierGroup:  {table: "modifier_groups", condition: "is_deleted = FALSE"},
+	models.TranslationEntityModifierOption: {table: "modifier_options", condition: "TRUE"},
+	models.TranslationEntityCombo:          {table: "combos", condition: "is_deleted = FALSE"},
+}
+
+var translationEntityOrder = []string{
+	models.TranslationEntityCategory,
+	models.TranslationEntityMenuItem,
+	models.TranslationEntityModifierGroup,
+	models.TranslationEntityModifierOption,
+	models.TranslationEntityCombo,
+}
+
+func (s *Service) GetTranslations(ctx context.Context, request *models.ListTranslationRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	filters := []repositories.Clause{}
+
+	if request.EntityType != nil && *request.EntityType != "" {
+		entityType := *request.EntityType
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("entity_type = ?", entityType)
+		})
+	}
+
+	if request.EntityID != nil {
+		entityID := *request.EntityID
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("entity_id = ?", entityID)
+		})
+	}
+
+	if request.Locale != nil && *request.Locale != "" {
+		locale := *request.Locale
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("locale = ?", locale)
+		})
+	}
+
+	totalCount, err := s.menuTranslationRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if totalCount == 0 {
+		return &models.BaseListResponse{
+			Total:    0,
+			Page:     page,
+			PageSize: pageSize,
+			Items:    []*models.MenuTranslation{},
+		}, nil
+	}
+
+	queryParams := models.QueryParams{
+		Limit:  pageSize,
+		Offset: (page - 1) * pageSize,
+		QuerySort: models.QuerySort{
+			Origin: "id.asc",
+		},
+	}
+
+	translations, err := s.menuTranslationRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    translations,
+	}, nil
+}
+
+func (s *Service) UpsertTranslation(ctx context.Context, params *models.TranslationParamsUri, request *models.UpsertTranslationRequest) (*models.MenuTranslation, error) {
+	if params.Locale == common.DEFAULT_LOCALE || !common.IsSupportedLocale(params.Locale) {
+		return nil, common.ErrUnsupportedLocale
+	}
+
+	source := translationSources[params.EntityType]
+
+	var count int64
+	err := s.menuTranslationRepo.GetDB().WithContext(ctx).
+		Table(source.table).
+		Where("id = ? AND "+source.condition, params.EntityID).
+		Count(&count).Error
+	if err != nil {
+		return nil, err
+	}
+	if count == 0 {
+		return nil, gorm.ErrRecordNotFound
+	}
+
+	now := time.Now()
+	translation := &models.MenuTranslation{
+		EntityType:  params.EntityType,
+		EntityID:    params.EntityID,
+		Locale:      params.Locale,
+		Name:        request.Name,
+		Description: request.Description,
+		CreatedAt:   &now,
+		UpdatedAt:   &now,
+	}
+
+	err = s.menuTranslationRepo.GetDB().WithContext(ctx).
+		Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "entity_type"}, {Name: "entity_id"}, {Name: "locale"}},
+			DoUpdates: clause.AssignmentColumns([]string{"name", "description", "updated_at"}),
+		}).
+		Create(translation).Error
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateTranslationCache(ctx, params.EntityType, params.EntityID)
+
+	return translation, nil
+}
+
+func (s *Service) DeleteTranslation(ctx context.Context, params *models.TranslationParamsUri) error {
+	result := s.menuTranslationRepo.GetDB().WithContext(ctx).
+		Where("entity_type = ? AND entity_id = ? AND locale = ?", params.EntityType, params.EntityID, params.Locale).
+		Delete(&models.MenuTranslation{})
+	if result.Error != nil {
+		return result.Error
+	}
+
+	if result.RowsAffected == 0 {
+		return gorm.ErrRecordNotFound
+	}
+
+	s.invalidateTranslationCache(ctx, params.EntityType, params.EntityID)
+
+	return nil
+}
+
+// GetMissingTranslations lists menu content that has no translation in the given locale yet
+func (s *Service) GetMissingTranslations(ctx context.Context, request *models.ListMissingTranslationRequest) (*models.BaseListResponse, error) {
+	if request.Locale == common.DEFAULT_LOCALE || !common.IsSupportedLocale(request.Locale) {
+		return nil, common.ErrUnsupportedLocale
+	}
+
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	entityTypes := translationEntityOrder
+	if request.EntityType != nil && *request.EntityType != "" {
+		entityTypes = []string{*request.EntityType}
+	}
+
+	queries := make([]interface{}, 0, len(entityTypes))
+	sql := ""
+	for i, entityType := range entityTypes {
+		source := translationSources[entityType]
+		if i > 0 {
+			sql += " UNION ALL "
+		}
+		sql += "SELECT ? AS entity_type, b.id AS entity_id, b.name AS name, ? AS sort_key FROM " + source.table + " b" +
+			" WHERE " + source.condition + " AND NOT EXISTS (" +
+			"SELECT 1 FROM menu_translations t WHERE t.entity_type = ? AND t.entity_id = b.id AND t.locale = ?)"
+		queries = append(queries, entityType, i, entityType, request.Locale)
+	}
+
+	db := s.menuTranslationRepo.GetDB().WithContext(ctx)
+
+	var totalCount int64
+	if err := db.Raw("SELECT COUNT(*) FROM ("+sql+") missing", queries...).Scan(&totalCount).Error; err != nil {
+		return nil, err
+	}
+
+	if totalCount == 0 {
+		return &models.BaseListResponse{
+			Total:    0,
+			Page:     page,
+			PageSize: pageSize,
+			Items:    []*models.MissingTranslationResponse{},
+		}, nil
+	}
+
+	var missing []*models.MissingTranslationResponse
+	err := db.Raw("SELECT entity_type, entity_id, name FROM ("+sql+") missing ORDER BY sort_key, entity_id LIMIT ? OFFSET ?",
+		append(queries, pageSize, (page-1)*pageSize)...).Scan(&missing).Error
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    missing,
+	}, nil
+}
+
+// getTranslationMap returns translations keyed by entity id. Nothing is looked up for the default locale.
+func (s *Service) getTranslationMap(ctx context.Context, locale string, entityType string, entityIDs []int) map[int]*models.MenuTranslation {
+	translationMap := make(map[int]*models.MenuTranslation)
+	if locale == "" || locale == common.DEFAULT_LOCALE || len(entityIDs) == 0 {
+		return translationMap
+	}
+
+	translations, err := s.menuTranslationRepo.List(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("entity_type = ? AND locale = ? AND entity_id IN ?", entityType, locale, entityIDs)
+	})
+	if err != nil {
+		return translationMap
+	}
+
+	for _, translation := range translations {
+		translationMap[translation.EntityID] = translation
+	}
+
+	return translationMap
+}
+
+// localize swaps in the translated name and description, keeping the default language for anything missing
+func localize(translation *models.MenuTranslation, name *string, description **string) {
+	if translation == nil {
+		return
+	}
+
+	*name = translation.Name
+	if description != nil && translation.Description != nil {
+		*description = translation.Description
+	}
+}
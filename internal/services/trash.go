@@ -0,0 +1,235 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/config"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultTrashRetentionDays = 30
+	menuPurgeBatchSize        = 100
+)
+
+func menuTrashRetention() time.Duration {
+	days := config.Config.Menu.TrashRetentionDays
+	if days <= 0 {
+		days = defaultTrashRetentionDays
+	}
+	return time.Duration(days) * 24 * time.Hour
+}
+
+// GetDeletedMenuItems lists the trash, most recently deleted first
+func (s *Service) GetDeletedMenuItems(ctx context.Context, request *models.ListDeletedMenuItemRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("restaurant_id = ? AND is_deleted = TRUE", restaurantID)
+		},
+	}
+	if request.Search != nil && *request.Search != "" {
+		search := "%" + strings.ToLower(*request.Search) + "%"
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("LOWER(name) LIKE ?", search)
+		})
+	}
+
+	totalCount, err := s.menuItemRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams := models.QueryParams{
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+		QuerySort: models.QuerySort{Origin: "deleted_at.desc,id.desc"},
+	}
+
+	menuItems, err := s.menuItemRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	categoryIDs := make([]int, 0, len(menuItems))
+	itemIDs := make([]int, 0, len(menuItems))
+	for _, menuItem := range menuItems {
+		categoryIDs = append(categoryIDs, menuItem.CategoryID)
+		itemIDs = append(itemIDs, menuItem.ID)
+	}
+
+	categoryMap, err := s.getCategoryMapByIDs(ctx, categoryIDs)
+	if err != nil {
+		categoryMap = make(map[int]string)
+	}
+
+	primaryImageMap, err := s.getPrimaryImageMap(ctx, itemIDs, models.PhotoSizeThumb)
+	if err != nil {
+		primaryImageMap = make(map[int]string)
+	}
+
+	retention := menuTrashRetention()
+	items := make([]*models.DeletedMenuItemResponse, 0, len(menuItems))
+	for _, menuItem := range menuItems {
+		item := &models.DeletedMenuItemResponse{
+			ID:        menuItem.ID,
+			Name:      menuItem.Name,
+			Category:  categoryMap[menuItem.CategoryID],
+			Price:     menuItem.Price,
+			ImageURL:  primaryImageMap[menuItem.ID],
+			DeletedAt: menuItem.DeletedAt,
+		}
+		if menuItem.DeletedAt != nil {
+			purgeAt := menuItem.DeletedAt.Add(retention)
+			item.PurgeAt = &purgeAt
+		}
+
+		items = append(items, item)
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    items,
+	}, nil
+}
+
+// RestoreMenuItem takes an item out of the trash. It goes back to the end of its category, since
+// the others have been reordered without it in the meantime.
+func (s *Service) RestoreMenuItem(ctx context.Context, id int) (*models.MenuItem, error) {
+	menuItem, err := s.getDeletedMenuItem(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+
+	if _, err := s.getActiveMenuCategory(ctx, menuItem.CategoryID); err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrCategoryDeleted
+		}
+		return nil, err
+	}
+
+	columns := map[string]interface{}{
+		"is_deleted":    false,
+		"deleted_at":    nil,
+		"display_order": s.nextMenuItemDisplayOrder(ctx, menuItem.CategoryID),
+		"updated_at":    time.Now(),
+	}
+
+	restored, err := s.menuItemRepo.UpdateColumns(ctx, id, columns)
+	if err != nil {
+		return nil, err
+	}
+
+	s.invalidateMenuCache(ctx, menuItem.RestaurantID)
+	return restored, nil
+}
+
+// PurgeMenuItem removes one item of the trash for good without waiting for the retention period
+func (s *Service) PurgeMenuItem(ctx context.Context, id int) error {
+	if _, err := s.getDeletedMenuItem(ctx, id); err != nil {
+		return err
+	}
+
+	return s.purgeMenuItems(ctx, []int{id})
+}
+
+// PurgeDeletedMenuItems is run by the purge job. It removes every item that has been in the trash
+// longer than the retention period and returns how many went.
+func (s *Service) PurgeDeletedMenuItems(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-menuTrashRetention())
+
+	purged := 0
+	for {
+		menuItems, err := s.menuItemRepo.List(ctx, models.QueryParams{
+			Limit:     menuPurgeBatchSize,
+			QuerySort: models.QuerySort{Origin: "id.asc"},
+			Selected:  []string{"id"},
+		}, func(tx *gorm.DB) {
+			tx.Where("is_deleted = TRUE AND deleted_at < ?", cutoff)
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		if len(menuItems) == 0 {
+			return purged, nil
+		}
+
+		ids := make([]int, 0, len(menuItems))
+		for _, menuItem := range menuItems {
+			ids = append(ids, menuItem.ID)
+		}
+
+		if err := s.purgeMenuItems(ctx, ids); err != nil {
+			return purged, err
+		}
+		purged += len(ids)
+
+		s.logger.Info("Purged deleted menu items", zap.Ints("ids", ids))
+	}
+}
+
+func (s *Service) getDeletedMenuItem(ctx context.Context, id int) (*models.MenuItem, error) {
+	return s.menuItemRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = TRUE", id)
+	})
+}
+
+// purgeMenuItems hard-deletes items and what hangs off them, then the stored photo objects. Modifier
+// assignments, recipe lines and combo choices go with the row through their foreign keys;
+// translations and stock alerts only point at the item by entity id. Order lines keep their
+// snapshot of the name and price.
+func (s *Service) purgeMenuItems(ctx context.Context, ids []int) error {
+	var photos []*models.MenuItemPhoto
+	err := s.menuItemRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		if err := tx.Where("menu_item_id IN ?", ids).Find(&photos).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("entity_type = ? AND entity_id IN ?", models.TranslationEntityMenuItem, ids).
+			Delete(&models.MenuTranslation{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("entity_type = ? AND entity_id IN ?", models.StockEntityMenuItem, ids).
+			Delete(&models.StockAlert{}).Error; err != nil {
+			return err
+		}
+
+		if err := tx.Where("menu_item_id IN ?", ids).Delete(&models.MenuItemPhoto{}).Error; err != nil {
+			return err
+		}
+
+		return tx.Where("id IN ? AND is_deleted = TRUE", ids).Delete(&models.MenuItem{}).Error
+	})
+	if err != nil {
+		return err
+	}
+
+	s.removePhotoObjects(ctx, photos)
+	return nil
+}
+
+// getActiveMenuItem loads an item that is not in the trash. Admin endpoints that work on one item
+// go through it, so a deleted item answers not found everywhere until it is restored.
+func (s *Service) getActiveMenuItem(ctx context.Context, id int) (*models.MenuItem, error) {
+	return s.menuItemRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ? AND is_deleted = FALSE", id)
+	})
+}
@@ -0,0 +1,234 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/config"
+	"app-noti/internal/models"
+	"app-noti/pkg/storage"
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"time"
+
+	"github.com/google/uuid"
+	"go.uber.org/zap"
+	"gorm.io/gorm"
+)
+
+const (
+	defaultMaxUploadMB          = 20
+	defaultPresignExpiryMinutes = 15
+	defaultOrphanRetentionHours = 24
+	uploadCleanupBatchSize      = 100
+)
+
+var uploadExtensions = map[string]string{
+	"image/jpeg": "jpg",
+	"image/png":  "png",
+}
+
+func maxUploadSize() int64 {
+	mb := config.Config.Storage.MaxUploadMB
+	if mb <= 0 {
+		mb = defaultMaxUploadMB
+	}
+	return int64(mb) * 1024 * 1024
+}
+
+func presignExpiry() time.Duration {
+	minutes := config.Config.Storage.PresignExpiryMinutes
+	if minutes <= 0 {
+		minutes = defaultPresignExpiryMinutes
+	}
+	return time.Duration(minutes) * time.Minute
+}
+
+func orphanUploadRetention() time.Duration {
+	hours := config.Config.Storage.OrphanRetentionHours
+	if hours <= 0 {
+		hours = defaultOrphanRetentionHours
+	}
+	return time.Duration(hours) * time.Hour
+}
+
+// MaxUploadSize is the largest file a presigned url accepts
+func MaxUploadSize() int64 {
+	return maxUploadSize()
+}
+
+// PresignUpload registers a pending upload and signs a PUT for it, so the client sends the file
+// straight to storage instead of through the API
+func (s *Service) PresignUpload(ctx context.Context, request *models.PresignUploadRequest) (*models.PresignUploadResponse, error) {
+	maxSize := maxUploadSize()
+	if request.Size > maxSize {
+		return nil, common.ErrUploadTooLarge
+	}
+
+	key := fmt.Sprintf("%s/%s.%s", common.UPLOAD_FOLDER, uuid.New().String(), uploadExtensions[request.ContentType])
+	expires := presignExpiry()
+	signed, err := s.storage.Sign(ctx, key, storage.SignOptions{
+		Method:      http.MethodPut,
+		ContentType: request.ContentType,
+		Expires:     expires,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	expiresAt := time.Now().Add(expires)
+	upload, err := s.uploadRepo.Create(ctx, &models.Upload{
+		ObjectKey:   key,
+		ContentType: request.ContentType,
+		SizeBytes:   request.Size,
+		Status:      models.UploadStatusPending,
+		URL:         s.storage.URL(key),
+		ExpiresAt:   &expiresAt,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &models.PresignUploadResponse{
+		ID:        upload.ID,
+		Key:       key,
+		Upload:    signed,
+		MaxSize:   maxSize,
+		ExpiresAt: expiresAt,
+	}, nil
+}
+
+// ConfirmUpload checks that the file arrived as it was declared and marks the upload confirmed.
+// With Process set, the file is stored as photo variants and the original is removed. Confirming
+// a confirmed upload returns it unchanged.
+func (s *Service) ConfirmUpload(ctx context.Context, id int, request *models.ConfirmUploadRequest) (*models.Upload, error) {
+	upload, err := s.uploadRepo.GetByID(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	if upload.Status == models.UploadStatusConfirmed {
+		return upload, nil
+	}
+
+	info, err := s.storage.Stat(ctx, upload.ObjectKey)
+	if errors.Is(err, storage.ErrObjectNotFound) {
+		return nil, common.ErrUploadNotReceived
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// S3 cannot hold a presigned PUT to a size, so the size is only checked here
+	if info.Size != upload.SizeBytes || info.ContentType != upload.ContentType {
+		s.logger.Warn("Uploaded file does not match its presign",
+			zap.Int("upload_id", upload.ID),
+			zap.Int64("size", info.Size),
+			zap.String("content_type", info.ContentType))
+		if err := s.storage.Delete(ctx, upload.ObjectKey); err != nil {
+			s.logger.Warn("Failed to delete upload object", zap.String("key", upload.ObjectKey), zap.Error(err))
+		}
+		return nil, common.ErrUploadMismatch
+	}
+
+	now := time.Now()
+	update := &models.Upload{
+		Status:      models.UploadStatusConfirmed,
+		ConfirmedAt: &now,
+		UpdatedAt:   &now,
+	}
+
+	if request.Process {
+		data, err := s.storage.Get(ctx, upload.ObjectKey)
+		if err != nil {
+			return nil, err
+		}
+
+		photo, err := s.storeMenuPhoto(ctx, data, upload.ObjectKey)
+		if err != nil {
+			return nil, err
+		}
+		update.URL = photo.URL
+		update.Variants = photo.Variants
+	}
+
+	if _, err := s.uploadRepo.Update(ctx, upload.ID, update); err != nil {
+		return nil, err
+	}
+
+	if request.Process {
+		s.removeUploadObjects([]string{upload.ObjectKey})
+	}
+
+	return s.uploadRepo.GetByID(ctx, upload.ID)
+}
+
+// PurgeOrphanUploads deletes uploads older than the retention period that no menu item photo or
+// combo uses, objects first, in batches until none are left. Uploads whose objects could not be
+// deleted are kept for the next run.
+func (s *Service) PurgeOrphanUploads(ctx context.Context) (int, error) {
+	cutoff := time.Now().Add(-orphanUploadRetention())
+
+	purged := 0
+	lastID := 0
+	for {
+		uploads, err := s.uploadRepo.List(ctx, models.QueryParams{
+			Limit:     uploadCleanupBatchSize,
+			QuerySort: models.QuerySort{Origin: "id.asc"},
+		}, func(tx *gorm.DB) {
+			// kept uploads are skipped by id, so they are not listed again in this run
+			tx.Where("id > ? AND created_at < ?", lastID, cutoff).
+				Where("NOT EXISTS (SELECT 1 FROM menu_item_photos p WHERE p.url = uploads.url)").
+				Where("NOT EXISTS (SELECT 1 FROM combos c WHERE c.image_url = uploads.url)")
+		})
+		if err != nil {
+			return purged, err
+		}
+
+		if len(uploads) == 0 {
+			return purged, nil
+		}
+		lastID = uploads[len(uploads)-1].ID
+
+		ids := make([]int, 0, len(uploads))
+		for _, upload := range uploads {
+			if s.removeUploadObjects(uploadObjectKeys(upload, s.storage)) {
+				ids = append(ids, upload.ID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		if err := s.uploadRepo.Delete(ctx, func(tx *gorm.DB) {
+			tx.Where("id IN ?", ids)
+		}); err != nil {
+			return purged, err
+		}
+		purged += len(ids)
+
+		s.logger.Info("Purged orphan uploads", zap.Int("count", len(ids)))
+	}
+}
+
+// removeUploadObjects reports whether every object is gone
+func (s *Service) removeUploadObjects(keys []string) bool {
+	removed := true
+	for _, key := range keys {
+		if err := s.storage.Delete(context.Background(), key); err != nil {
+			s.logger.Warn("Failed to delete upload object", zap.String("key", key), zap.Error(err))
+			removed = false
+		}
+	}
+	return removed
+}
+
+// uploadObjectKeys lists the stored objects of an upload: the file as uploaded and its variants
+func uploadObjectKeys(upload *models.Upload, objectStorage storage.Storage) []string {
+	keys := []string{upload.ObjectKey}
+	for _, url := range upload.Variants {
+		if key, ok := objectStorage.Key(url); ok && key != upload.ObjectKey {
+			keys = append(keys, key)
+		}
+	}
+	return keys
+}
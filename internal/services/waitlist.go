@@ -0,0 +1,649 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/config"
+	"app-noti/internal/models"
+	"app-noti/internal/repositories"
+	"app-noti/pkg/utils"
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	defaultWaitlistStatusURL        = "https://smart-restaurant-fe.vercel.app/waitlist"
+	defaultWaitlistHistoryDays      = 30
+	defaultWaitlistMinHistoryOrders = 20
+	defaultQuoteRoundingMinutes     = 5
+)
+
+// activeWaitlistStatuses are the parties still in the queue
+var activeWaitlistStatuses = []string{models.WaitlistStatusWaiting, models.WaitlistStatusNotified}
+
+// waitlistTransitions lists the statuses an entry can move to from each status. A notified
+// party can be notified again.
+var waitlistTransitions = map[string][]string{
+	models.WaitlistStatusWaiting: {
+		models.WaitlistStatusNotified,
+		models.WaitlistStatusSeated,
+		models.WaitlistStatusRemoved,
+	},
+	models.WaitlistStatusNotified: {
+		models.WaitlistStatusNotified,
+		models.WaitlistStatusSeated,
+		models.WaitlistStatusRemoved,
+	},
+}
+
+// waitTable is a table as the wait estimate sees it: free from freeAt, except while reserved
+type waitTable struct {
+	capacity int
+	zoneID   int
+	freeAt   time.Time
+	reserved []busyRange
+}
+
+func waitlistStatusURL(token string) string {
+	base := config.Config.Waitlist.StatusURL
+	if base == "" {
+		base = defaultWaitlistStatusURL
+	}
+	return fmt.Sprintf("%s?token=%s", base, token)
+}
+
+// quoteMinutes rounds a wait up to whole minutes, then up to the quote rounding
+func quoteMinutes(wait time.Duration) int {
+	rounding := config.Config.Waitlist.QuoteRoundingMinutes
+	if rounding <= 0 {
+		rounding = defaultQuoteRoundingMinutes
+	}
+	minutes := int((wait + time.Minute - 1) / time.Minute)
+	return (minutes + rounding - 1) / rounding * rounding
+}
+
+func (s *Service) GetWaitlist(ctx context.Context, request *models.ListWaitlistRequest) (*models.BaseListResponse, error) {
+	page, pageSize := utils.GetPageAndPageSize(request.Page, request.PageSize)
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	filters := []repositories.Clause{
+		func(tx *gorm.DB) {
+			tx.Where("restaurant_id = ?", restaurantID)
+		},
+	}
+	status := "active"
+	if request.Status != nil && *request.Status != "" {
+		status = *request.Status
+	}
+	switch status {
+	case "all":
+	case "active":
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("status IN ?", activeWaitlistStatuses)
+		})
+	default:
+		filters = append(filters, func(tx *gorm.DB) {
+			tx.Where("status = ?", status)
+		})
+	}
+
+	totalCount, err := s.waitlistRepo.Count(ctx, models.QueryParams{}, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	queryParams := models.QueryParams{
+		Limit:     pageSize,
+		Offset:    (page - 1) * pageSize,
+		QuerySort: models.QuerySort{Origin: "created_at.asc,id.asc"},
+	}
+
+	entries, err := s.waitlistRepo.List(ctx, queryParams, filters...)
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachWaitEstimates(ctx, restaurantID, entries); err != nil {
+		return nil, err
+	}
+
+	return &models.BaseListResponse{
+		Total:    int(totalCount),
+		Page:     page,
+		PageSize: pageSize,
+		Items:    entries,
+	}, nil
+}
+
+func (s *Service) GetWaitlistEntryByID(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	entry, err := s.waitlistRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrWaitlistEntryNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.attachWaitEstimates(ctx, entry.RestaurantID, []*models.WaitlistEntry{entry}); err != nil {
+		return nil, err
+	}
+
+	return entry, nil
+}
+
+// GetWaitEstimate is the wait a new party of the given size would be quoted, behind everyone
+// already in the queue
+func (s *Service) GetWaitEstimate(ctx context.Context, request *models.WaitEstimateRequest) (*models.WaitEstimateResponse, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	queue, err := s.getWaitlistQueue(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+
+	partySizes := make([]int, 0, len(queue)+1)
+	for _, entry := range queue {
+		partySizes = append(partySizes, entry.PartySize)
+	}
+	waits, err := s.estimateWaits(ctx, restaurantID, append(partySizes, request.PartySize))
+	if err != nil {
+		return nil, err
+	}
+
+	response := &models.WaitEstimateResponse{
+		PartySize:    request.PartySize,
+		PartiesAhead: len(queue),
+	}
+	if wait := waits[len(waits)-1]; wait != nil {
+		minutes := quoteMinutes(*wait)
+		response.EstimatedWaitMinutes = &minutes
+	}
+
+	return response, nil
+}
+
+// CreateWaitlistEntry adds a walk-in party to the end of the queue. It is quoted the estimated
+// wait unless the host gives one.
+func (s *Service) CreateWaitlistEntry(ctx context.Context, request *models.CreateWaitlistEntryRequest) (*models.WaitlistEntry, error) {
+	quoted := request.QuotedWaitMinutes
+	if quoted == nil {
+		estimate, err := s.GetWaitEstimate(ctx, &models.WaitEstimateRequest{
+			RestaurantID: request.RestaurantID,
+			PartySize:    request.PartySize,
+		})
+		if err != nil {
+			return nil, err
+		}
+		if estimate.EstimatedWaitMinutes == nil {
+			return nil, common.ErrWaitEstimateUnavailable
+		}
+		quoted = estimate.EstimatedWaitMinutes
+	}
+
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	token, err := generateSecureToken(16)
+	if err != nil {
+		return nil, err
+	}
+
+	entry, err := s.waitlistRepo.Create(ctx, &models.WaitlistEntry{
+		RestaurantID:      restaurantID,
+		GuestName:         request.GuestName,
+		GuestPhone:        request.GuestPhone,
+		PartySize:         request.PartySize,
+		QuotedWaitMinutes: *quoted,
+		Status:            models.WaitlistStatusWaiting,
+		Token:             token,
+		Notes:             request.Notes,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetWaitlistEntryByID(ctx, entry.ID)
+}
+
+// NotifyWaitlistEntry records that the party was told its table is ready. Sending the message
+// is up to the host.
+func (s *Service) NotifyWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	return s.updateWaitlistStatus(ctx, id, models.WaitlistStatusNotified, func(tx *gorm.DB, entry *models.WaitlistEntry, now time.Time, columns map[string]interface{}) error {
+		columns["notified_at"] = now
+		return nil
+	})
+}
+
+// SeatWaitlistEntry seats the party at a table and marks the table occupied. The table must be
+// active, seat the party, and not be held by a reservation within the party's turn time.
+func (s *Service) SeatWaitlistEntry(ctx context.Context, id int, request *models.SeatWaitlistEntryRequest) (*models.WaitlistEntry, error) {
+	return s.updateWaitlistStatus(ctx, id, models.WaitlistStatusSeated, func(tx *gorm.DB, entry *models.WaitlistEntry, now time.Time, columns map[string]interface{}) error {
+		var table models.Table
+		err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).
+			First(&table, "id = ? AND restaurant_id = ?", request.TableID, entry.RestaurantID).Error
+		if err != nil {
+			return err
+		}
+
+		if table.Status != "active" {
+			return common.ErrTableNotAvailable
+		}
+		if table.Capacity < entry.PartySize {
+			return common.ErrInsufficientTableCapacity
+		}
+
+		end := now.Add(time.Duration(reservationDuration(nil, entry.PartySize))*time.Minute + reservationBuffer())
+		var reserved int64
+		if err := tx.Model(&models.ReservationTable{}).
+			Where("table_id = ? AND is_active = TRUE AND during && ?::tsrange", table.ID, tsRange(now, end)).
+			Count(&reserved).Error; err != nil {
+			return err
+		}
+		if reserved > 0 {
+			return common.ErrTableAlreadyBooked
+		}
+
+		if err := setTablesStatus(tx, []int{table.ID}, "occupied", now); err != nil {
+			return err
+		}
+
+		columns["table_id"] = table.ID
+		columns["seated_at"] = now
+		return nil
+	})
+}
+
+// RemoveWaitlistEntry takes a party off the queue, for example when it left
+func (s *Service) RemoveWaitlistEntry(ctx context.Context, id int) (*models.WaitlistEntry, error) {
+	return s.updateWaitlistStatus(ctx, id, models.WaitlistStatusRemoved, func(tx *gorm.DB, entry *models.WaitlistEntry, now time.Time, columns map[string]interface{}) error {
+		columns["removed_at"] = now
+		return nil
+	})
+}
+
+// GetWaitlistStatus is the guest's view of their entry, found by the token in the status link
+func (s *Service) GetWaitlistStatus(ctx context.Context, token string) (*models.WaitlistStatusResponse, error) {
+	entry, err := s.waitlistRepo.GetDetailByConditions(ctx, func(tx *gorm.DB) {
+		tx.Where("token = ?", token)
+	})
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrWaitlistEntryNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.attachWaitEstimates(ctx, entry.RestaurantID, []*models.WaitlistEntry{entry}); err != nil {
+		return nil, err
+	}
+
+	return &models.WaitlistStatusResponse{
+		GuestName:            entry.GuestName,
+		PartySize:            entry.PartySize,
+		Status:               entry.Status,
+		Position:             entry.Position,
+		QuotedWaitMinutes:    entry.QuotedWaitMinutes,
+		EstimatedWaitMinutes: entry.EstimatedWaitMinutes,
+		NotifiedAt:           entry.NotifiedAt,
+		CreatedAt:            entry.CreatedAt,
+	}, nil
+}
+
+// updateWaitlistStatus moves an entry to status under a row lock; apply adds the columns and
+// side effects of the move
+func (s *Service) updateWaitlistStatus(ctx context.Context, id int, status string, apply func(tx *gorm.DB, entry *models.WaitlistEntry, now time.Time, columns map[string]interface{}) error) (*models.WaitlistEntry, error) {
+	err := s.waitlistRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		entry, err := lockWaitlistEntry(tx, id)
+		if err != nil {
+			return err
+		}
+
+		if !slices.Contains(waitlistTransitions[entry.Status], status) {
+			return common.ErrInvalidWaitlistStatus
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{
+			"status":     status,
+			"updated_at": now,
+		}
+		if err := apply(tx, entry, now, columns); err != nil {
+			return err
+		}
+
+		return tx.Model(entry).Updates(columns).Error
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetWaitlistEntryByID(ctx, id)
+}
+
+func lockWaitlistEntry(tx *gorm.DB, id int) (*models.WaitlistEntry, error) {
+	var entry models.WaitlistEntry
+	err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&entry, "id = ?", id).Error
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrWaitlistEntryNotFound
+		}
+		return nil, err
+	}
+
+	return &entry, nil
+}
+
+// getWaitlistQueue lists the parties still waiting or notified, first come first
+func (s *Service) getWaitlistQueue(ctx context.Context, restaurantID int) ([]*models.WaitlistEntry, error) {
+	return s.waitlistRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "created_at.asc,id.asc"},
+	}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ? AND status IN ?", restaurantID, activeWaitlistStatuses)
+	})
+}
+
+// attachWaitEstimates sets the status link of each entry and, for entries still in the queue,
+// their position and current estimated wait
+func (s *Service) attachWaitEstimates(ctx context.Context, restaurantID int, entries []*models.WaitlistEntry) error {
+	active := false
+	for _, entry := range entries {
+		entry.StatusURL = waitlistStatusURL(entry.Token)
+		active = active || slices.Contains(activeWaitlistStatuses, entry.Status)
+	}
+	if !active {
+		return nil
+	}
+
+	queue, err := s.getWaitlistQueue(ctx, restaurantID)
+	if err != nil {
+		return err
+	}
+
+	partySizes := make([]int, 0, len(queue))
+	for _, entry := range queue {
+		partySizes = append(partySizes, entry.PartySize)
+	}
+	waits, err := s.estimateWaits(ctx, restaurantID, partySizes)
+	if err != nil {
+		return err
+	}
+
+	positions := make(map[int]int, len(queue))
+	for i, entry := range queue {
+		positions[entry.ID] = i
+	}
+	for _, entry := range entries {
+		i, ok := positions[entry.ID]
+		if !ok {
+			continue
+		}
+		position := i + 1
+		entry.Position = &position
+		if waits[i] != nil {
+			minutes := quoteMinutes(*waits[i])
+			entry.EstimatedWaitMinutes = &minutes
+		}
+	}
+
+	return nil
+}
+
+// estimateWaits plays the queue forward: each party in turn takes the table that seats it and
+// frees up first, and that table frees up again one dining time (plus the reset buffer) later.
+// An occupied table frees up one dining time after its oldest open order, and no table is
+// handed out over one of its reservations. A party no single table seats waits for tables of one
+// zone to be pushed together, as availability combines them; if none do, it gets no estimate.
+func (s *Service) estimateWaits(ctx context.Context, restaurantID int, partySizes []int) ([]*time.Duration, error) {
+	waits := make([]*time.Duration, len(partySizes))
+	if len(partySizes) == 0 {
+		return waits, nil
+	}
+
+	tables, err := s.tableRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "capacity.asc,id.asc"},
+	}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ? AND status <> ?", restaurantID, "inactive").Where(activeZoneCondition)
+	})
+	if err != nil {
+		return nil, err
+	}
+	if len(tables) == 0 {
+		return waits, nil
+	}
+
+	dining, err := s.averageDiningDuration(ctx, restaurantID)
+	if err != nil {
+		return nil, err
+	}
+	diningTime := func(partySize int) time.Duration {
+		if dining > 0 {
+			return dining
+		}
+		return time.Duration(reservationDuration(nil, partySize)) * time.Minute
+	}
+
+	now := time.Now()
+	reserved, err := s.getReservedRanges(ctx, tables, now, now.Add(upcomingReservationWindow()))
+	if err != nil {
+		return nil, err
+	}
+	occupiedSince, err := s.getOpenOrderSince(ctx, tables)
+	if err != nil {
+		return nil, err
+	}
+
+	waitTables := make([]*waitTable, 0, len(tables))
+	for _, table := range tables {
+		item := &waitTable{capacity: table.Capacity, freeAt: now, reserved: reserved[table.ID]}
+		if table.ZoneID != nil {
+			item.zoneID = *table.ZoneID
+		}
+		sort.Slice(item.reserved, func(i, j int) bool { return item.reserved[i].start.Before(item.reserved[j].start) })
+
+		if table.Status == "occupied" {
+			// without open orders, the guests are taken to have sat down when the table was flipped
+			since, ok := occupiedSince[table.ID]
+			if !ok && table.UpdatedAt != nil {
+				since, ok = fromTimestamp(*table.UpdatedAt), true
+			}
+			if !ok {
+				since = now
+			}
+			item.freeAt = since.Add(diningTime(table.Capacity))
+			if item.freeAt.Before(now) {
+				item.freeAt = now
+			}
+			item.freeAt = item.freeAt.Add(reservationBuffer())
+		}
+		waitTables = append(waitTables, item)
+	}
+
+	for i, partySize := range partySizes {
+		length := diningTime(partySize)
+
+		var best []*waitTable
+		var bestStart time.Time
+		for _, table := range waitTables {
+			if table.capacity < partySize {
+				continue
+			}
+			start := table.seatingStart(now, length)
+			if best == nil || start.Before(bestStart) {
+				best, bestStart = []*waitTable{table}, start
+			}
+		}
+		if best == nil {
+			best, bestStart = combinedSeating(waitTables, partySize, length, now)
+		}
+		if best == nil {
+			continue
+		}
+
+		for _, table := range best {
+			table.freeAt = bestStart.Add(length + reservationBuffer())
+		}
+		wait := bestStart.Sub(now)
+		waits[i] = &wait
+	}
+
+	return waits, nil
+}
+
+// combinedSeating finds the tables of one zone, up to maxCombinedTables, that together seat the
+// party and are first all free for length. tables must be sorted by capacity.
+func combinedSeating(tables []*waitTable, partySize int, length time.Duration, now time.Time) ([]*waitTable, time.Time) {
+	byZone := make(map[int][]*waitTable)
+	var zoneIDs []int
+	for _, table := range tables {
+		if _, ok := byZone[table.zoneID]; !ok {
+			zoneIDs = append(zoneIDs, table.zoneID)
+		}
+		byZone[table.zoneID] = append(byZone[table.zoneID], table)
+	}
+	slices.Sort(zoneIDs)
+
+	var best []*waitTable
+	var bestStart time.Time
+	for _, zoneID := range zoneIDs {
+		group := byZone[zoneID]
+		// prefix[i] is the capacity of the first i tables
+		prefix := make([]int, len(group)+1)
+		for i, table := range group {
+			prefix[i+1] = prefix[i] + table.capacity
+		}
+		n := len(group)
+
+		for size := 2; size <= maxCombinedTables(); size++ {
+			var walk func(from int, picked []*waitTable, capacity int)
+			walk = func(from int, picked []*waitTable, capacity int) {
+				left := size - len(picked)
+				if left == 0 {
+					if capacity < partySize {
+						return
+					}
+					if start := groupSeatingStart(picked, length, now); best == nil || start.Before(bestStart) {
+						best, bestStart = slices.Clone(picked), start
+					}
+					return
+				}
+				// even the largest tables left cannot seat the party
+				if n-from < left || capacity+prefix[n]-prefix[n-left] < partySize {
+					return
+				}
+				for i := from; i <= n-left; i++ {
+					walk(i+1, append(picked, group[i]), capacity+group[i].capacity)
+				}
+			}
+			walk(0, make([]*waitTable, 0, size), 0)
+		}
+	}
+
+	return best, bestStart
+}
+
+// groupSeatingStart is the first time from now all the tables are free together for length
+func groupSeatingStart(tables []*waitTable, length time.Duration, now time.Time) time.Time {
+	start := now
+	for moved := true; moved; {
+		moved = false
+		for _, table := range tables {
+			if next := table.seatingStart(start, length); next.After(start) {
+				start, moved = next, true
+			}
+		}
+	}
+	return start
+}
+
+// seatingStart is the first time from freeAt, and not before from, the table is free for length,
+// buffer included
+func (t *waitTable) seatingStart(from time.Time, length time.Duration) time.Time {
+	start := t.freeAt
+	if start.Before(from) {
+		start = from
+	}
+	for moved := true; moved; {
+		moved = false
+		for _, r := range t.reserved {
+			if r.overlaps(start, start.Add(length+reservationBuffer())) {
+				start, moved = r.end, true
+			}
+		}
+	}
+	return start
+}
+
+// averageDiningDuration is how long orders completed in the history window took. It is zero
+// when there are too few of them to go by, and turn times are used instead.
+func (s *Service) averageDiningDuration(ctx context.Context, restaurantID int) (time.Duration, error) {
+	days := config.Config.Waitlist.HistoryDays
+	if days <= 0 {
+		days = defaultWaitlistHistoryDays
+	}
+	minOrders := config.Config.Waitlist.MinHistoryOrders
+	if minOrders <= 0 {
+		minOrders = defaultWaitlistMinHistoryOrders
+	}
+
+	var result struct {
+		Orders  int
+		Seconds float64
+	}
+	err := s.waitlistRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT COUNT(*) AS orders, COALESCE(AVG(EXTRACT(EPOCH FROM o.completed_at - o.created_at)), 0) AS seconds
+		FROM orders o
+		JOIN tables t ON t.id = o.table_id
+		WHERE t.restaurant_id = ? AND o.completed_at IS NOT NULL AND o.completed_at >= ?
+	`, restaurantID, time.Now().AddDate(0, 0, -days)).Scan(&result).Error
+	if err != nil {
+		return 0, err
+	}
+
+	if result.Orders < minOrders {
+		return 0, nil
+	}
+	return time.Duration(result.Seconds * float64(time.Second)), nil
+}
+
+// getOpenOrderSince is the creation time of the oldest open order on each table
+func (s *Service) getOpenOrderSince(ctx context.Context, tables []*models.Table) (map[int]time.Time, error) {
+	tableIDs := make([]int, 0, len(tables))
+	for _, table := range tables {
+		tableIDs = append(tableIDs, table.ID)
+	}
+
+	var rows []struct {
+		TableID int
+		Since   time.Time
+	}
+	err := s.waitlistRepo.GetDB().WithContext(ctx).Raw(`
+		SELECT table_id, MIN(created_at) AS since
+		FROM orders
+		WHERE table_id IN ? AND status IN ('pending', 'processing')
+		GROUP BY table_id
+	`, tableIDs).Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	since := make(map[int]time.Time, len(rows))
+	for _, row := range rows {
+		since[row.TableID] = fromTimestamp(row.Since)
+	}
+	return since, nil
+}
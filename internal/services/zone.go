@@ -0,0 +1,227 @@
+package services
+
+import (
+	"app-noti/common"
+	"app-noti/internal/models"
+	"context"
+	"errors"
+	"strings"
+	"time"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+const (
+	// zoneNameCondition matches tables in the zone with the given name, in any case
+	zoneNameCondition = "zone_id IN (SELECT id FROM zones WHERE LOWER(name) = LOWER(?))"
+	// activeZoneCondition keeps tables that are in no zone or in an active one
+	activeZoneCondition = "(zone_id IS NULL OR zone_id IN (SELECT id FROM zones WHERE is_active = TRUE))"
+)
+
+func (s *Service) GetZones(ctx context.Context, request *models.ListZonesRequest) ([]*models.Zone, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	zones, err := s.zoneRepo.List(ctx, models.QueryParams{
+		QuerySort: models.QuerySort{Origin: "display_order.asc,id.asc"},
+	}, func(tx *gorm.DB) {
+		tx.Where("restaurant_id = ?", restaurantID)
+		if !request.IncludeInactive {
+			tx.Where("is_active = ?", true)
+		}
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	if err := s.attachZoneTableCounts(ctx, zones); err != nil {
+		return nil, err
+	}
+
+	return zones, nil
+}
+
+func (s *Service) GetZoneByID(ctx context.Context, id int) (*models.Zone, error) {
+	zone, err := s.zoneRepo.GetByID(ctx, id)
+	if err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrZoneNotFound
+		}
+		return nil, err
+	}
+
+	if err := s.attachZoneTableCounts(ctx, []*models.Zone{zone}); err != nil {
+		return nil, err
+	}
+
+	return zone, nil
+}
+
+func (s *Service) CreateZone(ctx context.Context, request *models.CreateZoneRequest) (*models.Zone, error) {
+	restaurantID := 1
+	if request.RestaurantID != nil {
+		restaurantID = *request.RestaurantID
+	}
+
+	zone := &models.Zone{
+		RestaurantID:         restaurantID,
+		Name:                 strings.TrimSpace(request.Name),
+		IsActive:             true,
+		ServiceChargePercent: zoneRule(request.ServiceChargePercent),
+		MinimumSpend:         zoneRule(request.MinimumSpend),
+	}
+	if request.DisplayOrder != nil {
+		zone.DisplayOrder = *request.DisplayOrder
+	}
+	if request.IsActive != nil {
+		zone.IsActive = *request.IsActive
+	}
+
+	created, err := s.zoneRepo.Create(ctx, zone)
+	if err != nil {
+		if strings.Contains(err.Error(), "zones_restaurant_name_key") {
+			return nil, common.ErrZoneAlreadyExists
+		}
+		return nil, err
+	}
+
+	return s.GetZoneByID(ctx, created.ID)
+}
+
+// UpdateZone changes a zone. A new name is copied to the location of its tables in the same
+// transaction, so nothing else has to be edited.
+func (s *Service) UpdateZone(ctx context.Context, id int, request *models.UpdateZoneRequest) (*models.Zone, error) {
+	err := s.zoneRepo.GetDB().WithContext(ctx).Transaction(func(tx *gorm.DB) error {
+		var zone models.Zone
+		if err := tx.Clauses(clause.Locking{Strength: "UPDATE"}).First(&zone, "id = ?", id).Error; err != nil {
+			if errors.Is(err, gorm.ErrRecordNotFound) {
+				return common.ErrZoneNotFound
+			}
+			return err
+		}
+
+		now := time.Now()
+		columns := map[string]interface{}{
+			"updated_at": now,
+		}
+		if request.Name != nil {
+			columns["name"] = strings.TrimSpace(*request.Name)
+		}
+		if request.DisplayOrder != nil {
+			columns["display_order"] = *request.DisplayOrder
+		}
+		if request.IsActive != nil {
+			columns["is_active"] = *request.IsActive
+		}
+		if request.ServiceChargePercent != nil {
+			columns["service_charge_percent"] = zoneRule(request.ServiceChargePercent)
+		}
+		if request.MinimumSpend != nil {
+			columns["minimum_spend"] = zoneRule(request.MinimumSpend)
+		}
+
+		if err := tx.Model(&zone).Updates(columns).Error; err != nil {
+			if strings.Contains(err.Error(), "zones_restaurant_name_key") {
+				return common.ErrZoneAlreadyExists
+			}
+			return err
+		}
+
+		if name, ok := columns["name"]; ok {
+			return tx.Model(&models.Table{}).
+				Where("zone_id = ?", zone.ID).
+				Updates(map[string]interface{}{"location": name, "updated_at": now}).Error
+		}
+
+		return nil
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return s.GetZoneByID(ctx, id)
+}
+
+// DeleteZone removes a zone without tables, along with its areas on floor plans
+func (s *Service) DeleteZone(ctx context.Context, id int) error {
+	if _, err := s.GetZoneByID(ctx, id); err != nil {
+		return err
+	}
+
+	tables, err := s.tableRepo.Count(ctx, models.QueryParams{}, func(tx *gorm.DB) {
+		tx.Where("zone_id = ?", id)
+	})
+	if err != nil {
+		return err
+	}
+	if tables > 0 {
+		return common.ErrZoneInUse
+	}
+
+	return s.zoneRepo.Delete(ctx, func(tx *gorm.DB) {
+		tx.Where("id = ?", id)
+	})
+}
+
+// resolveZone finds the zone a table goes in: by id, or else by name in any case within the
+// restaurant
+func resolveZone(tx *gorm.DB, restaurantID int, zoneID *int, name *string) (*models.Zone, error) {
+	var zone models.Zone
+	query := tx.Model(&models.Zone{})
+	if zoneID != nil {
+		query = query.Where("id = ?", *zoneID)
+	} else {
+		query = query.Where("restaurant_id = ? AND LOWER(name) = LOWER(?)", restaurantID, strings.TrimSpace(*name))
+	}
+
+	if err := query.First(&zone).Error; err != nil {
+		if errors.Is(err, gorm.ErrRecordNotFound) {
+			return nil, common.ErrZoneNotFound
+		}
+		return nil, err
+	}
+
+	return &zone, nil
+}
+
+func sameZone(a, b *int) bool {
+	if a == nil || b == nil {
+		return a == b
+	}
+	return *a == *b
+}
+
+// zoneRule stores 0 as no rule
+func zoneRule(value *float64) *float64 {
+	if value == nil || *value == 0 {
+		return nil
+	}
+	return value
+}
+
+func (s *Service) attachZoneTableCounts(ctx context.Context, zones []*models.Zone) error {
+	if len(zones) == 0 {
+		return nil
+	}
+
+	ids := make([]int, 0, len(zones))
+	for _, zone := range zones {
+		ids = append(ids, zone.ID)
+	}
+
+	counts, err := s.tableRepo.CountGroupByInt(ctx, "zone_id", func(tx *gorm.DB) {
+		tx.Where("zone_id IN ?", ids)
+	})
+	if err != nil {
+		return err
+	}
+
+	for _, zone := range zones {
+		zone.TableCount = counts[zone.ID]
+	}
+
+	return nil
+}
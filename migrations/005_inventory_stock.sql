@@ -0,0 +1,41 @@
+-- =====================================================
+-- STOCK TRACKING FOR MENU ITEMS AND MODIFIER OPTIONS
+-- =====================================================
+
+-- NULL stock_quantity means the item is not stock tracked
+ALTER TABLE "public"."menu_items"
+ADD COLUMN "stock_quantity" INT CHECK (stock_quantity >= 0),
+ADD COLUMN "low_stock_threshold" INT CHECK (low_stock_threshold >= 0);
+
+ALTER TABLE "public"."modifier_options"
+ADD COLUMN "stock_quantity" INT CHECK (stock_quantity >= 0),
+ADD COLUMN "low_stock_threshold" INT CHECK (low_stock_threshold >= 0);
+
+-- Options can now be sold out, the same way menu items are
+ALTER TABLE "public"."modifier_options" DROP CONSTRAINT IF EXISTS "modifier_options_status_check";
+ALTER TABLE "public"."modifier_options"
+ADD CONSTRAINT "modifier_options_status_check" CHECK (status IN ('active', 'inactive', 'sold_out'));
+
+-- =====================================================
+-- STOCK ALERTS
+-- =====================================================
+
+CREATE TABLE stock_alerts (
+    id SERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('menu_item', 'modifier_option')),
+    entity_id INT NOT NULL,
+    alert_type VARCHAR(20) NOT NULL CHECK (alert_type IN ('low_stock', 'out_of_stock')),
+    stock_quantity INT NOT NULL,
+    low_stock_threshold INT,
+    is_resolved BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    resolved_at TIMESTAMP
+);
+
+CREATE INDEX idx_stock_alerts_entity ON public.stock_alerts(entity_type, entity_id);
+CREATE INDEX idx_stock_alerts_unresolved ON public.stock_alerts(is_resolved) WHERE is_resolved = FALSE;
+
+-- Orders remember whether stock has been taken for them, so cancellation only restores what was consumed
+ALTER TABLE "public"."orders"
+ADD COLUMN "stock_consumed" BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN "cancelled_at" timestamp;
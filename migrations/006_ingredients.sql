@@ -0,0 +1,56 @@
+-- =====================================================
+-- INGREDIENTS, RECIPES AND STOCK MOVEMENTS
+-- =====================================================
+
+CREATE TABLE ingredients (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    name VARCHAR(80) NOT NULL,
+    unit VARCHAR(10) NOT NULL CHECK (unit IN ('g', 'kg', 'ml', 'l', 'pcs')),
+    stock_quantity DECIMAL(12,3) NOT NULL DEFAULT 0 CHECK (stock_quantity >= 0),
+    low_stock_threshold DECIMAL(12,3) CHECK (low_stock_threshold >= 0),
+    status VARCHAR(20) DEFAULT 'active' CHECK (status IN ('active', 'inactive')),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    updated_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    UNIQUE (restaurant_id, name)
+);
+
+-- One recipe line says how much of an ingredient one portion of an item (or one option) uses
+CREATE TABLE recipe_lines (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT NOT NULL,
+    menu_item_id INT,
+    modifier_option_id INT,
+    quantity DECIMAL(12,3) NOT NULL CHECK (quantity > 0),
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP,
+    CHECK ((menu_item_id IS NULL) <> (modifier_option_id IS NULL)),
+    UNIQUE (menu_item_id, ingredient_id),
+    UNIQUE (modifier_option_id, ingredient_id)
+);
+
+CREATE INDEX idx_recipe_lines_ingredient ON public.recipe_lines(ingredient_id);
+
+-- quantity is signed: negative takes stock out, positive puts it back
+CREATE TABLE stock_movements (
+    id SERIAL PRIMARY KEY,
+    ingredient_id INT NOT NULL,
+    movement_type VARCHAR(20) NOT NULL CHECK (movement_type IN ('order_consumption', 'order_restore', 'restock', 'adjustment', 'waste')),
+    quantity DECIMAL(12,3) NOT NULL,
+    stock_after DECIMAL(12,3) NOT NULL,
+    order_id INT,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT CURRENT_TIMESTAMP
+);
+
+CREATE INDEX idx_stock_movements_ingredient ON public.stock_movements(ingredient_id, created_at);
+
+ALTER TABLE "public"."ingredients"
+ADD CONSTRAINT "ingredients_restaurant_id_fkey" FOREIGN KEY ("restaurant_id") REFERENCES "public"."restaurants"("id") ON DELETE CASCADE;
+
+ALTER TABLE "public"."recipe_lines"
+ADD CONSTRAINT "recipe_lines_ingredient_id_fkey" FOREIGN KEY ("ingredient_id") REFERENCES "public"."ingredients"("id") ON DELETE CASCADE,
+ADD CONSTRAINT "recipe_lines_menu_item_id_fkey" FOREIGN KEY ("menu_item_id") REFERENCES "public"."menu_items"("id") ON DELETE CASCADE,
+ADD CONSTRAINT "recipe_lines_modifier_option_id_fkey" FOREIGN KEY ("modifier_option_id") REFERENCES "public"."modifier_options"("id") ON DELETE CASCADE;
+
+ALTER TABLE "public"."stock_movements"
+ADD CONSTRAINT "stock_movements_ingredient_id_fkey" FOREIGN KEY ("ingredient_id") REFERENCES "public"."ingredients"("id") ON DELETE CASCADE;
@@ -0,0 +1,33 @@
+-- =====================================================
+-- ALLERGENS, DIETARY TAGS AND NUTRITION
+-- =====================================================
+
+-- Allergens follow the 14 allergens of EU regulation 1169/2011
+ALTER TABLE "public"."menu_items"
+ADD COLUMN "allergens" TEXT[] NOT NULL DEFAULT '{}' CHECK (allergens <@ ARRAY[
+    'gluten', 'crustaceans', 'eggs', 'fish', 'peanuts', 'soybeans', 'milk',
+    'nuts', 'celery', 'mustard', 'sesame', 'sulphites', 'lupin', 'molluscs'
+]::TEXT[]),
+ADD COLUMN "dietary_tags" TEXT[] NOT NULL DEFAULT '{}' CHECK (dietary_tags <@ ARRAY[
+    'vegan', 'vegetarian', 'halal', 'gluten_free'
+]::TEXT[]),
+ADD COLUMN "spicy_level" SMALLINT CHECK (spicy_level BETWEEN 0 AND 5),
+ADD COLUMN "nutrition" JSONB;
+
+ALTER TABLE "public"."modifier_options"
+ADD COLUMN "allergens" TEXT[] NOT NULL DEFAULT '{}' CHECK (allergens <@ ARRAY[
+    'gluten', 'crustaceans', 'eggs', 'fish', 'peanuts', 'soybeans', 'milk',
+    'nuts', 'celery', 'mustard', 'sesame', 'sulphites', 'lupin', 'molluscs'
+]::TEXT[]),
+ADD COLUMN "dietary_tags" TEXT[] NOT NULL DEFAULT '{}' CHECK (dietary_tags <@ ARRAY[
+    'vegan', 'vegetarian', 'halal', 'gluten_free'
+]::TEXT[]),
+ADD COLUMN "spicy_level" SMALLINT CHECK (spicy_level BETWEEN 0 AND 5),
+ADD COLUMN "nutrition" JSONB;
+
+-- =====================================================
+-- INDEXES
+-- =====================================================
+
+CREATE INDEX idx_menu_items_allergens ON menu_items USING GIN (allergens);
+CREATE INDEX idx_menu_items_dietary_tags ON menu_items USING GIN (dietary_tags);
@@ -0,0 +1,18 @@
+-- =====================================================
+-- MENU TRANSLATIONS
+-- =====================================================
+
+-- Base tables hold the default language (en). One row per entity and extra locale.
+CREATE TABLE menu_translations (
+    id SERIAL PRIMARY KEY,
+    entity_type VARCHAR(20) NOT NULL CHECK (entity_type IN ('category', 'menu_item', 'modifier_group', 'modifier_option')),
+    entity_id INT NOT NULL,
+    locale VARCHAR(10) NOT NULL,
+    name VARCHAR(120) NOT NULL,
+    description TEXT,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (entity_type, entity_id, locale)
+);
+
+CREATE INDEX idx_menu_translations_locale ON menu_translations(locale, entity_type);
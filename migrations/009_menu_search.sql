@@ -0,0 +1,32 @@
+-- =====================================================
+-- FULL TEXT AND FUZZY MENU SEARCH
+-- =====================================================
+
+CREATE EXTENSION IF NOT EXISTS unaccent;
+CREATE EXTENSION IF NOT EXISTS pg_trgm;
+
+-- unaccent() is only STABLE, this wrapper pins the dictionary so it can be used in indexes
+CREATE OR REPLACE FUNCTION public.f_unaccent(text) RETURNS text AS $$
+    SELECT public.unaccent('public.unaccent'::regdictionary, $1)
+$$ LANGUAGE sql IMMUTABLE PARALLEL SAFE STRICT;
+
+-- "simple" parsing with accents stripped, so "pho" matches "phở" and highlights keep the original text
+CREATE TEXT SEARCH CONFIGURATION public.menu_search (COPY = pg_catalog.simple);
+ALTER TEXT SEARCH CONFIGURATION public.menu_search
+    ALTER MAPPING FOR hword, hword_part, word WITH public.unaccent, pg_catalog.simple;
+
+-- Names rank above descriptions
+ALTER TABLE "public"."menu_items"
+ADD COLUMN "search_vector" TSVECTOR GENERATED ALWAYS AS (
+    setweight(to_tsvector('public.menu_search', coalesce(name, '')), 'A') ||
+    setweight(to_tsvector('public.menu_search', coalesce(description, '')), 'B')
+) STORED;
+
+-- =====================================================
+-- INDEXES
+-- =====================================================
+
+CREATE INDEX idx_menu_items_search_vector ON menu_items USING GIN (search_vector);
+CREATE INDEX idx_menu_items_name_trgm ON menu_items USING GIN (public.f_unaccent(lower(name)) gin_trgm_ops);
+CREATE INDEX idx_menu_categories_name_trgm ON menu_categories USING GIN (public.f_unaccent(lower(name)) gin_trgm_ops);
+CREATE INDEX idx_menu_translations_name_trgm ON menu_translations USING GIN (public.f_unaccent(lower(name)) gin_trgm_ops);
@@ -0,0 +1,70 @@
+-- =====================================================
+-- COMBOS / SET MENUS
+-- =====================================================
+
+CREATE TABLE combos (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    category_id INT,
+    name VARCHAR(80) NOT NULL,
+    description TEXT,
+    price DECIMAL(12,2) NOT NULL CHECK (price > 0),
+    image_url TEXT,
+    status VARCHAR(20) NOT NULL DEFAULT 'available' CHECK (status IN ('available', 'unavailable')),
+    display_order INT DEFAULT 0,
+    is_deleted BOOLEAN DEFAULT FALSE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- A slot is one pick in the combo ("Main", "Side", "Drink")
+CREATE TABLE combo_slots (
+    id SERIAL PRIMARY KEY,
+    combo_id INT NOT NULL,
+    name VARCHAR(80) NOT NULL,
+    min_choices INT NOT NULL DEFAULT 1 CHECK (min_choices >= 0),
+    max_choices INT NOT NULL DEFAULT 1 CHECK (max_choices >= 1),
+    display_order INT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CHECK (max_choices >= min_choices)
+);
+
+CREATE TABLE combo_slot_choices (
+    id SERIAL PRIMARY KEY,
+    slot_id INT NOT NULL,
+    menu_item_id INT NOT NULL,
+    upcharge DECIMAL(12,2) NOT NULL DEFAULT 0 CHECK (upcharge >= 0),
+    is_default BOOLEAN DEFAULT FALSE,
+    display_order INT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (slot_id, menu_item_id)
+);
+
+-- Combos can be translated like the rest of the menu
+ALTER TABLE "public"."menu_translations" DROP CONSTRAINT IF EXISTS "menu_translations_entity_type_check";
+ALTER TABLE "public"."menu_translations"
+ADD CONSTRAINT "menu_translations_entity_type_check" CHECK (entity_type IN ('category', 'menu_item', 'modifier_group', 'modifier_option', 'combo'));
+
+-- =====================================================
+-- FOREIGN KEYS
+-- =====================================================
+
+ALTER TABLE "public"."combos"
+ADD CONSTRAINT "combos_restaurant_id_fkey" FOREIGN KEY ("restaurant_id") REFERENCES "public"."restaurants"("id") ON DELETE CASCADE,
+ADD CONSTRAINT "combos_category_id_fkey" FOREIGN KEY ("category_id") REFERENCES "public"."menu_categories"("id") ON DELETE SET NULL;
+
+ALTER TABLE "public"."combo_slots"
+ADD CONSTRAINT "combo_slots_combo_id_fkey" FOREIGN KEY ("combo_id") REFERENCES "public"."combos"("id") ON DELETE CASCADE;
+
+ALTER TABLE "public"."combo_slot_choices"
+ADD CONSTRAINT "combo_slot_choices_slot_id_fkey" FOREIGN KEY ("slot_id") REFERENCES "public"."combo_slots"("id") ON DELETE CASCADE,
+ADD CONSTRAINT "combo_slot_choices_menu_item_id_fkey" FOREIGN KEY ("menu_item_id") REFERENCES "public"."menu_items"("id") ON DELETE CASCADE;
+
+-- =====================================================
+-- INDEXES
+-- =====================================================
+
+CREATE INDEX idx_combos_restaurant ON combos(restaurant_id) WHERE is_deleted = FALSE;
+CREATE INDEX idx_combo_slots_combo ON combo_slots(combo_id);
+CREATE INDEX idx_combo_slot_choices_slot ON combo_slot_choices(slot_id);
+CREATE INDEX idx_combo_slot_choices_menu_item ON combo_slot_choices(menu_item_id);
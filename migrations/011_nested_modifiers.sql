@@ -0,0 +1,29 @@
+-- =====================================================
+-- NESTED MODIFIERS
+-- =====================================================
+
+-- Picking the option opens the linked group ("Add side" -> "Which side?").
+-- Cycles and the maximum depth (menu.modifier_max_depth) are checked by the API.
+CREATE TABLE modifier_option_child_groups (
+    id SERIAL PRIMARY KEY,
+    option_id INT NOT NULL,
+    group_id INT NOT NULL,
+    display_order INT DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    UNIQUE (option_id, group_id)
+);
+
+-- =====================================================
+-- FOREIGN KEYS
+-- =====================================================
+
+ALTER TABLE "public"."modifier_option_child_groups"
+ADD CONSTRAINT "modifier_option_child_groups_option_id_fkey" FOREIGN KEY ("option_id") REFERENCES "public"."modifier_options"("id") ON DELETE CASCADE,
+ADD CONSTRAINT "modifier_option_child_groups_group_id_fkey" FOREIGN KEY ("group_id") REFERENCES "public"."modifier_groups"("id") ON DELETE CASCADE;
+
+-- =====================================================
+-- INDEXES
+-- =====================================================
+
+CREATE INDEX idx_modifier_option_child_groups_option ON modifier_option_child_groups(option_id);
+CREATE INDEX idx_modifier_option_child_groups_group ON modifier_option_child_groups(group_id);
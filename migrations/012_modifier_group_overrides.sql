@@ -0,0 +1,20 @@
+-- =====================================================
+-- PER-ITEM MODIFIER GROUP OVERRIDES
+-- =====================================================
+
+-- A shared group ("Size") can behave differently on each item it is assigned to.
+-- NULL means the group's own value is used.
+ALTER TABLE menu_item_modifier_groups
+ADD COLUMN display_order INT,
+ADD COLUMN min_selections INT CHECK (min_selections >= 0),
+ADD COLUMN max_selections INT CHECK (max_selections >= 0),
+-- Options of the group that are not offered on this item
+ADD COLUMN hidden_option_ids INTEGER[] NOT NULL DEFAULT '{}',
+-- Price adjustment per option on this item: {"<option_id>": 1.5}
+ADD COLUMN price_overrides JSONB NOT NULL DEFAULT '{}';
+
+-- =====================================================
+-- INDEXES
+-- =====================================================
+
+CREATE INDEX idx_menu_item_modifier_groups_item_order ON menu_item_modifier_groups(menu_item_id, display_order);
@@ -0,0 +1,34 @@
+-- =====================================================
+-- DISPLAY ORDER FOR ITEMS AND OPTIONS
+-- =====================================================
+
+-- Items are ordered within their category, options within their group (1 = first).
+ALTER TABLE menu_items
+ADD COLUMN display_order INT NOT NULL DEFAULT 0;
+
+ALTER TABLE modifier_options
+ADD COLUMN display_order INT NOT NULL DEFAULT 0;
+
+-- Keep the current order (by id) as the starting point
+UPDATE menu_items m
+SET display_order = r.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY category_id ORDER BY id) AS position
+    FROM menu_items
+) r
+WHERE m.id = r.id;
+
+UPDATE modifier_options o
+SET display_order = r.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY group_id ORDER BY id) AS position
+    FROM modifier_options
+) r
+WHERE o.id = r.id;
+
+-- =====================================================
+-- INDEXES
+-- =====================================================
+
+CREATE INDEX idx_menu_items_category_order ON menu_items(category_id, display_order);
+CREATE INDEX idx_modifier_options_group_order ON modifier_options(group_id, display_order);
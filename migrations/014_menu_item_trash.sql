@@ -0,0 +1,11 @@
+-- Trash for soft-deleted menu items: deleted_at drives the purge after the retention period
+
+ALTER TABLE "public"."menu_items"
+ADD COLUMN "deleted_at" TIMESTAMP;
+
+-- items deleted before this migration start their retention now
+UPDATE "public"."menu_items"
+SET deleted_at = NOW()
+WHERE is_deleted = TRUE;
+
+CREATE INDEX idx_menu_items_deleted_at ON menu_items(deleted_at) WHERE is_deleted = TRUE;
@@ -0,0 +1,9 @@
+-- Soft delete for menu categories and modifier groups
+
+ALTER TABLE "public"."menu_categories"
+ADD COLUMN "is_deleted" BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN "deleted_at" TIMESTAMP;
+
+ALTER TABLE "public"."modifier_groups"
+ADD COLUMN "is_deleted" BOOLEAN NOT NULL DEFAULT FALSE,
+ADD COLUMN "deleted_at" TIMESTAMP;
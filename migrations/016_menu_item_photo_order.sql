@@ -0,0 +1,38 @@
+-- Photo order and a single primary photo per menu item
+
+ALTER TABLE "public"."menu_item_photos"
+ADD COLUMN "display_order" INT NOT NULL DEFAULT 0;
+
+UPDATE menu_item_photos p
+SET display_order = o.position
+FROM (
+    SELECT id, ROW_NUMBER() OVER (PARTITION BY menu_item_id ORDER BY is_primary DESC, id) AS position
+    FROM menu_item_photos
+) o
+WHERE p.id = o.id;
+
+-- keep the first primary photo of each item
+UPDATE menu_item_photos p
+SET is_primary = FALSE
+WHERE p.is_primary = TRUE
+  AND EXISTS (
+      SELECT 1 FROM menu_item_photos q
+      WHERE q.menu_item_id = p.menu_item_id AND q.is_primary = TRUE AND q.id < p.id
+  );
+
+-- items with photos but no primary get their first photo
+UPDATE menu_item_photos p
+SET is_primary = TRUE
+WHERE p.display_order = 1
+  AND NOT EXISTS (
+      SELECT 1 FROM menu_item_photos q
+      WHERE q.menu_item_id = p.menu_item_id AND q.is_primary = TRUE
+  );
+
+UPDATE menu_item_photos SET is_primary = FALSE WHERE is_primary IS NULL;
+
+ALTER TABLE "public"."menu_item_photos"
+ALTER COLUMN "is_primary" SET NOT NULL;
+
+CREATE UNIQUE INDEX uniq_menu_item_photos_primary ON menu_item_photos(menu_item_id) WHERE is_primary = TRUE;
+CREATE INDEX idx_menu_item_photos_order ON menu_item_photos(menu_item_id, display_order);
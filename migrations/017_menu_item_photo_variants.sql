@@ -0,0 +1,4 @@
+-- Resized variants of uploaded photos, e.g. {"thumb": "...", "card": "...", "full": "..."}
+
+ALTER TABLE "public"."menu_item_photos"
+ADD COLUMN "variants" JSONB;
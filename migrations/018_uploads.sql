@@ -0,0 +1,24 @@
+-- =====================================================
+-- UPLOADS
+-- Every stored photo upload, so uploads never attached to a menu item can be cleaned up
+-- =====================================================
+
+CREATE TABLE uploads (
+    id SERIAL PRIMARY KEY,
+    object_key VARCHAR(512) NOT NULL UNIQUE,
+    content_type VARCHAR(100) NOT NULL,
+    size_bytes BIGINT NOT NULL CHECK (size_bytes > 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'pending' CHECK (status IN ('pending', 'confirmed')),
+    -- the url to attach to an item: the object itself, or its full variant once processed
+    url TEXT NOT NULL,
+    variants JSONB,
+    expires_at TIMESTAMP,
+    confirmed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_uploads_created_at ON uploads(created_at);
+
+-- the cleanup looks photos up by url
+CREATE INDEX idx_menu_item_photos_url ON menu_item_photos(url);
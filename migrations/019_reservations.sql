@@ -0,0 +1,40 @@
+-- =====================================================
+-- RESERVATIONS
+-- =====================================================
+
+-- lets the exclusion constraint mix = on table_id with && on the time range
+CREATE EXTENSION IF NOT EXISTS btree_gist;
+
+CREATE TABLE reservations (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    guest_name VARCHAR(100) NOT NULL,
+    guest_phone VARCHAR(20) NOT NULL,
+    party_size INT NOT NULL CHECK (party_size > 0),
+    starts_at TIMESTAMP NOT NULL,
+    duration_minutes INT NOT NULL CHECK (duration_minutes > 0),
+    ends_at TIMESTAMP GENERATED ALWAYS AS (starts_at + duration_minutes * INTERVAL '1 minute') STORED,
+    status VARCHAR(20) NOT NULL DEFAULT 'booked' CHECK (status IN ('booked', 'seated', 'completed', 'no_show', 'cancelled')),
+    notes TEXT,
+    seated_at TIMESTAMP,
+    completed_at TIMESTAMP,
+    cancelled_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_reservations_restaurant_starts ON reservations(restaurant_id, starts_at);
+
+-- The tables a reservation holds. A booked or seated reservation holds its tables for its time
+-- range; the exclusion constraint refuses a second booking of a table for an overlapping range.
+CREATE TABLE reservation_tables (
+    id SERIAL PRIMARY KEY,
+    reservation_id INT NOT NULL REFERENCES reservations(id) ON DELETE CASCADE,
+    table_id INT NOT NULL REFERENCES tables(id),
+    during TSRANGE NOT NULL,
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW(),
+    CONSTRAINT reservation_tables_no_overlap EXCLUDE USING gist (table_id WITH =, during WITH &&) WHERE (is_active)
+);
+
+CREATE INDEX idx_reservation_tables_reservation ON reservation_tables(reservation_id);
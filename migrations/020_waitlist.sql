@@ -0,0 +1,27 @@
+-- =====================================================
+-- WALK-IN WAITLIST
+-- =====================================================
+
+CREATE TABLE waitlist_entries (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    guest_name VARCHAR(100) NOT NULL,
+    guest_phone VARCHAR(20) NOT NULL,
+    party_size INT NOT NULL CHECK (party_size > 0),
+    quoted_wait_minutes INT NOT NULL CHECK (quoted_wait_minutes >= 0),
+    status VARCHAR(20) NOT NULL DEFAULT 'waiting' CHECK (status IN ('waiting', 'notified', 'seated', 'removed')),
+    -- lets the guest check their place without logging in
+    token VARCHAR(64) NOT NULL UNIQUE,
+    table_id INT REFERENCES tables(id),
+    notes TEXT,
+    notified_at TIMESTAMP,
+    seated_at TIMESTAMP,
+    removed_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_waitlist_entries_restaurant_status ON waitlist_entries(restaurant_id, status, created_at);
+
+-- the average dining time is read from recently completed orders
+CREATE INDEX IF NOT EXISTS idx_orders_completed_at ON orders(completed_at) WHERE completed_at IS NOT NULL;
@@ -0,0 +1,56 @@
+-- =====================================================
+-- MERGED TABLES
+-- =====================================================
+
+-- Tables pushed together for one party. The group shares the session of session_table_id:
+-- every order of the group is placed on that table, so it has one order stream and one bill.
+CREATE TABLE table_groups (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    session_table_id INT NOT NULL REFERENCES tables(id),
+    status VARCHAR(20) NOT NULL DEFAULT 'active' CHECK (status IN ('active', 'split')),
+    split_at TIMESTAMP,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE TABLE table_group_members (
+    id SERIAL PRIMARY KEY,
+    group_id INT NOT NULL REFERENCES table_groups(id) ON DELETE CASCADE,
+    table_id INT NOT NULL REFERENCES tables(id),
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_table_group_members_group ON table_group_members(group_id);
+
+-- a table is in one group at a time
+CREATE UNIQUE INDEX table_group_members_one_active ON table_group_members(table_id) WHERE is_active;
+
+-- The table an order was placed from, while it sits on the session table of a group. Splitting
+-- the group moves open orders back to it.
+ALTER TABLE orders ADD COLUMN source_table_id INT REFERENCES tables(id);
+
+-- Orders are created by the guest app from the QR of any table. An order for a merged table is
+-- placed on the session table of its group instead.
+CREATE OR REPLACE FUNCTION public.f_route_order_to_table_group() RETURNS trigger AS $$
+DECLARE
+    session_id INT;
+BEGIN
+    SELECT g.session_table_id INTO session_id
+    FROM table_group_members m
+    JOIN table_groups g ON g.id = m.group_id
+    WHERE m.table_id = NEW.table_id AND m.is_active AND g.status = 'active';
+
+    IF session_id IS NOT NULL AND session_id <> NEW.table_id THEN
+        NEW.source_table_id := NEW.table_id;
+        NEW.table_id := session_id;
+    END IF;
+
+    RETURN NEW;
+END;
+$$ LANGUAGE plpgsql;
+
+CREATE TRIGGER orders_route_to_table_group
+BEFORE INSERT ON orders
+FOR EACH ROW EXECUTE FUNCTION public.f_route_order_to_table_group();
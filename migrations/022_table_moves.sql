@@ -0,0 +1,21 @@
+-- =====================================================
+-- TABLE MOVES
+-- =====================================================
+
+-- History of guests moving tables mid-meal: which open orders went from one table to another
+CREATE TABLE table_moves (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    from_table_id INT NOT NULL REFERENCES tables(id),
+    to_table_id INT NOT NULL REFERENCES tables(id),
+    order_ids JSONB NOT NULL DEFAULT '[]',
+    -- the whole session moved, not only some orders
+    whole_session BOOLEAN NOT NULL,
+    -- the orders joined a session already open on the target table
+    merged BOOLEAN NOT NULL DEFAULT FALSE,
+    reason TEXT,
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_table_moves_from ON table_moves(from_table_id, created_at);
+CREATE INDEX idx_table_moves_to ON table_moves(to_table_id, created_at);
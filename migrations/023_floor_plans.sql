@@ -0,0 +1,49 @@
+-- =====================================================
+-- FLOOR PLANS
+-- =====================================================
+
+-- A floor of a restaurant, drawn on a width x height canvas. Units are up to the floor view.
+CREATE TABLE floor_plans (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    width NUMERIC(10,2) NOT NULL CHECK (width > 0),
+    height NUMERIC(10,2) NOT NULL CHECK (height > 0),
+    display_order INT NOT NULL DEFAULT 0,
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_floor_plans_restaurant ON floor_plans(restaurant_id, display_order);
+
+-- Named areas of a zone on the plan, e.g. "Window row" in "Main Hall". zone matches tables.location.
+CREATE TABLE floor_plan_areas (
+    id SERIAL PRIMARY KEY,
+    floor_plan_id INT NOT NULL REFERENCES floor_plans(id) ON DELETE CASCADE,
+    zone VARCHAR(100) NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    x NUMERIC(10,2) NOT NULL,
+    y NUMERIC(10,2) NOT NULL,
+    width NUMERIC(10,2) NOT NULL CHECK (width > 0),
+    height NUMERIC(10,2) NOT NULL CHECK (height > 0),
+    color VARCHAR(20),
+    created_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_floor_plan_areas_plan ON floor_plan_areas(floor_plan_id);
+
+-- Where a table sits. A table is on one plan at most.
+CREATE TABLE table_layouts (
+    table_id INT PRIMARY KEY REFERENCES tables(id) ON DELETE CASCADE,
+    floor_plan_id INT NOT NULL REFERENCES floor_plans(id) ON DELETE CASCADE,
+    x NUMERIC(10,2) NOT NULL,
+    y NUMERIC(10,2) NOT NULL,
+    width NUMERIC(10,2) NOT NULL CHECK (width > 0),
+    height NUMERIC(10,2) NOT NULL CHECK (height > 0),
+    -- degrees clockwise, around the center of the table
+    rotation NUMERIC(5,2) NOT NULL DEFAULT 0 CHECK (rotation >= 0 AND rotation < 360),
+    shape VARCHAR(20) NOT NULL DEFAULT 'rectangle' CHECK (shape IN ('rectangle', 'circle')),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+CREATE INDEX idx_table_layouts_plan ON table_layouts(floor_plan_id);
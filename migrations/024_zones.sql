@@ -0,0 +1,71 @@
+-- =====================================================
+-- ZONES
+-- =====================================================
+
+CREATE TABLE zones (
+    id SERIAL PRIMARY KEY,
+    restaurant_id INT NOT NULL,
+    name VARCHAR(100) NOT NULL,
+    display_order INT NOT NULL DEFAULT 0,
+    -- tables of an inactive zone are not offered for bookings or walk-ins
+    is_active BOOLEAN NOT NULL DEFAULT TRUE,
+    service_charge_percent NUMERIC(5,2) CHECK (service_charge_percent > 0 AND service_charge_percent <= 100),
+    minimum_spend NUMERIC(10,2) CHECK (minimum_spend > 0),
+    created_at TIMESTAMP DEFAULT NOW(),
+    updated_at TIMESTAMP DEFAULT NOW()
+);
+
+-- "Main Hall" and "main hall" are the same zone
+CREATE UNIQUE INDEX zones_restaurant_name_key ON zones(restaurant_id, LOWER(name));
+
+-- One zone per location in use, ignoring case and surrounding spaces. The spelling of the
+-- oldest table wins.
+INSERT INTO zones (restaurant_id, name)
+SELECT DISTINCT ON (COALESCE(restaurant_id, 1), LOWER(BTRIM(location))) COALESCE(restaurant_id, 1), BTRIM(location)
+FROM tables
+WHERE BTRIM(COALESCE(location, '')) <> ''
+ORDER BY COALESCE(restaurant_id, 1), LOWER(BTRIM(location)), id;
+
+INSERT INTO zones (restaurant_id, name)
+SELECT DISTINCT ON (p.restaurant_id, LOWER(BTRIM(a.zone))) p.restaurant_id, BTRIM(a.zone)
+FROM floor_plan_areas a
+JOIN floor_plans p ON p.id = a.floor_plan_id
+WHERE BTRIM(a.zone) <> ''
+ORDER BY p.restaurant_id, LOWER(BTRIM(a.zone)), a.id
+ON CONFLICT DO NOTHING;
+
+-- Tables point at their zone. location stays as a copy of the zone name for older clients;
+-- the API keeps it in sync.
+ALTER TABLE tables ADD COLUMN zone_id INT REFERENCES zones(id);
+
+UPDATE tables t
+SET zone_id = z.id, location = z.name
+FROM zones z
+WHERE z.restaurant_id = COALESCE(t.restaurant_id, 1) AND LOWER(z.name) = LOWER(BTRIM(t.location));
+
+CREATE INDEX idx_tables_zone ON tables(zone_id);
+
+-- Floor plan areas point at their zone instead of naming it
+ALTER TABLE floor_plan_areas ADD COLUMN zone_id INT REFERENCES zones(id) ON DELETE CASCADE;
+
+UPDATE floor_plan_areas a
+SET zone_id = z.id
+FROM floor_plans p, zones z
+WHERE p.id = a.floor_plan_id AND z.restaurant_id = p.restaurant_id AND LOWER(z.name) = LOWER(BTRIM(a.zone));
+
+-- Areas drawn without a zone go in an "Other" zone of their restaurant, so none is lost
+INSERT INTO zones (restaurant_id, name)
+SELECT DISTINCT p.restaurant_id, 'Other'
+FROM floor_plan_areas a
+JOIN floor_plans p ON p.id = a.floor_plan_id
+WHERE a.zone_id IS NULL
+ON CONFLICT DO NOTHING;
+
+UPDATE floor_plan_areas a
+SET zone_id = z.id
+FROM floor_plans p, zones z
+WHERE p.id = a.floor_plan_id AND z.restaurant_id = p.restaurant_id AND LOWER(z.name) = 'other'
+    AND a.zone_id IS NULL;
+
+ALTER TABLE floor_plan_areas ALTER COLUMN zone_id SET NOT NULL;
+ALTER TABLE floor_plan_areas DROP COLUMN zone;
@@ -0,0 +1,39 @@
+package prometheus
+
+import (
+	"context"
+
+	prm "github.com/prometheus/client_golang/prometheus"
+)
+
+const (
+	CacheResultHit   = "hit"
+	CacheResultMiss  = "miss"
+	CacheResultError = "error"
+)
+
+type CacheCounterMetricInterface interface {
+	Get(ctx context.Context) *prm.CounterVec
+	Add(ctx context.Context, cacheName string, store string, result string)
+}
+
+type cacheCounterMetric struct {
+	counterVec *prm.CounterVec
+}
+
+func NewCacheCounterMetric(name string) *cacheCounterMetric {
+	opts := prm.CounterOpts{
+		Name: name,
+		Help: "Cache lookups by cache, store and result (hit, miss, error)",
+	}
+	ct := prm.NewCounterVec(opts, []string{"cache", "store", "result"})
+	return &cacheCounterMetric{counterVec: ct}
+}
+
+func (c *cacheCounterMetric) Get(ctx context.Context) *prm.CounterVec {
+	return c.counterVec
+}
+
+func (c *cacheCounterMetric) Add(ctx context.Context, cacheName string, store string, result string) {
+	c.counterVec.WithLabelValues(cacheName, store, result).Inc()
+}
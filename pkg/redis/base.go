@@ -22,6 +22,7 @@ type ClientI interface {
 	Delete(ctx context.Context, keys ...string) error
 	Publish(ctx context.Context, channel string, message string) error
 	Subscribe(ctx context.Context, channel string) *redis.PubSub
+	Ping(ctx context.Context) error
 }
 
 type redisClient struct {
@@ -68,3 +69,7 @@ func (c *redisClient) Publish(ctx context.Context, channel string, message strin
 func (c *redisClient) Subscribe(ctx context.Context, channel string) *redis.PubSub {
 	return c.client.Subscribe(ctx, channel)
 }
+
+func (c *redisClient) Ping(ctx context.Context) error {
+	return c.client.Ping(ctx).Err()
+}
@@ -0,0 +1,225 @@
+package storage
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"io/fs"
+	"net/http"
+	"net/url"
+	"os"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// LocalStorage keeps objects as files under a root folder. The API serves the folder as static
+// files, so its urls are public like a public-read bucket.
+type LocalStorage struct {
+	root    string
+	baseURL string
+	secret  []byte
+}
+
+// NewLocalStorage refuses an empty secret: anyone could then sign uploads and overwrite any file
+func NewLocalStorage(root string, baseURL string, secret []byte) (*LocalStorage, error) {
+	if len(secret) == 0 {
+		return nil, ErrMissingSecret
+	}
+
+	if err := os.MkdirAll(root, 0o755); err != nil {
+		return nil, err
+	}
+
+	return &LocalStorage{
+		root:    root,
+		baseURL: strings.TrimSuffix(baseURL, "/"),
+		secret:  secret,
+	}, nil
+}
+
+// Root is the folder to serve as static files
+func (s *LocalStorage) Root() string {
+	return s.root
+}
+
+func (s *LocalStorage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(filepath.Dir(filename), 0o755); err != nil {
+		return err
+	}
+
+	// write next to the target and rename, so readers never see half a file
+	tmp, err := os.CreateTemp(filepath.Dir(filename), ".upload-*")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		return err
+	}
+	if err := os.Chmod(tmp.Name(), 0o644); err != nil {
+		return err
+	}
+
+	return os.Rename(tmp.Name(), filename)
+}
+
+func (s *LocalStorage) Get(ctx context.Context, key string) ([]byte, error) {
+	filename, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	return data, err
+}
+
+func (s *LocalStorage) Delete(ctx context.Context, key string) error {
+	filename, err := s.path(key)
+	if err != nil {
+		return err
+	}
+
+	err = os.Remove(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil
+	}
+	return err
+}
+
+// Stat has no stored content type to report, so it sniffs one from the first bytes of the file
+func (s *LocalStorage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	filename, err := s.path(key)
+	if err != nil {
+		return nil, err
+	}
+
+	file, err := os.Open(filename)
+	if errors.Is(err, fs.ErrNotExist) {
+		return nil, ErrObjectNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer file.Close()
+
+	info, err := file.Stat()
+	if err != nil {
+		return nil, err
+	}
+
+	head := make([]byte, 512)
+	n, err := io.ReadFull(file, head)
+	if err != nil && !errors.Is(err, io.ErrUnexpectedEOF) && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Size:        info.Size(),
+		ContentType: http.DetectContentType(head[:n]),
+	}, nil
+}
+
+// Sign adds an expiry and an HMAC of method, key, content type and expiry to the object url,
+// the way S3 signs a presigned request. The static route does not need it for GET; PUT requests
+// are checked by UploadHandler.
+func (s *LocalStorage) Sign(ctx context.Context, key string, opts SignOptions) (*SignedRequest, error) {
+	if _, err := s.path(key); err != nil {
+		return nil, err
+	}
+
+	expiresAt := strconv.FormatInt(time.Now().Add(opts.Expires).Unix(), 10)
+	query := url.Values{}
+	query.Set("expires", expiresAt)
+	query.Set("signature", s.sign(opts.Method, key, opts.ContentType, expiresAt))
+
+	headers := make(map[string]string)
+	if opts.ContentType != "" {
+		headers["Content-Type"] = opts.ContentType
+	}
+
+	return &SignedRequest{
+		Method:  opts.Method,
+		URL:     s.URL(key) + "?" + query.Encode(),
+		Headers: headers,
+	}, nil
+}
+
+// VerifySignature checks a url made by Sign against the request using it
+func (s *LocalStorage) VerifySignature(method string, key string, contentType string, expiresAt string, signature string) bool {
+	expires, err := strconv.ParseInt(expiresAt, 10, 64)
+	if err != nil || time.Now().Unix() > expires {
+		return false
+	}
+
+	return hmac.Equal([]byte(signature), []byte(s.sign(method, key, contentType, expiresAt)))
+}
+
+// UploadHandler accepts PUT requests to signed urls, standing in for the bucket. It expects the
+// route prefix to be stripped, so the path is the object key.
+func (s *LocalStorage) UploadHandler(maxSize int64) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		key := strings.TrimPrefix(r.URL.Path, "/")
+		query := r.URL.Query()
+		contentType := r.Header.Get("Content-Type")
+		if !s.VerifySignature(http.MethodPut, key, contentType, query.Get("expires"), query.Get("signature")) {
+			http.Error(w, "signature does not match", http.StatusForbidden)
+			return
+		}
+
+		data, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxSize))
+		if err != nil {
+			http.Error(w, err.Error(), http.StatusRequestEntityTooLarge)
+			return
+		}
+
+		if err := s.Put(r.Context(), key, data, contentType); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+		w.WriteHeader(http.StatusOK)
+	})
+}
+
+func (s *LocalStorage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *LocalStorage) Key(url string) (string, bool) {
+	return strings.CutPrefix(url, s.baseURL+"/")
+}
+
+func (s *LocalStorage) sign(method string, key string, contentType string, expiresAt string) string {
+	mac := hmac.New(sha256.New, s.secret)
+	mac.Write([]byte(fmt.Sprintf("%s\n%s\n%s\n%s", method, key, contentType, expiresAt)))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// path maps a key to its file, refusing keys that would leave the root
+func (s *LocalStorage) path(key string) (string, error) {
+	cleaned := path.Clean("/" + key)
+	if key == "" || cleaned == "/" || cleaned != "/"+key {
+		return "", ErrInvalidKey
+	}
+
+	return filepath.Join(s.root, filepath.FromSlash(cleaned)), nil
+}
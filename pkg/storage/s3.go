@@ -0,0 +1,182 @@
+package storage
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/aws/aws-sdk-go/aws"
+	"github.com/aws/aws-sdk-go/aws/awserr"
+	"github.com/aws/aws-sdk-go/aws/credentials"
+	"github.com/aws/aws-sdk-go/aws/request"
+	"github.com/aws/aws-sdk-go/aws/session"
+	"github.com/aws/aws-sdk-go/service/s3"
+)
+
+type S3Config struct {
+	AccessKey string
+	SecretKey string
+	Endpoint  string
+	Region    string
+	Bucket    string
+	ACL       string
+	// PublicURL overrides the default https://<bucket>.<endpoint>, e.g. for a CDN
+	PublicURL string
+}
+
+type s3Storage struct {
+	client  *s3.S3
+	bucket  string
+	acl     string
+	baseURL string
+	// bucketURL is where the bucket serves objects itself. Photos uploaded before a public url
+	// was set still point there.
+	bucketURL string
+}
+
+// NewS3Storage opens one client for the bucket, to be shared by every request
+func NewS3Storage(cfg S3Config) (Storage, error) {
+	newSession, err := session.NewSession(&aws.Config{
+		Credentials: credentials.NewStaticCredentials(cfg.AccessKey, cfg.SecretKey, ""),
+		Endpoint:    aws.String(cfg.Endpoint),
+		Region:      aws.String(cfg.Region),
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	bucketURL := fmt.Sprintf("https://%s.%s", cfg.Bucket, cfg.Endpoint)
+	baseURL := strings.TrimSuffix(cfg.PublicURL, "/")
+	if baseURL == "" {
+		baseURL = bucketURL
+	}
+
+	acl := cfg.ACL
+	if acl == "" {
+		acl = s3.ObjectCannedACLPublicRead
+	}
+
+	return &s3Storage{
+		client:    s3.New(newSession),
+		bucket:    cfg.Bucket,
+		acl:       acl,
+		baseURL:   baseURL,
+		bucketURL: bucketURL,
+	}, nil
+}
+
+func (s *s3Storage) Put(ctx context.Context, key string, data []byte, contentType string) error {
+	_, err := s.client.PutObjectWithContext(ctx, &s3.PutObjectInput{
+		Bucket:      aws.String(s.bucket),
+		Key:         aws.String(key),
+		Body:        bytes.NewReader(data),
+		ACL:         aws.String(s.acl),
+		ContentType: aws.String(contentType),
+	})
+	return err
+}
+
+func (s *s3Storage) Get(ctx context.Context, key string) ([]byte, error) {
+	output, err := s.client.GetObjectWithContext(ctx, &s3.GetObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+	defer output.Body.Close()
+
+	return io.ReadAll(output.Body)
+}
+
+func (s *s3Storage) Delete(ctx context.Context, key string) error {
+	_, err := s.client.DeleteObjectWithContext(ctx, &s3.DeleteObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	return err
+}
+
+func (s *s3Storage) Stat(ctx context.Context, key string) (*ObjectInfo, error) {
+	output, err := s.client.HeadObjectWithContext(ctx, &s3.HeadObjectInput{
+		Bucket: aws.String(s.bucket),
+		Key:    aws.String(key),
+	})
+	if err != nil {
+		if isNotFound(err) {
+			return nil, ErrObjectNotFound
+		}
+		return nil, err
+	}
+
+	return &ObjectInfo{
+		Size:        aws.Int64Value(output.ContentLength),
+		ContentType: aws.StringValue(output.ContentType),
+	}, nil
+}
+
+// Sign presigns the request. For PUT the content type and ACL are part of the signature, so an
+// upload sending other headers is refused by the bucket.
+func (s *s3Storage) Sign(ctx context.Context, key string, opts SignOptions) (*SignedRequest, error) {
+	var req *request.Request
+	switch opts.Method {
+	case http.MethodGet:
+		req, _ = s.client.GetObjectRequest(&s3.GetObjectInput{
+			Bucket: aws.String(s.bucket),
+			Key:    aws.String(key),
+		})
+	case http.MethodPut:
+		req, _ = s.client.PutObjectRequest(&s3.PutObjectInput{
+			Bucket:      aws.String(s.bucket),
+			Key:         aws.String(key),
+			ACL:         aws.String(s.acl),
+			ContentType: aws.String(opts.ContentType),
+		})
+	default:
+		return nil, fmt.Errorf("unsupported method %s", opts.Method)
+	}
+
+	req.SetContext(ctx)
+	signedURL, signedHeaders, err := req.PresignRequest(opts.Expires)
+	if err != nil {
+		return nil, err
+	}
+
+	headers := make(map[string]string, len(signedHeaders))
+	for name, values := range signedHeaders {
+		// the signer keeps names lowercase; Host is set by the client from the url
+		name = http.CanonicalHeaderKey(name)
+		if name != "Host" {
+			headers[name] = strings.Join(values, ",")
+		}
+	}
+
+	return &SignedRequest{Method: opts.Method, URL: signedURL, Headers: headers}, nil
+}
+
+func (s *s3Storage) URL(key string) string {
+	return s.baseURL + "/" + key
+}
+
+func (s *s3Storage) Key(url string) (string, bool) {
+	if key, ok := strings.CutPrefix(url, s.baseURL+"/"); ok {
+		return key, true
+	}
+	return strings.CutPrefix(url, s.bucketURL+"/")
+}
+
+// isNotFound matches both GET's NoSuchKey and HEAD's bare 404, which has no body to carry a code
+func isNotFound(err error) bool {
+	var awsErr awserr.RequestFailure
+	if errors.As(err, &awsErr) {
+		return awsErr.Code() == s3.ErrCodeNoSuchKey || awsErr.StatusCode() == http.StatusNotFound
+	}
+	return false
+}
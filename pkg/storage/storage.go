@@ -0,0 +1,56 @@
+// Package storage keeps uploaded files behind one interface so the API does not care whether they
+// live in an S3 compatible bucket (DigitalOcean Spaces) or on the local disk.
+package storage
+
+import (
+	"context"
+	"errors"
+	"time"
+)
+
+const (
+	DriverLocal = "local"
+	DriverS3    = "s3"
+)
+
+var (
+	ErrObjectNotFound = errors.New("object not found")
+	ErrInvalidKey     = errors.New("invalid object key")
+	ErrMissingSecret  = errors.New("local storage needs a signing secret")
+)
+
+// SignOptions describe the request a signed url allows
+type SignOptions struct {
+	Method string
+	// ContentType, for PUT, is the Content-Type header the upload has to send
+	ContentType string
+	Expires     time.Duration
+}
+
+// SignedRequest is what a client needs to make the request: the url and the headers it has to send
+type SignedRequest struct {
+	Method  string            `json:"method"`
+	URL     string            `json:"url"`
+	Headers map[string]string `json:"headers"`
+}
+
+type ObjectInfo struct {
+	Size        int64
+	ContentType string
+}
+
+type Storage interface {
+	// Put stores data under key, replacing what was there
+	Put(ctx context.Context, key string, data []byte, contentType string) error
+	Get(ctx context.Context, key string) ([]byte, error)
+	// Delete removes the object. Deleting a missing object is not an error.
+	Delete(ctx context.Context, key string) error
+	// Stat returns ErrObjectNotFound when there is no object under key
+	Stat(ctx context.Context, key string) (*ObjectInfo, error)
+	// Sign lets whoever holds the request GET or PUT the object until it expires
+	Sign(ctx context.Context, key string, opts SignOptions) (*SignedRequest, error)
+	// URL is the public url of the object
+	URL(key string) string
+	// Key finds the object key behind a public url, if the url belongs to this storage
+	Key(url string) (string, bool)
+}
@@ -0,0 +1,44 @@
+package redis
+
+import (
+	redis2 "app-noti/pkg/redis"
+	"context"
+)
+
+type MainRedis struct {
+	prefix string
+	client redis2.ClientI
+}
+
+func NewMainRedis(prefix string) *MainRedis {
+	return &MainRedis{prefix: prefix}
+}
+
+func (s *MainRedis) Run() error {
+	client := redis2.NewRedisClient()
+	if err := client.Ping(context.Background()); err != nil {
+		return err
+	}
+
+	s.client = client
+	return nil
+}
+
+func (s *MainRedis) Get() interface{} {
+	if s.client == nil {
+		return nil
+	}
+	return s.client
+}
+
+func (s *MainRedis) GetPrefix() string {
+	return s.prefix
+}
+
+func (s *MainRedis) Stop() <-chan bool {
+	stop := make(chan bool)
+	go func() {
+		stop <- true
+	}()
+	return stop
+}
@@ -3,8 +3,11 @@ package rest_api_service
 import (
 	"app-noti/common"
 	"app-noti/internal/handlers"
+	"app-noti/internal/services"
 	"app-noti/middleware"
+	"app-noti/pkg/storage"
 	"app-noti/server"
+	"net/http"
 	"os"
 
 	"github.com/gin-contrib/requestid"
@@ -12,6 +15,7 @@ import (
 	"github.com/gin-contrib/cors"
 	_ "github.com/gin-contrib/cors"
 	"github.com/gin-gonic/gin"
+	"github.com/prometheus/client_golang/prometheus/promhttp"
 )
 
 func RestHandler(sc server.ServerContext) func() *gin.Engine {
@@ -40,8 +44,23 @@ func RestHandler(sc server.ServerContext) func() *gin.Engine {
 			health.GET("/status", handlers.Check(sc))
 		}
 
+		router.GET("/metrics", gin.WrapH(promhttp.Handler()))
+
+		// files kept on local disk are served the way a public bucket would serve them, and
+		// presigned uploads are PUT to the same urls
+		if local, ok := sc.GetService(common.PREFIX_MAIN_STORAGE).(*storage.LocalStorage); ok {
+			router.Static(common.LOCAL_STORAGE_ROUTE, local.Root())
+			upload := http.StripPrefix(common.LOCAL_STORAGE_ROUTE, local.UploadHandler(services.MaxUploadSize()))
+			router.PUT(common.LOCAL_STORAGE_ROUTE+"/*filepath", gin.WrapH(upload))
+		}
+
 		// Handler
-		handler := handlers.NewHandler(sc)
+		handler, err := handlers.NewHandler(sc)
+		if err != nil {
+			// the server is already stopping with the error of the service that failed
+			sc.GetLogger().Error().Println("NewHandler", err)
+			return router
+		}
 		handler.RegisterRouter(router)
 
 		return router
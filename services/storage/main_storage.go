@@ -0,0 +1,97 @@
+package storage
+
+import (
+	"app-noti/common"
+	"app-noti/config"
+	"app-noti/pkg/storage"
+	"fmt"
+)
+
+// defaultBucket is the bucket photos were uploaded to before the bucket could be configured
+const defaultBucket = "smart-restaurant"
+
+// MainStorage opens the object storage chosen by config once, for every request to share
+type MainStorage struct {
+	prefix  string
+	storage storage.Storage
+}
+
+func NewMainStorage(prefix string) *MainStorage {
+	return &MainStorage{prefix: prefix}
+}
+
+func (s *MainStorage) Run() error {
+	cfg := config.Config.Storage
+	switch driver() {
+	case storage.DriverS3:
+		client, err := storage.NewS3Storage(storage.S3Config{
+			AccessKey: config.Config.DigitalOcean.StorageAccessKey,
+			SecretKey: config.Config.DigitalOcean.StorageSecretKey,
+			Endpoint:  config.Config.DigitalOcean.StorageEndPoint,
+			Region:    config.Config.DigitalOcean.StorageRegion,
+			Bucket:    bucket(),
+			ACL:       config.Config.DigitalOcean.StorageAcl,
+			PublicURL: cfg.PublicURL,
+		})
+		if err != nil {
+			return err
+		}
+		s.storage = client
+	case storage.DriverLocal:
+		publicURL := cfg.PublicURL
+		if publicURL == "" {
+			publicURL = "http://localhost:8080" + common.LOCAL_STORAGE_ROUTE
+		}
+		client, err := storage.NewLocalStorage(cfg.LocalRoot, publicURL, []byte(cfg.SigningSecret))
+		if err != nil {
+			return err
+		}
+		s.storage = client
+	default:
+		return fmt.Errorf("unknown storage driver %q", cfg.Driver)
+	}
+
+	return nil
+}
+
+// driver is the configured driver. Without one, deployments that only set the DigitalOcean
+// credentials keep uploading to Spaces as before, and local runs use the disk.
+func driver() string {
+	if config.Config.Storage.Driver != "" {
+		return config.Config.Storage.Driver
+	}
+	if config.Config.DigitalOcean.StorageAccessKey != "" {
+		return storage.DriverS3
+	}
+	return storage.DriverLocal
+}
+
+// bucket falls back to the DigitalOcean setting, then to the default bucket
+func bucket() string {
+	if config.Config.Storage.Bucket != "" {
+		return config.Config.Storage.Bucket
+	}
+	if config.Config.DigitalOcean.StorageBucket != "" {
+		return config.Config.DigitalOcean.StorageBucket
+	}
+	return defaultBucket
+}
+
+func (s *MainStorage) Get() interface{} {
+	if s.storage == nil {
+		return nil
+	}
+	return s.storage
+}
+
+func (s *MainStorage) GetPrefix() string {
+	return s.prefix
+}
+
+func (s *MainStorage) Stop() <-chan bool {
+	stop := make(chan bool)
+	go func() {
+		stop <- true
+	}()
+	return stop
+}